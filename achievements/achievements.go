@@ -0,0 +1,88 @@
+package achievements
+
+//
+// The "achievements" package keeps the unlocked achievements in a JSON file.
+// The file is optional, it is created when the first achievement is unlocked.
+//
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type Store struct {
+	// The file that the store was loaded from. It is also the destination of saving.
+	filePath string
+	// The times when the achievements were unlocked, by the names of the achievements.
+	Unlocked map[string]time.Time `json:"unlocked"`
+}
+
+func (store *Store) IsUnlocked(name string) bool {
+	_, ok := store.Unlocked[name]
+	return ok
+}
+
+// Returns whether it is unlocked for the first time.
+func (store *Store) Unlock(name string, now time.Time) bool {
+	if store.IsUnlocked(name) {
+		return false
+	}
+	store.Unlocked[name] = now
+	return true
+}
+
+// Returns "$XDG_CONFIG_HOME/tower-of-go/achievements.json" or the equivalent of the OS.
+func GetDefaultStoreFilePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return filepath.Join(configDir, "tower-of-go", "achievements.json"), nil
+}
+
+// A store that is not saved to a file.
+func CreateStore() *Store {
+	return &Store{Unlocked: make(map[string]time.Time)}
+}
+
+// Returns an empty store if the file does not exist.
+func LoadStore(filePath string) (*Store, error) {
+	store := CreateStore()
+	store.filePath = filePath
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return store, nil
+	} else if err != nil {
+		return store, errors.WithStack(err)
+	}
+	if err := json.Unmarshal(content, store); err != nil {
+		return store, errors.Wrapf(err, "The achievement file (%s) is invalid.", filePath)
+	}
+	if store.Unlocked == nil {
+		store.Unlocked = make(map[string]time.Time)
+	}
+	return store, nil
+}
+
+// Write the store to the file that it was loaded from.
+// A store that was not loaded from a file is not saved.
+func SaveStore(store *Store) error {
+	if store.filePath == "" {
+		return nil
+	}
+	content, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(store.filePath), 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := ioutil.WriteFile(store.filePath, append(content, '\n'), 0644); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package achievements
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore_Unlock_NotTD(t *testing.T) {
+	t.Run("初めて解除したときだけtrueを返し、最初の時刻を保持する", func(t *testing.T) {
+		store := CreateStore()
+		firstTime := time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+		if !store.Unlock("firstFloor", firstTime) {
+			t.Fatal("解除しない")
+		} else if store.Unlock("firstFloor", firstTime.Add(time.Hour)) {
+			t.Fatal("再び解除する")
+		} else if !store.IsUnlocked("firstFloor") || !store.Unlocked["firstFloor"].Equal(firstTime) {
+			t.Fatal("時刻が違う")
+		} else if store.IsUnlocked("floor10") {
+			t.Fatal("他の実績も解除している")
+		}
+	})
+}
+
+func TestLoadStore_NotTD(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "tower-of-go")
+	defer os.RemoveAll(dir)
+
+	t.Run("ファイルが存在しないとき、空の保管庫を返す", func(t *testing.T) {
+		store, err := LoadStore(filepath.Join(dir, "missing.json"))
+		if err != nil {
+			t.Fatal(err)
+		} else if len(store.Unlocked) != 0 {
+			t.Fatal("空ではない")
+		}
+	})
+
+	t.Run("保存した実績を読み込む", func(t *testing.T) {
+		filePath := filepath.Join(dir, "achievements.json")
+		store, _ := LoadStore(filePath)
+		store.Unlock("optimalPath", time.Now())
+		if err := SaveStore(store); err != nil {
+			t.Fatal(err)
+		}
+		loadedStore, err := LoadStore(filePath)
+		if err != nil {
+			t.Fatal(err)
+		} else if !loadedStore.IsUnlocked("optimalPath") {
+			t.Fatal("実績を読み込んでいない")
+		}
+	})
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/kjirou/tower-of-go/controller"
+	"github.com/kjirou/tower-of-go/i18n"
+	"github.com/kjirou/tower-of-go/levels"
+	"github.com/kjirou/tower-of-go/mazes"
+	"github.com/kjirou/tower-of-go/stats"
+	"github.com/kjirou/tower-of-go/utils"
+	"github.com/pkg/errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const mazeCommandUsage = `Usage: tower-of-go maze <command> [flags]
+
+Commands:
+  export  Write a maze as JSON, SVG or PNG.
+  stats   Analyze the mazes of many seeds per algorithm.`
+
+// Define the flags that decide a generated maze.
+func defineMazeGenerationFlags(flagSet *flag.FlagSet) *mazes.GenerationOptions {
+	options := &mazes.GenerationOptions{}
+	flagSet.IntVar(&options.RowLength, "rows", 13, "Number of rows, an odd number.")
+	flagSet.IntVar(&options.ColumnLength, "columns", 21, "Number of columns, an odd number.")
+	flagSet.StringVar(&options.Algorithm, "algorithm", "clustering", "Generation algorithm, \"clustering\" or \"digging\".")
+	flagSet.Float64Var(&options.LoopDensity, "loop", 0, "Rate of the walls broken to make loops, from 0 to 1.")
+	flagSet.IntVar(&options.MinSolutionLength, "min-solution", 0, "Minimum steps from the start to the goal. Mazes are generated again until one fits.")
+	flagSet.IntVar(&options.MaxSolutionLength, "max-solution", 0, "Maximum steps from the start to the goal. Mazes are generated again until one fits.")
+	flagSet.Int64Var(&options.Seed, "seed", 0, "Seed of the generation. Defaults to the current time.")
+	return options
+}
+
+func isFlagPassed(flagSet *flag.FlagSet, name string) bool {
+	isPassed := false
+	flagSet.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			isPassed = true
+		}
+	})
+	return isPassed
+}
+
+func runMazeExportCommand(args []string) error {
+	flagSet := flag.NewFlagSet("maze export", flag.ContinueOnError)
+	generationOptions := defineMazeGenerationFlags(flagSet)
+	var format string
+	flagSet.StringVar(&format, "format", "",
+		"Output format, \"" + strings.Join(mazes.ExportFormats, "\", \"") + "\". Defaults to the extension of -output, or json.")
+	var cellSize int
+	flagSet.IntVar(&cellSize, "cell", mazes.DefaultCellSize, "Pixels of a side of a cell in SVG and PNG.")
+	var outputFilePath string
+	flagSet.StringVar(&outputFilePath, "output", "", "Path to the output file. Defaults to the standard output.")
+	var mapFilePath string
+	flagSet.StringVar(&mapFilePath, "map", "", "Path to a map file to export instead of a generated maze.")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	if !isFlagPassed(flagSet, "seed") {
+		generationOptions.Seed = time.Now().UnixNano()
+	}
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(outputFilePath), ".")
+		if format == "" {
+			format = "json"
+		}
+	}
+	var maze *mazes.Maze
+	if mapFilePath != "" {
+		level, err := levels.LoadLevel(mapFilePath)
+		if err != nil {
+			return err
+		}
+		maze = mazes.CreateMazeFromField(level.Field)
+	} else {
+		generatedMaze, err := mazes.GenerateMaze(generationOptions)
+		if err != nil {
+			return err
+		}
+		maze = generatedMaze
+	}
+
+	var writer io.Writer = os.Stdout
+	if outputFilePath != "" {
+		file, err := os.Create(outputFilePath)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		defer file.Close()
+		writer = file
+	}
+	return mazes.Export(writer, maze, format, cellSize)
+}
+
+// The most frequent corridor lengths are listed, the others are put together.
+const maxListedCorridorLengths = 8
+
+func formatCorridorLengths(corridorLengths map[int]int) string {
+	total := 0
+	steps := make([]int, 0)
+	for step, count := range corridorLengths {
+		total += count
+		steps = append(steps, step)
+	}
+	if total == 0 {
+		return "-"
+	}
+	sort.Ints(steps)
+	parts := make([]string, 0)
+	others := 0
+	for i, step := range steps {
+		if i < maxListedCorridorLengths {
+			parts = append(parts, fmt.Sprintf("%d:%.0f%%", step, float64(corridorLengths[step]) / float64(total) * 100))
+		} else {
+			others += corridorLengths[step]
+		}
+	}
+	if others > 0 {
+		parts = append(parts, fmt.Sprintf(">%d:%.0f%%", steps[maxListedCorridorLengths - 1], float64(others) / float64(total) * 100))
+	}
+	return strings.Join(parts, " ")
+}
+
+func writeMazeStatsSummaries(writer io.Writer, summaries []*mazes.MazeStatsSummary) error {
+	tabWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	rows := []struct {
+		label string
+		format func(summary *mazes.MazeStatsSummary) string
+	}{
+		{"Algorithm", func(summary *mazes.MazeStatsSummary) string { return summary.Options.Algorithm }},
+		{"Size", func(summary *mazes.MazeStatsSummary) string {
+			return fmt.Sprintf("%dx%d", summary.Options.RowLength, summary.Options.ColumnLength)
+		}},
+		{"Loop density", func(summary *mazes.MazeStatsSummary) string { return fmt.Sprintf("%g", summary.Options.LoopDensity) }},
+		{"Seeds", func(summary *mazes.MazeStatsSummary) string {
+			return fmt.Sprintf("%d-%d", summary.Options.Seed, summary.Options.Seed + int64(summary.SampleCount) - 1)
+		}},
+		{"Dead ends", func(summary *mazes.MazeStatsSummary) string { return fmt.Sprintf("%.1f", summary.DeadEndCount) }},
+		{"Junctions", func(summary *mazes.MazeStatsSummary) string { return fmt.Sprintf("%.1f", summary.JunctionCount) }},
+		{"Solution length", func(summary *mazes.MazeStatsSummary) string {
+			return fmt.Sprintf("%.1f", summary.SolutionLength)
+		}},
+		{"Decisions", func(summary *mazes.MazeStatsSummary) string { return fmt.Sprintf("%.1f", summary.DecisionCount) }},
+		{"River factor", func(summary *mazes.MazeStatsSummary) string { return fmt.Sprintf("%.2f", summary.RiverFactor) }},
+		{"Difficulty rating", func(summary *mazes.MazeStatsSummary) string {
+			return fmt.Sprintf("%.2f", summary.DifficultyRating)
+		}},
+	}
+	for _, row := range rows {
+		fmt.Fprint(tabWriter, row.label)
+		for _, summary := range summaries {
+			fmt.Fprint(tabWriter, "\t" + row.format(summary))
+		}
+		fmt.Fprintln(tabWriter)
+	}
+	if err := tabWriter.Flush(); err != nil {
+		return errors.WithStack(err)
+	}
+	fmt.Fprintln(writer, "\nCorridor lengths (steps:share)")
+	for _, summary := range summaries {
+		fmt.Fprintf(writer, "  %s: %s\n", summary.Options.Algorithm, formatCorridorLengths(summary.CorridorLengths))
+	}
+	return nil
+}
+
+func runMazeStatsCommand(args []string) error {
+	flagSet := flag.NewFlagSet("maze stats", flag.ContinueOnError)
+	generationOptions := defineMazeGenerationFlags(flagSet)
+	var sampleCount int
+	flagSet.IntVar(&sampleCount, "count", 100, "Number of seeds per algorithm, from -seed in order.")
+	var outputsJSON bool
+	flagSet.BoolVar(&outputsJSON, "json", false, "Outputs JSON instead of a table.")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	if !isFlagPassed(flagSet, "seed") {
+		generationOptions.Seed = time.Now().UnixNano()
+	}
+	// All algorithms are compared unless they are specified.
+	algorithmNames := utils.MazeAlgorithmNames
+	if isFlagPassed(flagSet, "algorithm") {
+		algorithmNames = strings.Split(generationOptions.Algorithm, ",")
+	}
+	summaries := make([]*mazes.MazeStatsSummary, 0)
+	for _, algorithmName := range algorithmNames {
+		algorithmOptions := *generationOptions
+		algorithmOptions.Algorithm = strings.TrimSpace(algorithmName)
+		summary, err := mazes.SummarizeGeneratedMazes(&algorithmOptions, sampleCount)
+		if err != nil {
+			return err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if outputsJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return errors.WithStack(encoder.Encode(summaries))
+	}
+	return writeMazeStatsSummaries(os.Stdout, summaries)
+}
+
+// The subcommands about mazes, such as "maze export".
+func runMazeCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New(mazeCommandUsage)
+	}
+	switch args[0] {
+	case "export":
+		return runMazeExportCommand(args[1:])
+	case "stats":
+		return runMazeStatsCommand(args[1:])
+	}
+	return errors.Errorf("The command \"%s\" does not exist.\n%s", args[0], mazeCommandUsage)
+}
+
+// Print the charts of the history of the games, the same as the stats scene.
+func runStatsCommand(args []string) error {
+	flagSet := flag.NewFlagSet("stats", flag.ContinueOnError)
+	var historyFilePath string
+	flagSet.StringVar(&historyFilePath, "history", "", "Path to the history file. Defaults to \"tower-of-go/history.json\" in the user config directory.")
+	var language string
+	flagSet.StringVar(&language, "lang", "", "Language, \"en\" or \"ja\". Defaults to the locale.")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	if historyFilePath == "" {
+		defaultHistoryFilePath, err := stats.GetDefaultStoreFilePath()
+		if err != nil {
+			return err
+		}
+		historyFilePath = defaultHistoryFilePath
+	}
+	store, err := stats.LoadStore(historyFilePath)
+	if err != nil {
+		return err
+	}
+	return controller.WriteStats(os.Stdout, store, i18n.CreateTranslator(i18n.DetectLanguage(language)))
+}
+
+// Returns false if the arguments are not a subcommand, then the game runs.
+func runSubcommand(args []string) (bool, error) {
+	if len(args) > 0 {
+		switch args[0] {
+		case "maze":
+			return true, runMazeCommand(args[1:])
+		case "stats":
+			return true, runStatsCommand(args[1:])
+		}
+	}
+	return false, nil
+}
+
+func exitWithError(err error) {
+	if err != flag.ErrHelp {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	os.Exit(1)
+}
@@ -0,0 +1,136 @@
+package config
+
+//
+// The "config" package loads the user's preferences from a JSON file.
+// The file is optional, every setting has a default value.
+//
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// The appearance of a kind of tile on the field. Empty values are inherited from the base theme.
+type ThemeTileConfig struct {
+	Glyph string `json:"glyph"`
+	Foreground string `json:"foreground"`
+	Background string `json:"background"`
+}
+
+// A user-defined theme. See the "themes" package for the names of tiles and colors.
+type ThemeConfig struct {
+	// The name of the theme to inherit from. An empty value means "default".
+	Base string `json:"base"`
+	Tiles map[string]*ThemeTileConfig `json:"tiles"`
+	Colors map[string]string `json:"colors"`
+}
+
+// A user-defined difficulty, it is used as "custom". Zero values are inherited from "normal".
+// See the "models" package for the meanings of the settings.
+type DifficultyConfig struct {
+	RowLength int `json:"rowLength"`
+	ColumnLength int `json:"columnLength"`
+	// "clustering" or "digging".
+	Algorithm string `json:"algorithm"`
+	LoopDensity float64 `json:"loopDensity"`
+	TimeLimitRate float64 `json:"timeLimitRate"`
+	GrowthInterval int `json:"growthInterval"`
+	MaxRowLength int `json:"maxRowLength"`
+	MaxColumnLength int `json:"maxColumnLength"`
+	LoopDensityDelta float64 `json:"loopDensityDelta"`
+	// The band of the solution length, as the ratio to the steps on the field without walls.
+	MinSolutionRatio float64 `json:"minSolutionRatio"`
+	MaxSolutionRatio float64 `json:"maxSolutionRatio"`
+}
+
+// A rank of a rank table.
+type RankConfig struct {
+	// The minimum score, or the maximum seconds in modes that are scored by time.
+	Threshold float64 `json:"threshold"`
+	Title string `json:"title"`
+	// A color name of the theme such as "rank.good", or a color spec such as "208+bold".
+	// An empty value means "rank.normal".
+	Color string `json:"color"`
+}
+
+// A user-defined rank table. The ranks are in order from the best.
+type RankTableConfig struct {
+	// The thresholds are calibrated from the autoplay.
+	// The ranks are used only for the titles and colors, the built-in ones are used if they are empty.
+	Auto bool `json:"auto"`
+	Ranks []*RankConfig `json:"ranks"`
+	// The rank of scores that do not reach any threshold.
+	Lowest *RankConfig `json:"lowest"`
+}
+
+type Config struct {
+	// The file that the config was loaded from. It is also the destination of saving.
+	filePath string
+	// A language code such as "en" or "ja". An empty value means that it is detected from the locale.
+	Language string `json:"language"`
+	// The name of a built-in theme or a theme in Themes. An empty value means "default".
+	Theme string `json:"theme"`
+	Themes map[string]*ThemeConfig `json:"themes"`
+	// "basic", "256" or "auto". An empty value means "auto".
+	ColorMode string `json:"colorMode"`
+	// "easy", "normal", "hard" or "custom". An empty value means "normal".
+	Difficulty string `json:"difficulty"`
+	CustomDifficulty *DifficultyConfig `json:"customDifficulty"`
+	// The keys are "<mode>/<difficulty>" or "<mode>", the former has priority.
+	RankTables map[string]*RankTableConfig `json:"rankTables"`
+	// The cells that the hero has visited in the floor are marked during the games.
+	ShowsTrail bool `json:"trail"`
+	// The visited corridors that lead to nothing but dead ends are shaded during the games.
+	ShadesDeadEnds bool `json:"deadEnds"`
+}
+
+// Returns "$XDG_CONFIG_HOME/tower-of-go/config.json" or the equivalent of the OS.
+func GetDefaultConfigFilePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return filepath.Join(configDir, "tower-of-go", "config.json"), nil
+}
+
+func CreateDefaultConfig() *Config {
+	return &Config{}
+}
+
+// Returns the default config if the file does not exist.
+func LoadConfig(filePath string) (*Config, error) {
+	cfg := CreateDefaultConfig()
+	cfg.filePath = filePath
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	} else if err != nil {
+		return cfg, errors.WithStack(err)
+	}
+	if err := json.Unmarshal(content, cfg); err != nil {
+		return cfg, errors.Wrapf(err, "The config file (%s) is invalid.", filePath)
+	}
+	return cfg, nil
+}
+
+// Write the config to the file that it was loaded from.
+// A config that was not loaded from a file is not saved.
+func SaveConfig(cfg *Config) error {
+	if cfg.filePath == "" {
+		return nil
+	}
+	content, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.filePath), 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := ioutil.WriteFile(cfg.filePath, append(content, '\n'), 0644); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig_NotTD(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "tower-of-go")
+	defer os.RemoveAll(dir)
+
+	t.Run("ファイルが存在しないとき、既定の設定を返す", func(t *testing.T) {
+		cfg, err := LoadConfig(filepath.Join(dir, "missing.json"))
+		if err != nil {
+			t.Fatal("エラーを返す")
+		} else if cfg.Language != "" {
+			t.Fatal("既定の設定ではない")
+		}
+	})
+
+	t.Run("ファイルの設定を読み込む", func(t *testing.T) {
+		filePath := filepath.Join(dir, "config.json")
+		ioutil.WriteFile(filePath, []byte(`{"language": "ja"}`), 0644)
+		cfg, err := LoadConfig(filePath)
+		if err != nil {
+			t.Fatal("エラーを返す")
+		} else if cfg.Language != "ja" {
+			t.Fatal("設定を読み込んでいない")
+		}
+	})
+
+	t.Run("ファイルが不正なとき、エラーを返す", func(t *testing.T) {
+		filePath := filepath.Join(dir, "invalid.json")
+		ioutil.WriteFile(filePath, []byte(`{`), 0644)
+		_, err := LoadConfig(filePath)
+		if err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+}
@@ -18,46 +18,63 @@ package controller
 //
 
 import (
+	"fmt"
+	"github.com/kjirou/tower-of-go/achievements"
+	"github.com/kjirou/tower-of-go/config"
+	"github.com/kjirou/tower-of-go/highscores"
+	"github.com/kjirou/tower-of-go/i18n"
 	"github.com/kjirou/tower-of-go/models"
 	"github.com/kjirou/tower-of-go/utils"
 	"github.com/kjirou/tower-of-go/reducers"
+	"github.com/kjirou/tower-of-go/replays"
+	"github.com/kjirou/tower-of-go/saves"
+	"github.com/kjirou/tower-of-go/stats"
+	"github.com/kjirou/tower-of-go/themes"
 	"github.com/kjirou/tower-of-go/views"
 	"github.com/nsf/termbox-go"
+	"github.com/pkg/errors"
+	"strings"
 	"time"
 )
 
-func mapFieldElementToScreenCellProps(fieldElement *models.FieldElement) *views.ScreenCellProps {
-	symbol := '.'
-	fg := termbox.ColorWhite
-	bg := termbox.ColorBlack
+// The time that the unlocked achievement is shown, in the execution time.
+const achievementNoticeDuration = 3 * time.Second
+
+func mapTileNameToScreenCellProps(tileName string, theme *themes.Theme) *views.ScreenCellProps {
+	tile := theme.GetTile(tileName)
+	return &views.ScreenCellProps{
+		Symbol: tile.Symbol,
+		Foreground: tile.Foreground,
+		Background: tile.Background,
+	}
+}
+
+func mapFieldElementToScreenCellProps(fieldElement *models.FieldElement, theme *themes.Theme) *views.ScreenCellProps {
+	tileName := "floor"
 	if !fieldElement.IsObjectEmpty() {
 		switch fieldElement.GetObjectClass() {
 		case "hero":
-			symbol = '@'
-			fg = termbox.ColorMagenta
+			tileName = "hero"
 		case "wall":
-			symbol = '#'
-			fg = termbox.ColorYellow
+			tileName = "wall"
 		default:
-			symbol = '?'
+			tileName = "unknown"
 		}
 	} else {
 		switch fieldElement.GetFloorObjectClass() {
 		case "upstairs":
-			symbol = '<'
-			fg = termbox.ColorGreen
+			tileName = "upstairs"
 		}
 	}
-	return &views.ScreenCellProps{
-		Symbol: symbol,
-		Foreground: fg,
-		Background: bg,
-	}
+	return mapTileNameToScreenCellProps(tileName, theme)
 }
 
-func mapStateModelToScreenProps(state *models.State) *views.ScreenProps {
+func mapStateModelToGameSceneProps(
+	state *models.State, translator *i18n.Translator, theme *themes.Theme,
+	showsTrail bool, shadesDeadEnds bool) *views.GameSceneProps {
 	game := state.GetGame()
 	field := state.GetField()
+	mode := game.GetMode()
 
 	// Cells of the field.
 	fieldRowLength := field.MeasureRowLength()
@@ -67,55 +84,574 @@ func mapStateModelToScreenProps(state *models.State) *views.ScreenProps {
 		cellsRow := make([]*views.ScreenCellProps, fieldColumnLength)
 		for x := 0; x < fieldColumnLength; x++ {
 			fieldElement, _ := field.At(&utils.MatrixPosition{Y: y, X: x})
-			cellsRow[x] = mapFieldElementToScreenCellProps(fieldElement)
+			cellsRow[x] = mapFieldElementToScreenCellProps(fieldElement, theme)
 		}
 		fieldCells[y] = cellsRow
 	}
 
-	// Lank message.
-	lankMessage := ""
-	lankMessageForeground := termbox.ColorWhite
-	if game.IsFinished() {
-		score := game.GetFloorNumber()
-		switch {
-			case score == 3:
-				lankMessage = "Good!"
-				lankMessageForeground = termbox.ColorGreen
-			case score == 4:
-				lankMessage = "Excellent!"
-				lankMessageForeground = termbox.ColorGreen
-			case score == 5:
-				lankMessage = "Marvelous!"
-				lankMessageForeground = termbox.ColorGreen
-			case score >= 6:
-				lankMessage = "Gopher!!"
-				lankMessageForeground = termbox.ColorCyan
-			default:
-				lankMessage = "No good..."
-		}
-	}
-
-	return &views.ScreenProps{
+	// The trail is drawn on the empty cells, so the hero, the walls and the upstairs are not hidden.
+	if showsTrail && game.IsStarted() {
+		trailTile := theme.GetTile("trail")
+		for _, position := range game.ListVisitedPositions(game.GetFloorNumber()) {
+			fieldElement, err := field.At(position)
+			if err == nil && fieldElement.IsObjectEmpty() && fieldElement.GetFloorObjectClass() != "upstairs" {
+				fieldCells[position.Y][position.X] = &views.ScreenCellProps{
+					Symbol: trailTile.Symbol,
+					Foreground: trailTile.Foreground,
+					Background: trailTile.Background,
+				}
+			}
+		}
+	}
+
+	// The dead ends are drawn over the trail, because all of them have been visited.
+	if shadesDeadEnds && game.IsStarted() {
+		deadEndTile := theme.GetTile("deadEnd")
+		visitCounts := models.CountVisits(game.ListVisitedPositions(game.GetFloorNumber()))
+		for position := range field.FindDeadEnds(visitCounts) {
+			fieldElement, err := field.At(&position)
+			if err == nil && fieldElement.IsObjectEmpty() {
+				fieldCells[position.Y][position.X] = &views.ScreenCellProps{
+					Symbol: deadEndTile.Symbol,
+					Foreground: deadEndTile.Foreground,
+					Background: deadEndTile.Background,
+				}
+			}
+		}
+	}
+
+	// The breadcrumbs are the marks of the player, so they are drawn over the assists.
+	breadcrumbTile := theme.GetTile("breadcrumb")
+	for y := 0; y < fieldRowLength; y++ {
+		for x := 0; x < fieldColumnLength; x++ {
+			fieldElement, _ := field.At(&utils.MatrixPosition{Y: y, X: x})
+			if fieldElement.GetOverlayClass() == "breadcrumb" && fieldElement.IsObjectEmpty() {
+				fieldCells[y][x] = &views.ScreenCellProps{
+					Symbol: breadcrumbTile.Symbol,
+					Foreground: breadcrumbTile.Foreground,
+					Background: breadcrumbTile.Background,
+				}
+			}
+		}
+	}
+
+	// The ghost is seen through, it is drawn on the background of the cell and under the hero.
+	ghostFloorNumber := 0
+	if ghostStep := game.FindGhostStep(state.GetExecutionTime()); ghostStep != nil {
+		ghostFloorNumber = ghostStep.FloorNumber
+		ghostPosition := &utils.MatrixPosition{Y: ghostStep.Y, X: ghostStep.X}
+		ghostFieldElement, err := field.At(ghostPosition)
+		if ghostStep.FloorNumber == game.GetFloorNumber() && err == nil && ghostFieldElement.IsObjectEmpty() {
+			ghostTile := theme.GetTile("ghost")
+			fieldCells[ghostStep.Y][ghostStep.X] = &views.ScreenCellProps{
+				Symbol: ghostTile.Symbol,
+				Foreground: ghostTile.Foreground,
+				Background: fieldCells[ghostStep.Y][ghostStep.X].Background,
+			}
+		}
+	}
+
+	// The rule of the mode.
+	var rule string
+	switch {
+	case mode.GetScoreUnit() == models.ScoreUnitSeconds:
+		rule = translator.Translate("description." + mode.GetName(), mode.GetGoalFloorNumber())
+	// The modes without a timer have nothing to fill in.
+	case !game.HasTimeLimit():
+		rule = translator.Translate("description." + mode.GetName())
+	default:
+		rule = translator.Translate("description." + mode.GetName(), int(game.CalculateTimeLimit(1).Seconds()))
+	}
+
+	_, isRewindable := mode.(models.RewindableMode)
+
+	goal := translator.Translate("description.goal")
+	if _, ok := mode.(models.FloorProvider); ok {
+		goal = translator.Translate("description.goalOfTower")
+	}
+
+	return &views.GameSceneProps{
 		FieldCells: fieldCells,
+		HasTimeLimit: game.HasTimeLimit(),
 		RemainingTime: game.CalculateRemainingTime(state.GetExecutionTime()).Seconds(),
+		ElapsedTime: game.CalculatePlaytime(state.GetExecutionTime()).Seconds(),
 		FloorNumber: game.GetFloorNumber(),
-		LankMessage: lankMessage,
-		LankMessageForeground: lankMessageForeground,
+		GoalFloorNumber: mode.GetGoalFloorNumber(),
+		GhostFloorNumber: ghostFloorNumber,
+		IsRewindable: isRewindable,
+		IsPaused: state.IsPaused(),
+		Description: goal + "\n" + rule,
+	}
+}
+
+// Returns the lank message and its color of the score.
+// The message is empty if the mode does not rank.
+func mapScoreToLank(
+	rankTable *models.RankTable, score float64, translator *i18n.Translator, theme *themes.Theme) (string, termbox.Attribute) {
+	rank := rankTable.DecideRank(score)
+	if rank == nil {
+		return "", theme.GetColor("rank.normal")
+	}
+	message := rank.Title
+	if rank.MessageKey != "" {
+		message = translator.Translate(rank.MessageKey)
+	}
+	// The colors of the config are validated when it is applied.
+	color, err := theme.ResolveColor(rank.Color)
+	if err != nil {
+		color = theme.GetColor("rank.normal")
+	}
+	return message, color
+}
+
+// The clear times of the last floors, because all floors do not fit in a row.
+func formatFloorSplits(floorRecords []*models.FloorRecord, translator *i18n.Translator) string {
+	maxCount := 6
+	splits := make([]string, 0)
+	if len(floorRecords) > maxCount {
+		splits = append(splits, "...")
+		floorRecords = floorRecords[len(floorRecords)-maxCount:]
+	}
+	for _, floorRecord := range floorRecords {
+		splits = append(splits, translator.Translate("unit.seconds", floorRecord.ClearTime.Seconds()))
+	}
+	if len(splits) == 0 {
+		return "-"
+	}
+	return strings.Join(splits, " ")
+}
+
+func mapStateModelToResultsSceneProps(
+	state *models.State, translator *i18n.Translator, theme *themes.Theme) *views.ResultsSceneProps {
+	game := state.GetGame()
+	mode := game.GetMode()
+	score := game.CalculateScore(state.GetExecutionTime())
+	lankMessage, lankMessageForeground := mapScoreToLank(game.GetRankTable(), score, translator, theme)
+	scoreBreakdown := game.CalculateScoreBreakdown()
+	rows := []*views.ResultsRowProps{
+		&views.ResultsRowProps{
+			Label: translator.Translate("results.mode"),
+			Value: translator.Translate("mode." + mode.GetName()),
+		},
+		&views.ResultsRowProps{
+			Label: translator.Translate("results.difficulty"),
+			Value: translator.Translate("difficulty." + game.GetDifficulty().GetName()),
+		},
+		&views.ResultsRowProps{
+			Label: translator.Translate("results.floor"),
+			Value: fmt.Sprintf("%d", game.GetFloorNumber()),
+		},
+		&views.ResultsRowProps{
+			Label: translator.Translate("results.time"),
+			Value: translator.Translate("unit.seconds", game.CalculatePlaytime(state.GetExecutionTime()).Seconds()),
+		},
+		&views.ResultsRowProps{
+			Label: translator.Translate("results.splits"),
+			Value: formatFloorSplits(game.GetFloorRecords(), translator),
+		},
+		&views.ResultsRowProps{},
+		&views.ResultsRowProps{
+			Label: translator.Translate("results.floorPoints"),
+			Value: fmt.Sprintf("%d", scoreBreakdown.FloorPoints),
+		},
+		&views.ResultsRowProps{
+			Label: translator.Translate("results.timeBonus"),
+			Value: fmt.Sprintf("%d", scoreBreakdown.TimeBonus),
+		},
+		&views.ResultsRowProps{
+			Label: translator.Translate("results.efficiencyBonus"),
+			Value: fmt.Sprintf("%d", scoreBreakdown.EfficiencyBonus),
+		},
+		&views.ResultsRowProps{
+			Label: translator.Translate("results.total"),
+			Value: fmt.Sprintf("%d", scoreBreakdown.CalculateTotal()),
+		},
+	}
+	if challenge := game.CreateChallenge(); challenge != nil {
+		rows = append(rows, &views.ResultsRowProps{}, &views.ResultsRowProps{
+			Label: translator.Translate("results.challenge"),
+			Value: challenge.Encode(),
+		})
+	}
+	return &views.ResultsSceneProps{
+		Rows: rows,
+		RankMessage: lankMessage,
+		RankMessageForeground: lankMessageForeground,
+		Hint: translator.Translate("hint.results"),
 	}
 }
 
 type Controller struct {
 	inputtedCharacter rune
 	inputtedKey termbox.Key
+	// The terminal size received with the last resize event. Zero values mean that no resize has occurred.
+	inputtedScreenRowLength int
+	inputtedScreenColumnLength int
 	lastMainLoopRanAt time.Time
 	state  *models.State
 	screen *views.Screen
+	cfg *config.Config
+	translator *i18n.Translator
+	theme *themes.Theme
+	colorMode themes.ColorMode
+	// It is applied to the next game.
+	difficulty *models.Difficulty
+	// The modes that are listed before the built-in modes, such as a tower of map files.
+	extraGameModes []models.GameMode
+	// The floors of all games are generated from it. Zero means that every game has new floors.
+	seed int64
+	// The best runs, they are raced as ghosts.
+	replayStore *replays.Store
+	// The time when the daily challenge was started. It is zero if the games are not the daily challenge.
+	dailyStartedAt time.Time
+	highScoreStore *highscores.Store
+	// The history of the finished games, it is summarized in the stats scene.
+	statsStore *stats.Store
+	eventBus *models.EventBus
+	achievementStore *achievements.Store
+	achievementTracker *models.AchievementTracker
+	// The name of the achievement that was unlocked last, it is shown until the execution time.
+	achievementNotice string
+	achievementNoticeUntil time.Duration
+	// The rank tables of the modes with generated floors, by the mode and the difficulty.
+	// They are kept because the calibration plays the mode, and they are dropped when the config is applied.
+	rankTables map[rankTableKey]*models.RankTable
+	// The file where a game in progress is saved when it is quit. Empty means that the games are not saved.
+	saveFilePath string
+	// It is set when the player leaves the root scene or selects to quit.
+	isQuitRequested bool
 }
 
 func (controller *Controller) GetScreen() *views.Screen {
 	return controller.screen
 }
 
+func (controller *Controller) GetColorMode() themes.ColorMode {
+	return controller.colorMode
+}
+
+func (controller *Controller) IsQuitRequested() bool {
+	return controller.isQuitRequested
+}
+
+func (controller *Controller) AddGameMode(mode models.GameMode) {
+	controller.extraGameModes = append(controller.extraGameModes, mode)
+}
+
+// It is applied to the next game scene.
+func (controller *Controller) SetSeed(seed int64) {
+	controller.seed = seed
+}
+
+// Load the best runs from the file, and save new best runs to it.
+func (controller *Controller) LoadReplays(filePath string) error {
+	store, err := replays.LoadStore(filePath)
+	if err != nil {
+		return err
+	}
+	controller.replayStore = store
+	return nil
+}
+
+// Load the scores of the daily challenges from the file, and save new scores to it.
+func (controller *Controller) LoadHighScores(filePath string) error {
+	store, err := highscores.LoadStore(filePath)
+	if err != nil {
+		return err
+	}
+	controller.highScoreStore = store
+	return nil
+}
+
+// A game in progress is saved to the file when it is quit, and it is resumed from the file.
+func (controller *Controller) SetSaveFilePath(filePath string) {
+	controller.saveFilePath = filePath
+}
+
+// Save the game in progress, it does nothing if there is none.
+func (controller *Controller) SuspendGame() error {
+	savedRun := controller.state.CreateSavedRun()
+	if savedRun == nil || controller.saveFilePath == "" {
+		return nil
+	}
+	return saves.SaveRun(controller.saveFilePath, savedRun)
+}
+
+// Restore the saved game in the paused state, and remove it from the file.
+// A hand-made tower must be added before, because the floors are not saved.
+func (controller *Controller) ResumeGame() error {
+	savedRun, err := saves.LoadRun(controller.saveFilePath)
+	if err != nil {
+		return err
+	} else if savedRun == nil {
+		return errors.Errorf("There is no saved run in %s.", controller.saveFilePath)
+	}
+	var mode models.GameMode
+	if savedRun.TowerName != "" {
+		for _, extraGameMode := range controller.extraGameModes {
+			if customTowerMode, ok := extraGameMode.(*models.CustomTowerMode); ok &&
+				customTowerMode.GetTowerName() == savedRun.TowerName {
+				mode = customTowerMode
+			}
+		}
+		if mode == nil {
+			return errors.Errorf("The tower \"%s\" of the saved run is not loaded.", savedRun.TowerName)
+		}
+	} else if mode, err = models.CreateGameMode(savedRun.ModeName); err != nil {
+		return err
+	}
+	difficulty, err := models.CreateDifficulty(savedRun.DifficultyName, savedRun.CustomDifficulty)
+	if err != nil {
+		return err
+	}
+	controller.difficulty = difficulty
+	controller.seed = savedRun.FixedSeed
+	setup, err := controller.createGameSetup(mode)
+	if err != nil {
+		return err
+	}
+	newState, err := models.RestoreSavedRun(savedRun, setup)
+	if err != nil {
+		return err
+	}
+	controller.Dispatch(newState)
+	return saves.RemoveRun(controller.saveFilePath)
+}
+
+// Load the history of the games from the file, and append new games to it.
+func (controller *Controller) LoadStats(filePath string) error {
+	store, err := stats.LoadStore(filePath)
+	if err != nil {
+		return err
+	}
+	controller.statsStore = store
+	return nil
+}
+
+// Load the unlocked achievements from the file, and save new achievements to it.
+func (controller *Controller) LoadAchievements(filePath string) error {
+	store, err := achievements.LoadStore(filePath)
+	if err != nil {
+		return err
+	}
+	controller.achievementStore = store
+	return nil
+}
+
+// Unlock the achievements that are not unlocked yet, and notify the last one.
+func (controller *Controller) unlockAchievements(names []string, executionTime time.Duration) error {
+	isChanged := false
+	for _, name := range names {
+		if controller.achievementStore.Unlock(name, time.Now()) {
+			isChanged = true
+			controller.achievementNotice = name
+			controller.achievementNoticeUntil = executionTime + achievementNoticeDuration
+		}
+	}
+	if isChanged {
+		return achievements.SaveStore(controller.achievementStore)
+	}
+	return nil
+}
+
+// The seed of the games is derived from the date of the time, so all players get the same floors on the date.
+// The date does not change until the application is restarted.
+func (controller *Controller) StartDailyChallenge(now time.Time) {
+	controller.dailyStartedAt = now
+	controller.seed = models.CalculateDailySeed(now)
+}
+
+// Returns whether the floors of the game are the ones of the daily challenge.
+// A hand-made tower is not, because it does not depend on the seed.
+func (controller *Controller) isDailyGame(game *models.Game) bool {
+	if controller.dailyStartedAt.IsZero() {
+		return false
+	} else if _, ok := game.GetMode().(models.FloorProvider); ok {
+		return false
+	}
+	return game.GetFixedSeed() == models.CalculateDailySeed(controller.dailyStartedAt)
+}
+
+// Start the game of the challenge code at once.
+// The seed and the difficulty of the challenge are also applied to the following games.
+func (controller *Controller) StartChallenge(code string) error {
+	challenge, err := models.DecodeChallenge(code)
+	if err != nil {
+		return err
+	}
+	difficulty, err := challenge.CreateDifficulty()
+	if err != nil {
+		return err
+	}
+	mode, err := models.CreateGameMode(challenge.ModeName)
+	if err != nil {
+		return err
+	}
+	controller.difficulty = difficulty
+	controller.seed = challenge.Seed
+	setup, err := controller.createGameSetup(mode)
+	if err != nil {
+		return err
+	}
+	newState, err := reducers.EnterGameScene(*controller.state, 0, setup)
+	if err != nil {
+		return err
+	}
+	controller.Dispatch(newState)
+	return nil
+}
+
+type rankTableKey struct {
+	modeName string
+	difficulty models.Difficulty
+}
+
+// The rank tables of the hand-made towers are not kept, because a tower of a playtest has the name of the editor's map.
+func (controller *Controller) findOrCreateRankTable(mode models.GameMode) (*models.RankTable, error) {
+	_, isTower := mode.(models.FloorProvider)
+	key := rankTableKey{modeName: mode.GetName(), difficulty: *controller.difficulty}
+	if rankTable, ok := controller.rankTables[key]; ok && !isTower {
+		return rankTable, nil
+	}
+	rankTable, err := models.CreateRankTable(mode, controller.difficulty, controller.cfg.RankTables)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if !isTower {
+		controller.rankTables[key] = rankTable
+	}
+	return rankTable, nil
+}
+
+func (controller *Controller) createGameSetup(mode models.GameMode) (*models.GameSetup, error) {
+	rankTable, err := controller.findOrCreateRankTable(mode)
+	if err != nil {
+		return nil, err
+	}
+	return &models.GameSetup{
+		Mode: mode,
+		Difficulty: controller.difficulty,
+		RankTable: rankTable,
+		Seed: controller.seed,
+		Ghost: controller.replayStore.Find(models.CreateReplayKey(mode, controller.difficulty, controller.seed)),
+	}, nil
+}
+
+// Keep the run of the game that has just finished if it is the best.
+// The playtests in the editor are not kept, because the floor is still changing.
+func (controller *Controller) recordFinishedGame(event *models.GameFinishedEvent) error {
+	game := event.Game
+	if event.IsPlaytest {
+		return nil
+	}
+	if gameRecord := stats.CreateGameRecord(time.Now(), game, event.Time); gameRecord != nil {
+		controller.statsStore.Append(gameRecord)
+		if err := stats.SaveStore(controller.statsStore); err != nil {
+			return err
+		}
+	}
+	if controller.isDailyGame(game) {
+		dailyScore := highscores.CreateDailyScore(
+			models.FormatDailyDate(controller.dailyStartedAt), game, event.Time)
+		if controller.highScoreStore.SubmitDaily(dailyScore) {
+			if err := highscores.SaveStore(controller.highScoreStore); err != nil {
+				return err
+			}
+		}
+		streak := controller.highScoreStore.CalculateStreak(controller.dailyStartedAt)
+		err := controller.unlockAchievements(controller.achievementTracker.TrackDailyStreak(streak), event.Time)
+		if err != nil {
+			return err
+		}
+	}
+	if controller.replayStore.Submit(game.CreateReplay(event.Time)) {
+		return replays.SaveStore(controller.replayStore)
+	}
+	return nil
+}
+
+// The subscriber is called with every event of the games, such as climbing the stairs.
+func (controller *Controller) SubscribeEvents(subscriber models.EventSubscriber) {
+	controller.eventBus.Subscribe(subscriber)
+}
+
+// Record the finished game and the achievements, and deliver the events of the state to the subscribers.
+func (controller *Controller) publishEvents(state *models.State) error {
+	events := state.DrainEvents()
+	for _, event := range events {
+		if gameFinishedEvent, ok := event.(*models.GameFinishedEvent); ok {
+			if err := controller.recordFinishedGame(gameFinishedEvent); err != nil {
+				return err
+			}
+		}
+		// The playtests in the editor do not unlock achievements, because the floor can be made easy.
+		if !state.ContainsScene(models.SceneEditor) {
+			names := controller.achievementTracker.Track(event)
+			if err := controller.unlockAchievements(names, event.GetTime()); err != nil {
+				return err
+			}
+		}
+	}
+	controller.eventBus.Publish(events)
+	return nil
+}
+
+// The date and the streak are shown after the mode and the difficulty.
+func (controller *Controller) insertDailyResultsRows(props *views.ResultsSceneProps) {
+	translator := controller.translator
+	streak := controller.highScoreStore.CalculateStreak(controller.dailyStartedAt)
+	streakKey := "unit.days"
+	if streak == 1 {
+		streakKey = "unit.day"
+	}
+	dailyRows := []*views.ResultsRowProps{
+		&views.ResultsRowProps{
+			Label: translator.Translate("results.daily"),
+			Value: models.FormatDailyDate(controller.dailyStartedAt),
+		},
+		&views.ResultsRowProps{
+			Label: translator.Translate("results.streak"),
+			Value: translator.Translate(streakKey, streak),
+		},
+	}
+	props.Rows = append(props.Rows[:2], append(dailyRows, props.Rows[2:]...)...)
+}
+
+// Returns an empty string if there is no achievement to notify.
+func (controller *Controller) createAchievementNotice(state *models.State) string {
+	if controller.achievementNotice == "" || state.GetExecutionTime() >= controller.achievementNoticeUntil {
+		return ""
+	}
+	return controller.translator.Translate(
+		"notice.achievement", controller.translator.Translate("achievement." + controller.achievementNotice + ".title"))
+}
+
+func (controller *Controller) mapStateModelToScreenProps(state *models.State) *views.ScreenProps {
+	switch state.GetCurrentScene() {
+	case models.SceneGame:
+		gameProps := mapStateModelToGameSceneProps(
+			state, controller.translator, controller.theme, controller.cfg.ShowsTrail, controller.cfg.ShadesDeadEnds)
+		gameProps.Notice = controller.createAchievementNotice(state)
+		return &views.ScreenProps{Game: gameProps}
+	case models.SceneResults:
+		resultsProps := mapStateModelToResultsSceneProps(state, controller.translator, controller.theme)
+		if controller.isDailyGame(state.GetGame()) {
+			controller.insertDailyResultsRows(resultsProps)
+		}
+		resultsProps.Notice = controller.createAchievementNotice(state)
+		return &views.ScreenProps{Results: resultsProps}
+	case models.SceneStats:
+		return &views.ScreenProps{Stats: controller.mapStateModelToStatsSceneProps(state)}
+	case models.SceneHeatmap:
+		return &views.ScreenProps{Heatmap: controller.mapStateModelToHeatmapSceneProps(state)}
+	case models.SceneEditor:
+		return &views.ScreenProps{
+			Editor: mapStateModelToEditorSceneProps(state, controller.translator, controller.theme),
+		}
+	}
+	return &views.ScreenProps{Menu: controller.mapStateModelToMenuSceneProps(state)}
+}
+
 func (controller *Controller) setKeyInputs(ch rune, key termbox.Key) {
 	controller.inputtedCharacter = ch
 	controller.inputtedKey = key
@@ -125,6 +661,16 @@ func (controller *Controller) resetKeyInputs() {
 	controller.setKeyInputs(0, 0)
 }
 
+func (controller *Controller) resizeScreenIfRequested() {
+	rowLength := controller.inputtedScreenRowLength
+	columnLength := controller.inputtedScreenColumnLength
+	controller.inputtedScreenRowLength = 0
+	controller.inputtedScreenColumnLength = 0
+	if rowLength > 0 && columnLength > 0 {
+		controller.screen = views.CreateScreen(rowLength, columnLength, controller.translator, controller.theme)
+	}
+}
+
 func (controller *Controller) CalculateIntervalToNextMainLoop(now time.Time) time.Duration {
 	// About 60fps.
 	intervalOfPurpose := time.Microsecond*16666
@@ -145,7 +691,7 @@ func (controller *Controller) CalculateIntervalToNextMainLoop(now time.Time) tim
 
 func (controller *Controller) Dispatch(newState *models.State) {
 	controller.state = newState
-	screenProps := mapStateModelToScreenProps(controller.state)
+	screenProps := controller.mapStateModelToScreenProps(controller.state)
 	controller.screen.Render(screenProps)
 }
 
@@ -153,25 +699,36 @@ func (controller *Controller) HandleMainLoop(elapsedTime time.Duration) (*models
 	ch := controller.inputtedCharacter
 	key := controller.inputtedKey
 	controller.resetKeyInputs()
+	// The new screen is rendered by the following dispatch.
+	controller.resizeScreenIfRequested()
 
 	var newState *models.State
 	var err error
 
 	switch {
-	// Start or restart a game.
-	case ch == 's':
-		newState, err = reducers.StartOrRestartGame(*controller.state, elapsedTime)
-	// Move the hero.
-	case key == termbox.KeyArrowUp || ch == 'k':
-		newState, err = reducers.WalkHero(*controller.state, elapsedTime, reducers.FourDirectionUp)
-	case key == termbox.KeyArrowRight || ch == 'l':
-		newState, err = reducers.WalkHero(*controller.state, elapsedTime, reducers.FourDirectionRight)
-	case key == termbox.KeyArrowDown || ch == 'j':
-		newState, err = reducers.WalkHero(*controller.state, elapsedTime, reducers.FourDirectionDown)
-	case key == termbox.KeyArrowLeft || ch == 'h':
-		newState, err = reducers.WalkHero(*controller.state, elapsedTime, reducers.FourDirectionLeft)
+	// Go back to the previous scene, or quit the application at the root scene.
+	case key == termbox.KeyEsc:
+		if controller.state.IsAtRootScene() {
+			controller.isQuitRequested = true
+			newState, err = reducers.AdvanceOnlyTime(*controller.state, elapsedTime)
+		} else if err = controller.SuspendGame(); err == nil {
+			newState, err = reducers.PopScene(*controller.state, elapsedTime)
+		}
+	case controller.state.GetCurrentScene() == models.SceneGame:
+		newState, err = controller.handleGameScene(ch, key, elapsedTime)
+	case controller.state.GetCurrentScene() == models.SceneResults:
+		newState, err = controller.handleResultsScene(ch, key, elapsedTime)
+	case controller.state.GetCurrentScene() == models.SceneEditor:
+		newState, err = controller.handleEditorScene(ch, key, elapsedTime)
+	case controller.state.GetCurrentScene() == models.SceneStats:
+		newState, err = controller.handleStatsScene(ch, key, elapsedTime)
+	case controller.state.GetCurrentScene() == models.SceneHeatmap:
+		newState, err = controller.handleHeatmapScene(ch, key, elapsedTime)
 	default:
-		newState, err = reducers.AdvanceOnlyTime(*controller.state, elapsedTime)
+		newState, err = controller.handleMenuScene(ch, key, elapsedTime)
+	}
+	if err == nil {
+		err = controller.publishEvents(newState)
 	}
 
 	return newState, err
@@ -181,7 +738,46 @@ func (controller *Controller) HandleKeyPress(ch rune, key termbox.Key) {
 	controller.setKeyInputs(ch, key)
 }
 
-func CreateController() (*Controller, error) {
+func (controller *Controller) HandleResize(rowLength int, columnLength int) {
+	controller.inputtedScreenRowLength = rowLength
+	controller.inputtedScreenColumnLength = columnLength
+}
+
+func (controller *Controller) applyConfig(cfg *config.Config) error {
+	translator := i18n.CreateTranslator(i18n.DetectLanguage(cfg.Language))
+	colorMode, err := themes.ParseColorMode(cfg.ColorMode)
+	if err != nil {
+		return err
+	}
+	theme, err := themes.CreateTheme(cfg.Theme, cfg.Themes, colorMode)
+	if err != nil {
+		return err
+	}
+	difficulty, err := models.CreateDifficulty(cfg.Difficulty, cfg.CustomDifficulty)
+	if err != nil {
+		return err
+	}
+	for key, rankTableConfig := range cfg.RankTables {
+		rankConfigs := append([]*config.RankConfig{rankTableConfig.Lowest}, rankTableConfig.Ranks...)
+		for _, rankConfig := range rankConfigs {
+			if rankConfig == nil || rankConfig.Color == "" {
+				continue
+			}
+			if _, err := theme.ResolveColor(rankConfig.Color); err != nil {
+				return errors.Wrapf(err, "The color of the rank table \"%s\" is invalid.", key)
+			}
+		}
+	}
+	controller.cfg = cfg
+	controller.translator = translator
+	controller.theme = theme
+	controller.colorMode = colorMode
+	controller.difficulty = difficulty
+	controller.rankTables = make(map[rankTableKey]*models.RankTable)
+	return nil
+}
+
+func CreateController(screenRowLength int, screenColumnLength int, cfg *config.Config) (*Controller, error) {
 	controller := &Controller{}
 
 	state := models.CreateState()
@@ -190,11 +786,21 @@ func CreateController() (*Controller, error) {
 		return controller, err
 	}
 
-	screen := views.CreateScreen(24, 80)
+	err = controller.applyConfig(cfg)
+	if err != nil {
+		return controller, err
+	}
+	screen := views.CreateScreen(screenRowLength, screenColumnLength, controller.translator, controller.theme)
 
 	controller.resetKeyInputs()
 	controller.state = state
 	controller.screen = screen
+	controller.replayStore = replays.CreateStore()
+	controller.highScoreStore = highscores.CreateStore()
+	controller.statsStore = stats.CreateStore()
+	controller.eventBus = models.CreateEventBus()
+	controller.achievementStore = achievements.CreateStore()
+	controller.achievementTracker = &models.AchievementTracker{}
 	controller.Dispatch(state)
 
 	return controller, nil
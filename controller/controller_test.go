@@ -1,10 +1,45 @@
 package controller
 
 import (
+	"github.com/kjirou/tower-of-go/config"
+	"github.com/kjirou/tower-of-go/levels"
+	"github.com/kjirou/tower-of-go/models"
+	"github.com/kjirou/tower-of-go/utils"
+	"github.com/nsf/termbox-go"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
 
+// The interval of the main loop in the tests, it is about 60 frames per second.
+const testFrameInterval = 16 * time.Millisecond
+
+// Press a key and advance a frame of the main loop, as the main loop of the game does.
+func pressKey(t *testing.T, controller *Controller, ch rune, key termbox.Key) {
+	t.Helper()
+	controller.HandleKeyPress(ch, key)
+	newState, err := controller.HandleMainLoop(testFrameInterval)
+	if err != nil {
+		t.Fatal(err)
+	}
+	controller.Dispatch(newState)
+}
+
+// A tower of a floor, the hero reaches the upstairs by 4 steps to the right.
+func createCorridorTowerMode(t *testing.T) *models.CustomTowerMode {
+	t.Helper()
+	level, err := levels.ParseLevel("#######\n#@...<#\n#######\n", "corridor.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	towerMode, err := levels.CreateTowerMode(level.Name, []*levels.Level{level})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return towerMode
+}
+
 func TestController_CalculateIntervalToNextMainLoop_NotTD(t *testing.T) {
 	controller := &Controller{}
 
@@ -60,3 +95,742 @@ func TestController_CalculateIntervalToNextMainLoop_NotTD(t *testing.T) {
 		}
 	})
 }
+
+func TestController_HandleResize_NotTD(t *testing.T) {
+	t.Run("次のメインループで、指定したサイズの画面へ差し替わる", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		controller.HandleResize(30, 100)
+		elapsedTime, _ := time.ParseDuration("16ms")
+		controller.HandleMainLoop(elapsedTime)
+		maxY := 0
+		maxX := 0
+		controller.GetScreen().ForEachCells(func(y int, x int, _ rune, _ termbox.Attribute, _ termbox.Attribute) {
+			maxY = y
+			maxX = x
+		})
+		if maxY != 29 || maxX != 99 {
+			t.Fatal("画面のサイズが違う")
+		}
+	})
+}
+
+func TestController_GameSceneDescription_NotTD(t *testing.T) {
+	t.Run("制限時間のない禅モードの説明に書式の崩れがない", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyArrowUp)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		if controller.state.GetGame().GetMode().GetName() != "zen" {
+			t.Fatal("禅モードではない")
+		}
+		props := mapStateModelToGameSceneProps(controller.state, controller.translator, controller.theme, false, false)
+		if strings.Contains(props.Description, "%!") {
+			t.Fatal("説明の書式が崩れている")
+		}
+	})
+}
+
+func TestController_HandleMainLoop_Scenes_NotTD(t *testing.T) {
+	t.Run("タイトルから選択したモードのゲームへ進み、Escで戻る", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		if controller.state.GetCurrentScene() != models.SceneTitle {
+			t.Fatal("タイトルではない")
+		}
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		if controller.state.GetCurrentScene() != models.SceneModeSelect {
+			t.Fatal("モード選択ではない")
+		}
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		if controller.state.GetCurrentScene() != models.SceneGame {
+			t.Fatal("ゲームではない")
+		}
+		pressKey(t, controller, 0, termbox.KeyEsc)
+		if controller.state.GetCurrentScene() != models.SceneModeSelect {
+			t.Fatal("モード選択へ戻っていない")
+		}
+		if controller.IsQuitRequested() {
+			t.Fatal("終了を要求している")
+		}
+	})
+
+	t.Run("タイトルでEscを押すと終了を要求する", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		pressKey(t, controller, 0, termbox.KeyEsc)
+		if !controller.IsQuitRequested() {
+			t.Fatal("終了を要求していない")
+		}
+	})
+
+	t.Run("ゲームの時間切れで結果へ進む", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 's', 0)
+		longTime, _ := time.ParseDuration("31s")
+		newState, _ := controller.HandleMainLoop(longTime)
+		controller.Dispatch(newState)
+		newState, _ = controller.HandleMainLoop(testFrameInterval)
+		controller.Dispatch(newState)
+		if controller.state.GetCurrentScene() != models.SceneResults {
+			t.Fatal("結果ではない")
+		}
+	})
+}
+
+func TestController_OpenEditor_NotTD(t *testing.T) {
+	t.Run("新しいマップを保存すると、読み込めるマップファイルになる", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "new.txt")
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		if err := controller.OpenEditor(filePath); err != nil {
+			t.Fatal(err)
+		}
+		if controller.state.GetCurrentScene() != models.SceneEditor {
+			t.Fatal("エディタではない")
+		}
+		pressKey(t, controller, 0, termbox.KeyArrowRight)
+		pressKey(t, controller, 0, termbox.KeyArrowRight)
+		pressKey(t, controller, '1', 0)
+		pressKey(t, controller, 0, termbox.KeySpace)
+		pressKey(t, controller, 'w', 0)
+		if notice := controller.state.GetEditor().GetNotice(); notice == nil || notice.IsError {
+			t.Fatal("保存できていない")
+		}
+		level, err := levels.LoadLevel(filePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		element, _ := level.Field.At(&utils.MatrixPosition{Y: 1, X: 3})
+		if element.GetObjectClass() != "wall" {
+			t.Fatal("置いた壁が保存されていない")
+		}
+	})
+
+	t.Run("試遊するとゲームが始まり、Escでエディタへ戻る", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		controller.OpenEditor(filepath.Join(t.TempDir(), "new.txt"))
+		// The zero time means that the game has not started.
+		pressKey(t, controller, 0, 0)
+		pressKey(t, controller, 'p', 0)
+		if controller.state.GetCurrentScene() != models.SceneGame {
+			t.Fatal("ゲームではない")
+		} else if !controller.state.GetGame().IsStarted() {
+			t.Fatal("ゲームが始まっていない")
+		}
+		pressKey(t, controller, 0, termbox.KeyEsc)
+		if controller.state.GetCurrentScene() != models.SceneEditor {
+			t.Fatal("エディタへ戻っていない")
+		}
+	})
+
+	t.Run("クリアできないフロアは試遊できない", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		controller.OpenEditor(filepath.Join(t.TempDir(), "new.txt"))
+		pressKey(t, controller, 0, termbox.KeyArrowRight)
+		pressKey(t, controller, '1', 0)
+		pressKey(t, controller, 0, termbox.KeySpace)
+		pressKey(t, controller, 0, termbox.KeyArrowLeft)
+		pressKey(t, controller, 0, termbox.KeyArrowDown)
+		pressKey(t, controller, 0, termbox.KeySpace)
+		pressKey(t, controller, 'p', 0)
+		if controller.state.GetCurrentScene() != models.SceneEditor {
+			t.Fatal("エディタではない")
+		} else if notice := controller.state.GetEditor().GetNotice(); notice == nil || !notice.IsError {
+			t.Fatal("エラーを知らせていない")
+		}
+	})
+}
+
+func TestController_Ghost_NotTD(t *testing.T) {
+	towerMode := createCorridorTowerMode(t)
+
+	t.Run("塔を登りきった記録を保存し、次のゲームで幽霊として走らせる", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "replays.json")
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		controller.AddGameMode(towerMode)
+		if err := controller.LoadReplays(filePath); err != nil {
+			t.Fatal(err)
+		}
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 's', 0)
+		for i := 0; i < 4; i++ {
+			pressKey(t, controller, 0, termbox.KeyArrowRight)
+			pressKey(t, controller, 0, 0)
+		}
+		pressKey(t, controller, 0, 0)
+		if controller.state.GetCurrentScene() != models.SceneResults {
+			t.Fatal("結果ではない")
+		}
+		if err := controller.LoadReplays(filePath); err != nil {
+			t.Fatal(err)
+		} else if controller.replayStore.Find("custom:corridor/normal") == nil {
+			t.Fatal("記録が保存されていない")
+		}
+
+		// The ghost moved a step per frame, it goes ahead of the hero who stands still.
+		pressKey(t, controller, 's', 0)
+		pressKey(t, controller, 0, 0)
+		pressKey(t, controller, 0, 0)
+		props := mapStateModelToGameSceneProps(controller.state, controller.translator, controller.theme, false, false)
+		if props.GhostFloorNumber != 1 {
+			t.Fatal("幽霊の階が違う")
+		} else if props.FieldCells[1][4].Symbol != controller.theme.GetTile("ghost").Symbol {
+			t.Fatal("幽霊が描かれていない")
+		}
+	})
+}
+
+func TestController_StartDailyChallenge_NotTD(t *testing.T) {
+	now := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC)
+
+	t.Run("日付の種でゲームを始め、終わった得点を日付ごとに保存する", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "highscores.json")
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		controller.StartDailyChallenge(now)
+		if err := controller.LoadHighScores(filePath); err != nil {
+			t.Fatal(err)
+		}
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 's', 0)
+		if controller.state.GetGame().GetSeed() != 20240131 {
+			t.Fatal("日付の種ではない")
+		}
+		longTime, _ := time.ParseDuration("31s")
+		newState, _ := controller.HandleMainLoop(longTime)
+		controller.Dispatch(newState)
+		pressKey(t, controller, 0, 0)
+		if controller.state.GetCurrentScene() != models.SceneResults {
+			t.Fatal("結果ではない")
+		}
+
+		if err := controller.LoadHighScores(filePath); err != nil {
+			t.Fatal(err)
+		} else if controller.highScoreStore.FindDaily("2024-01-31", "timeAttack", "normal") == nil {
+			t.Fatal("得点が保存されていない")
+		}
+		props := controller.mapStateModelToScreenProps(controller.state).Results
+		if props.Rows[2].Value != "2024-01-31" || props.Rows[3].Value != "1 day" {
+			t.Fatal("日付か連続日数が表示されていない")
+		}
+	})
+}
+
+func TestController_StartChallenge_NotTD(t *testing.T) {
+	t.Run("結果に表示されたコードから同じゲームを始める", func(t *testing.T) {
+		cfg := config.CreateDefaultConfig()
+		cfg.Difficulty = "hard"
+		controller, _ := CreateController(24, 80, cfg)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 's', 0)
+		game := controller.state.GetGame()
+		longTime, _ := time.ParseDuration("31s")
+		newState, _ := controller.HandleMainLoop(longTime)
+		controller.Dispatch(newState)
+		pressKey(t, controller, 0, 0)
+		props := controller.mapStateModelToScreenProps(controller.state).Results
+		code := props.Rows[len(props.Rows)-1].Value
+
+		otherController, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		if err := otherController.StartChallenge(code); err != nil {
+			t.Fatal(err)
+		}
+		pressKey(t, otherController, 's', 0)
+		otherGame := otherController.state.GetGame()
+		if otherController.state.GetCurrentScene() != models.SceneGame {
+			t.Fatal("ゲームが始まっていない")
+		} else if otherGame.GetMode().GetName() != "timeAttack" || otherGame.GetDifficulty().GetName() != "hard" {
+			t.Fatal("モードか難易度が違う")
+		} else if otherGame.GetSeed() != game.GetSeed() {
+			t.Fatal("種が違う")
+		}
+	})
+
+	t.Run("不正なコードはエラーを返す", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		if err := controller.StartChallenge("AAAA"); err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+}
+
+func TestController_Rewind_NotTD(t *testing.T) {
+	findHero := func(state *models.State) *utils.MatrixPosition {
+		element, err := state.GetField().GetElementOfHero()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return element.GetPosition()
+	}
+	// Move from the upper left corner, one of the right and the bottom is a passage.
+	startGameAndMove := func(controller *Controller) {
+		pressKey(t, controller, 's', 0)
+		pressKey(t, controller, 0, 0)
+		pressKey(t, controller, 0, termbox.KeyArrowRight)
+		pressKey(t, controller, 0, termbox.KeyArrowDown)
+		if position := findHero(controller.state); position.Y == 1 && position.X == 1 {
+			t.Fatal("動いていない")
+		}
+	}
+
+	t.Run("禅モードでは一手ずつ戻せる", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyArrowUp)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		startGameAndMove(controller)
+		previousState := controller.state
+		pressKey(t, controller, 'u', 0)
+		pressKey(t, controller, 'u', 0)
+		if position := findHero(controller.state); position.Y != 1 || position.X != 1 {
+			t.Fatal("戻っていない")
+		} else if position := findHero(previousState); position.Y == 1 && position.X == 1 {
+			t.Fatal("前の状態が変わっている")
+		}
+	})
+
+	t.Run("戻しても実行時間は戻らず、遊んだ時間だけが戻る", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyArrowUp)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		startGameAndMove(controller)
+		for i := 0; i < 3; i++ {
+			pressKey(t, controller, 0, 0)
+		}
+		calculatePlaytime := func() time.Duration {
+			return controller.state.GetGame().CalculatePlaytime(controller.state.GetExecutionTime())
+		}
+		executionTime := controller.state.GetExecutionTime()
+		playtime := calculatePlaytime()
+		pressKey(t, controller, 'u', 0)
+		if controller.state.GetExecutionTime() <= executionTime {
+			t.Fatal("実行時間が戻っている")
+		} else if calculatePlaytime() >= playtime {
+			t.Fatal("遊んだ時間が戻っていない")
+		}
+	})
+
+	t.Run("戻すと、戻った位置の出来事を伝える", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		events := make([]models.Event, 0)
+		controller.SubscribeEvents(func(event models.Event) {
+			events = append(events, event)
+		})
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyArrowUp)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		startGameAndMove(controller)
+		pressKey(t, controller, 'u', 0)
+		event, ok := events[len(events)-1].(*models.HeroRewoundEvent)
+		if !ok {
+			t.Fatal("伝えていない")
+		} else if position := findHero(controller.state); *event.Position != *position || event.FloorNumber != 1 {
+			t.Fatal("戻った位置ではない")
+		}
+	})
+
+	t.Run("タイムアタックでは戻せない", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		startGameAndMove(controller)
+		pressKey(t, controller, 'u', 0)
+		pressKey(t, controller, 'u', 0)
+		if position := findHero(controller.state); position.Y == 1 && position.X == 1 {
+			t.Fatal("戻っている")
+		}
+	})
+}
+
+func TestController_CreateGameSetup_NotTD(t *testing.T) {
+	t.Run("同じモードと難易度のランク表は作り直さない", func(t *testing.T) {
+		cfg := config.CreateDefaultConfig()
+		cfg.Difficulty = "custom"
+		controller, _ := CreateController(24, 80, cfg)
+		createRankTable := func() *models.RankTable {
+			mode, _ := models.CreateGameMode("timeAttack")
+			setup, err := controller.createGameSetup(mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return setup.RankTable
+		}
+		rankTable := createRankTable()
+		if rankTable == nil || createRankTable() != rankTable {
+			t.Fatal("作り直している")
+		}
+	})
+}
+
+func TestController_ResumeGame_NotTD(t *testing.T) {
+	t.Run("Escで中断したゲームを、一時停止した状態で再開する", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "save.json")
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		controller.SetSaveFilePath(filePath)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 's', 0)
+		pressKey(t, controller, 0, 0)
+		playtime := controller.state.GetGame().CalculatePlaytime(controller.state.GetExecutionTime())
+		seed := controller.state.GetGame().GetSeed()
+		pressKey(t, controller, 0, termbox.KeyEsc)
+
+		resumedController, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		resumedController.SetSaveFilePath(filePath)
+		if err := resumedController.ResumeGame(); err != nil {
+			t.Fatal(err)
+		}
+		game := resumedController.state.GetGame()
+		if resumedController.state.GetCurrentScene() != models.SceneGame || game.GetSeed() != seed {
+			t.Fatal("ゲームが再開されていない")
+		}
+		calculatePlaytime := func() time.Duration {
+			return resumedController.state.GetGame().CalculatePlaytime(resumedController.state.GetExecutionTime())
+		}
+		pressKey(t, resumedController, 0, 0)
+		if calculatePlaytime() != playtime {
+			t.Fatal("一時停止中に時間が進んでいる")
+		}
+		pressKey(t, resumedController, 'x', 0)
+		pressKey(t, resumedController, 0, 0)
+		if calculatePlaytime() <= playtime {
+			t.Fatal("キーを押しても時間が進まない")
+		}
+		if err := resumedController.ResumeGame(); err == nil {
+			t.Fatal("再開したゲームが残っている")
+		}
+	})
+
+	t.Run("始まっていないゲームは保存しない", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "save.json")
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		controller.SetSaveFilePath(filePath)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyEsc)
+		if err := controller.ResumeGame(); err == nil {
+			t.Fatal("保存されている")
+		}
+	})
+}
+
+func TestController_SubscribeEvents_NotTD(t *testing.T) {
+	towerMode := createCorridorTowerMode(t)
+
+	t.Run("ゲームの開始から終了までの出来事を順に受け取る", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		controller.AddGameMode(towerMode)
+		events := make([]models.Event, 0)
+		controller.SubscribeEvents(func(event models.Event) {
+			events = append(events, event)
+		})
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 's', 0)
+		for i := 0; i < 4; i++ {
+			pressKey(t, controller, 0, termbox.KeyArrowRight)
+			pressKey(t, controller, 0, 0)
+		}
+		// A bump against the wall is not a move.
+		pressKey(t, controller, 0, termbox.KeyArrowUp)
+		pressKey(t, controller, 0, 0)
+
+		if len(events) != 7 {
+			t.Fatalf("%d events are received", len(events))
+		} else if event, ok := events[0].(*models.GameStartedEvent); !ok || event.ModeName != "custom" {
+			t.Fatal("開始していない")
+		}
+		for _, event := range events[1:5] {
+			if _, ok := event.(*models.HeroMovedEvent); !ok {
+				t.Fatal("移動していない")
+			}
+		}
+		if event, ok := events[4].(*models.HeroMovedEvent); !ok || event.To.X != 5 {
+			t.Fatal("階段へ移動していない")
+		} else if event, ok := events[5].(*models.FloorClearedEvent); !ok || event.Record.MoveCount != 4 {
+			t.Fatal("階を登っていない")
+		} else if event, ok := events[6].(*models.GameFinishedEvent); !ok || !event.Game.IsFinished() {
+			t.Fatal("終了していない")
+		}
+	})
+}
+
+func TestController_Achievements_NotTD(t *testing.T) {
+	towerMode := createCorridorTowerMode(t)
+
+	t.Run("最短経路で登ると実績を解除して保存し、結果に通知する", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "achievements.json")
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		controller.AddGameMode(towerMode)
+		if err := controller.LoadAchievements(filePath); err != nil {
+			t.Fatal(err)
+		}
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 's', 0)
+		for i := 0; i < 4; i++ {
+			pressKey(t, controller, 0, termbox.KeyArrowRight)
+			pressKey(t, controller, 0, 0)
+		}
+		if controller.state.GetCurrentScene() != models.SceneResults {
+			t.Fatal("結果ではない")
+		}
+		for _, name := range []string{"firstFloor", "optimalPath", "noBacktracking"} {
+			if !controller.achievementStore.IsUnlocked(name) {
+				t.Fatalf("%s is not unlocked", name)
+			}
+		}
+		if controller.achievementStore.IsUnlocked("sprinter") {
+			t.Fatal("条件を満たさない実績を解除している")
+		} else if controller.createAchievementNotice(controller.state) == "" {
+			t.Fatal("通知していない")
+		}
+
+		reloadedController, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		if err := reloadedController.LoadAchievements(filePath); err != nil {
+			t.Fatal(err)
+		} else if !reloadedController.achievementStore.IsUnlocked("optimalPath") {
+			t.Fatal("保存されていない")
+		}
+	})
+
+	t.Run("通知は一定時間で消える", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		controller.unlockAchievements([]string{"firstFloor"}, controller.state.GetExecutionTime())
+		if controller.createAchievementNotice(controller.state) == "" {
+			t.Fatal("通知していない")
+		}
+		newState, _ := controller.HandleMainLoop(achievementNoticeDuration)
+		controller.Dispatch(newState)
+		if controller.createAchievementNotice(controller.state) != "" {
+			t.Fatal("通知が残っている")
+		}
+	})
+
+	t.Run("タイトルから実績の一覧へ進み、Escで戻る", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		pressKey(t, controller, 0, termbox.KeyArrowDown)
+		pressKey(t, controller, 0, termbox.KeyArrowDown)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		if controller.state.GetCurrentScene() != models.SceneAchievements {
+			t.Fatal("実績の一覧ではない")
+		}
+		// The items can not be decided.
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		if controller.state.GetCurrentScene() != models.SceneAchievements {
+			t.Fatal("実績の一覧から移動している")
+		}
+		pressKey(t, controller, 0, termbox.KeyEsc)
+		if controller.state.GetCurrentScene() != models.SceneTitle {
+			t.Fatal("タイトルへ戻っていない")
+		}
+	})
+}
+
+func TestController_Stats_NotTD(t *testing.T) {
+	towerMode := createCorridorTowerMode(t)
+
+	t.Run("終えたゲームを履歴へ追加して保存し、統計に表示する", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "history.json")
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		controller.AddGameMode(towerMode)
+		if err := controller.LoadStats(filePath); err != nil {
+			t.Fatal(err)
+		}
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		for i := 0; i < 2; i++ {
+			pressKey(t, controller, 's', 0)
+			for j := 0; j < 4; j++ {
+				pressKey(t, controller, 0, termbox.KeyArrowRight)
+				pressKey(t, controller, 0, 0)
+			}
+		}
+
+		reloadedController, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		if err := reloadedController.LoadStats(filePath); err != nil {
+			t.Fatal(err)
+		}
+		games := reloadedController.statsStore.Games
+		if len(games) != 2 {
+			t.Fatalf("%d games are recorded", len(games))
+		} else if games[0].Mode != "custom" || len(games[0].Floors) != 1 || games[0].Floors[0].MoveCount != 4 {
+			t.Fatal("記録が違う")
+		}
+
+		for i := 0; i < 3; i++ {
+			pressKey(t, reloadedController, 0, termbox.KeyArrowDown)
+		}
+		pressKey(t, reloadedController, 0, termbox.KeyEnter)
+		if reloadedController.state.GetCurrentScene() != models.SceneStats {
+			t.Fatal("統計ではない")
+		}
+		props := reloadedController.mapStateModelToStatsSceneProps(reloadedController.state)
+		if props.Rows[0].Value != "2" {
+			t.Fatal("ゲーム数が違う")
+		}
+	})
+
+	t.Run("手作りの塔を登りきれずに時間切れになったゲームは履歴へ追加しない", func(t *testing.T) {
+		level, err := levels.ParseLevel("timeLimit: 0.5\n---\n#######\n#@...<#\n#######\n", "timed.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		timedTowerMode, err := levels.CreateTowerMode(level.Name, []*levels.Level{level})
+		if err != nil {
+			t.Fatal(err)
+		}
+		filePath := filepath.Join(t.TempDir(), "history.json")
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		controller.AddGameMode(timedTowerMode)
+		if err := controller.LoadStats(filePath); err != nil {
+			t.Fatal(err)
+		}
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 's', 0)
+		for j := 0; j < 4; j++ {
+			pressKey(t, controller, 0, termbox.KeyArrowRight)
+			pressKey(t, controller, 0, 0)
+		}
+		pressKey(t, controller, 's', 0)
+		for !controller.state.GetGame().IsFinished() {
+			pressKey(t, controller, 0, 0)
+		}
+
+		reloadedController, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		if err := reloadedController.LoadStats(filePath); err != nil {
+			t.Fatal(err)
+		}
+		if len(controller.statsStore.Games) != 1 || len(reloadedController.statsStore.Games) != 1 {
+			t.Fatal("時間切れのゲームを追加している")
+		}
+	})
+
+	t.Run("ゲームがなければ、その旨を表示する", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		props := controller.mapStateModelToStatsSceneProps(controller.state)
+		if len(props.Rows) != 1 || props.Rows[0].Label != controller.translator.Translate("stats.empty") {
+			t.Fatal("表示していない")
+		}
+	})
+}
+
+func TestController_Heatmap_NotTD(t *testing.T) {
+	towerMode := createCorridorTowerMode(t)
+
+	t.Run("結果からヒートマップを開き、訪れたマスを数える", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		controller.AddGameMode(towerMode)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 's', 0)
+		pressKey(t, controller, 0, termbox.KeyArrowRight)
+		pressKey(t, controller, 0, termbox.KeyArrowLeft)
+		for i := 0; i < 4; i++ {
+			pressKey(t, controller, 0, termbox.KeyArrowRight)
+		}
+		pressKey(t, controller, 0, 0)
+		pressKey(t, controller, 'v', 0)
+		if controller.state.GetCurrentScene() != models.SceneHeatmap {
+			t.Fatal("ヒートマップではない")
+		}
+		props := controller.mapStateModelToHeatmapSceneProps(controller.state)
+		if props.Rows[0].Value != "6" || props.Rows[1].Value != "4" {
+			t.Fatal("移動数が違う")
+		} else if props.Rows[2].Value != "5" || props.Rows[3].Value != "2" {
+			t.Fatal("マスの数が違う")
+		} else if props.FieldCells[1][1].Symbol != '2' {
+			t.Fatal("訪問回数を表示していない")
+		}
+	})
+
+	t.Run("tで軌跡の表示を切り替える", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		controller.AddGameMode(towerMode)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 's', 0)
+		pressKey(t, controller, 0, termbox.KeyArrowRight)
+		trailSymbol := controller.theme.GetTile("trail").Symbol
+		props := mapStateModelToGameSceneProps(controller.state, controller.translator, controller.theme, controller.cfg.ShowsTrail, false)
+		if props.FieldCells[1][1].Symbol == trailSymbol {
+			t.Fatal("表示している")
+		}
+		pressKey(t, controller, 't', 0)
+		props = mapStateModelToGameSceneProps(controller.state, controller.translator, controller.theme, controller.cfg.ShowsTrail, false)
+		if !controller.cfg.ShowsTrail {
+			t.Fatal("切り替わっていない")
+		} else if props.FieldCells[1][1].Symbol != trailSymbol || props.FieldCells[1][2].Symbol == trailSymbol {
+			t.Fatal("軌跡が違う")
+		}
+	})
+}
+
+func TestController_Breadcrumbs_NotTD(t *testing.T) {
+	towerMode := createCorridorTowerMode(t)
+	startGame := func() *Controller {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		controller.AddGameMode(towerMode)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 's', 0)
+		return controller
+	}
+
+	t.Run("mで主人公のマスに目印を置き、もう一度で拾う", func(t *testing.T) {
+		controller := startGame()
+		breadcrumbSymbol := controller.theme.GetTile("breadcrumb").Symbol
+		pressKey(t, controller, 'm', 0)
+		pressKey(t, controller, 0, termbox.KeyArrowRight)
+		props := mapStateModelToGameSceneProps(controller.state, controller.translator, controller.theme, false, false)
+		if props.FieldCells[1][1].Symbol != breadcrumbSymbol {
+			t.Fatal("目印を置いていない")
+		}
+		pressKey(t, controller, 0, termbox.KeyArrowLeft)
+		pressKey(t, controller, 'm', 0)
+		pressKey(t, controller, 0, termbox.KeyArrowRight)
+		props = mapStateModelToGameSceneProps(controller.state, controller.translator, controller.theme, false, false)
+		if props.FieldCells[1][1].Symbol == breadcrumbSymbol {
+			t.Fatal("目印を拾っていない")
+		}
+	})
+
+	t.Run("禅モードで一手戻しても同じ階の目印は残る", func(t *testing.T) {
+		controller, _ := CreateController(24, 80, config.CreateDefaultConfig())
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 0, termbox.KeyArrowUp)
+		pressKey(t, controller, 0, termbox.KeyEnter)
+		pressKey(t, controller, 's', 0)
+		pressKey(t, controller, 0, 0)
+		pressKey(t, controller, 0, termbox.KeyArrowRight)
+		pressKey(t, controller, 0, termbox.KeyArrowDown)
+		element, _ := controller.state.GetField().GetElementOfHero()
+		position := element.GetPosition()
+		pressKey(t, controller, 'm', 0)
+		pressKey(t, controller, 'u', 0)
+		if element, _ := controller.state.GetField().At(position); !element.IsObjectEmpty() {
+			t.Fatal("戻っていない")
+		} else if element.GetOverlayClass() != "breadcrumb" {
+			t.Fatal("目印が消えている")
+		}
+	})
+
+	t.Run("訪れた行き止まりを塗る", func(t *testing.T) {
+		controller := startGame()
+		deadEndSymbol := controller.theme.GetTile("deadEnd").Symbol
+		pressKey(t, controller, 0, termbox.KeyArrowRight)
+		props := mapStateModelToGameSceneProps(controller.state, controller.translator, controller.theme, false, true)
+		if props.FieldCells[1][1].Symbol != deadEndSymbol {
+			t.Fatal("塗っていない")
+		} else if props.FieldCells[1][3].Symbol == deadEndSymbol {
+			t.Fatal("訪れていないマスを塗っている")
+		}
+		props = mapStateModelToGameSceneProps(controller.state, controller.translator, controller.theme, false, false)
+		if props.FieldCells[1][1].Symbol == deadEndSymbol {
+			t.Fatal("無効なのに塗っている")
+		}
+	})
+}
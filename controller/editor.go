@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/kjirou/tower-of-go/i18n"
+	"github.com/kjirou/tower-of-go/levels"
+	"github.com/kjirou/tower-of-go/models"
+	"github.com/kjirou/tower-of-go/reducers"
+	"github.com/kjirou/tower-of-go/themes"
+	"github.com/kjirou/tower-of-go/utils"
+	"github.com/kjirou/tower-of-go/views"
+	"github.com/nsf/termbox-go"
+	"github.com/pkg/errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The size of a new map, it is the same as the first floor of the normal difficulty.
+const (
+	blankLevelRowLength = 13
+	blankLevelColumnLength = 21
+)
+
+func mapEditorTileToTileName(tile models.EditorTile) string {
+	switch tile {
+	case models.EditorTileWall:
+		return "wall"
+	case models.EditorTileStart:
+		return "hero"
+	case models.EditorTileUpstairs:
+		return "upstairs"
+	}
+	return "floor"
+}
+
+func mapEditorTileToMessageKey(tile models.EditorTile) string {
+	switch tile {
+	case models.EditorTileWall:
+		return "editor.tile.wall"
+	case models.EditorTileStart:
+		return "editor.tile.start"
+	case models.EditorTileUpstairs:
+		return "editor.tile.upstairs"
+	}
+	return "editor.tile.floor"
+}
+
+func mapStateModelToEditorSceneProps(
+	state *models.State, translator *i18n.Translator, theme *themes.Theme) *views.EditorSceneProps {
+	editor := state.GetEditor()
+	field := editor.GetField()
+	startPosition := field.GetStartPosition()
+	upstairsPosition := field.GetUpstairsPosition()
+	cursor := editor.GetCursor()
+
+	// Cells of the field, the start is shown as the hero.
+	fieldRowLength := field.MeasureRowLength()
+	fieldColumnLength := field.MeasureColumnLength()
+	fieldCells := make([][]*views.ScreenCellProps, fieldRowLength)
+	for y := 0; y < fieldRowLength; y++ {
+		cellsRow := make([]*views.ScreenCellProps, fieldColumnLength)
+		for x := 0; x < fieldColumnLength; x++ {
+			fieldElement, _ := field.At(&utils.MatrixPosition{Y: y, X: x})
+			tileName := "floor"
+			switch {
+			case y == startPosition.Y && x == startPosition.X:
+				tileName = "hero"
+			case y == upstairsPosition.Y && x == upstairsPosition.X:
+				tileName = "upstairs"
+			case fieldElement.GetObjectClass() == "wall":
+				tileName = "wall"
+			}
+			cellsRow[x] = mapTileNameToScreenCellProps(tileName, theme)
+			if y == cursor.Y && x == cursor.X {
+				cellsRow[x].Foreground |= termbox.AttrReverse
+			}
+		}
+		fieldCells[y] = cellsRow
+	}
+
+	paletteItems := make([]*views.EditorPaletteItemProps, 0)
+	for i, tile := range models.EditorTiles {
+		paletteItems = append(paletteItems, &views.EditorPaletteItemProps{
+			Tile: mapTileNameToScreenCellProps(mapEditorTileToTileName(tile), theme),
+			Label: fmt.Sprintf("%d: %s", i + 1, translator.Translate(mapEditorTileToMessageKey(tile))),
+		})
+	}
+
+	props := &views.EditorSceneProps{
+		FieldCells: fieldCells,
+		Heading: translator.Translate("editor.heading", editor.GetName(), editor.GetFilePath()),
+		PaletteItems: paletteItems,
+		SelectedTileIndex: editor.GetSelectedTileIndex(),
+		Hint: translator.Translate("hint.editor"),
+	}
+	if notice := editor.GetNotice(); notice != nil {
+		props.Notice = translator.Translate(notice.MessageKey, notice.Detail)
+		props.NoticeIsError = notice.IsError
+	}
+	return props
+}
+
+// The floor of the editor as a level. The field is cloned, so the level is not changed by the editing.
+func createLevelOfEditor(editor *models.Editor) *levels.Level {
+	return &levels.Level{
+		Name: editor.GetName(),
+		TimeLimit: editor.GetTimeLimit(),
+		Field: editor.GetField().Clone(),
+	}
+}
+
+// Returns the notice of the check, and whether the floor can be cleared.
+func checkFloorOfEditor(editor *models.Editor) (*models.EditorNotice, bool) {
+	field := editor.GetField()
+	if err := levels.ValidateField(field); err != nil {
+		return &models.EditorNotice{MessageKey: "editor.invalid", Detail: err.Error(), IsError: true}, false
+	}
+	pathLength := field.MeasureShortestPathLength(field.GetStartPosition(), field.GetUpstairsPosition())
+	return &models.EditorNotice{MessageKey: "editor.valid", Detail: fmt.Sprintf("%d", pathLength)}, true
+}
+
+func saveFloorOfEditor(editor *models.Editor) *models.EditorNotice {
+	notice, isValid := checkFloorOfEditor(editor)
+	if !isValid {
+		return notice
+	}
+	if err := levels.SaveLevel(editor.GetFilePath(), createLevelOfEditor(editor)); err != nil {
+		return &models.EditorNotice{MessageKey: "editor.saveFailed", Detail: err.Error(), IsError: true}
+	}
+	return &models.EditorNotice{MessageKey: "editor.saved", Detail: editor.GetFilePath()}
+}
+
+// Open the map file in the editor. A new map is created if the file does not exist.
+func (controller *Controller) OpenEditor(filePath string) error {
+	var level *levels.Level
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		name := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+		level = levels.CreateBlankLevel(name, blankLevelRowLength, blankLevelColumnLength)
+	} else {
+		level, err = levels.LoadLevel(filePath)
+		if err != nil {
+			return err
+		}
+	}
+	editor := models.CreateEditor(filePath, level.Name, level.TimeLimit, level.Field)
+	newState, err := reducers.OpenEditor(*controller.state, 0, editor)
+	if err != nil {
+		return err
+	}
+	controller.Dispatch(newState)
+	return nil
+}
+
+func (controller *Controller) playtestFloorOfEditor(
+	state models.State, elapsedTime time.Duration) (*models.State, error) {
+	editor := state.GetEditor()
+	if notice, isValid := checkFloorOfEditor(editor); !isValid {
+		return reducers.SetEditorNotice(state, elapsedTime, notice)
+	}
+	mode, err := levels.CreateTowerMode(editor.GetName(), []*levels.Level{createLevelOfEditor(editor)})
+	if err != nil {
+		return &state, errors.WithStack(err)
+	}
+	setup, err := controller.createGameSetup(mode)
+	if err != nil {
+		return &state, err
+	}
+	// The best run of a saved map is not raced, because the floor may have been changed.
+	setup.Ghost = nil
+	return reducers.PlaytestEditorFloor(state, elapsedTime, setup)
+}
+
+func (controller *Controller) handleEditorScene(
+	ch rune, key termbox.Key, elapsedTime time.Duration) (*models.State, error) {
+	state := *controller.state
+	switch {
+	// Move the cursor.
+	case key == termbox.KeyArrowUp || ch == 'k':
+		return reducers.MoveEditorCursor(state, elapsedTime, reducers.FourDirectionUp)
+	case key == termbox.KeyArrowRight || ch == 'l':
+		return reducers.MoveEditorCursor(state, elapsedTime, reducers.FourDirectionRight)
+	case key == termbox.KeyArrowDown || ch == 'j':
+		return reducers.MoveEditorCursor(state, elapsedTime, reducers.FourDirectionDown)
+	case key == termbox.KeyArrowLeft || ch == 'h':
+		return reducers.MoveEditorCursor(state, elapsedTime, reducers.FourDirectionLeft)
+	// Select a tile of the palette.
+	case key == termbox.KeyTab:
+		return reducers.CycleEditorTile(state, elapsedTime, 1)
+	case ch >= '1' && int(ch - '1') < len(models.EditorTiles):
+		return reducers.SelectEditorTile(state, elapsedTime, int(ch - '1'))
+	case key == termbox.KeySpace || key == termbox.KeyEnter:
+		return reducers.PlaceEditorTile(state, elapsedTime)
+	case ch == 'c':
+		notice, _ := checkFloorOfEditor(state.GetEditor())
+		return reducers.SetEditorNotice(state, elapsedTime, notice)
+	case ch == 'w':
+		return reducers.SetEditorNotice(state, elapsedTime, saveFloorOfEditor(state.GetEditor()))
+	case ch == 'p':
+		return controller.playtestFloorOfEditor(state, elapsedTime)
+	}
+	return reducers.AdvanceOnlyTime(state, elapsedTime)
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/kjirou/tower-of-go/models"
+	"github.com/kjirou/tower-of-go/reducers"
+	"github.com/kjirou/tower-of-go/themes"
+	"github.com/kjirou/tower-of-go/utils"
+	"github.com/kjirou/tower-of-go/views"
+	"github.com/nsf/termbox-go"
+	"time"
+)
+
+// The number of the visits that has the hottest color.
+const maxHeatLevel = 4
+
+// The number of the visits is written in the cell, so that it is also read without colors.
+func mapVisitCountToScreenCellProps(count int, theme *themes.Theme) *views.ScreenCellProps {
+	level := count
+	if level > maxHeatLevel {
+		level = maxHeatLevel
+	}
+	symbol := '+'
+	if count < 10 {
+		symbol = rune('0' + count)
+	}
+	return &views.ScreenCellProps{
+		Symbol: symbol,
+		Foreground: theme.GetColor("background"),
+		Background: theme.GetColor(fmt.Sprintf("heat.%d", level)),
+	}
+}
+
+func createHeatmapLegendCells(theme *themes.Theme) []*views.ScreenCellProps {
+	cells := make([]*views.ScreenCellProps, 0)
+	for count := 1; count <= maxHeatLevel; count++ {
+		cell := mapVisitCountToScreenCellProps(count, theme)
+		if count == maxHeatLevel {
+			cell.Symbol = '+'
+		}
+		cells = append(cells, cell)
+	}
+	return cells
+}
+
+// The menu cursor selects a floor. It starts from the last floor, that is the one at the end of the game.
+func (controller *Controller) mapStateModelToHeatmapSceneProps(state *models.State) *views.HeatmapSceneProps {
+	translator := controller.translator
+	theme := controller.theme
+	game := state.GetGame()
+	floorCount := game.CountKeptFloorFields()
+	props := &views.HeatmapSceneProps{
+		FieldCells: [][]*views.ScreenCellProps{},
+		LegendCells: createHeatmapLegendCells(theme),
+		Hint: translator.Translate("hint.heatmap"),
+	}
+	if floorCount == 0 {
+		props.Heading = translator.Translate("heading.heatmap")
+		return props
+	}
+	floorNumber := (floorCount - 1 + state.GetMenuCursorIndex()) % floorCount + 1
+	field := game.GetFloorField(floorNumber)
+	visitedPositions := game.ListVisitedPositions(floorNumber)
+	visitCounts := models.CountVisits(visitedPositions)
+
+	fieldRowLength := field.MeasureRowLength()
+	fieldColumnLength := field.MeasureColumnLength()
+	fieldCells := make([][]*views.ScreenCellProps, fieldRowLength)
+	for y := 0; y < fieldRowLength; y++ {
+		cellsRow := make([]*views.ScreenCellProps, fieldColumnLength)
+		for x := 0; x < fieldColumnLength; x++ {
+			position := utils.MatrixPosition{Y: y, X: x}
+			fieldElement, _ := field.At(&position)
+			count := visitCounts[position]
+			switch {
+			case count > 0 && fieldElement.GetFloorObjectClass() == "upstairs":
+				cellsRow[x] = mapTileNameToScreenCellProps("upstairs", theme)
+				cellsRow[x].Background = mapVisitCountToScreenCellProps(count, theme).Background
+			case count > 0:
+				cellsRow[x] = mapVisitCountToScreenCellProps(count, theme)
+			// The hero of the kept field is at the start, it is not drawn.
+			case fieldElement.GetObjectClass() == "hero":
+				cellsRow[x] = mapTileNameToScreenCellProps("floor", theme)
+			default:
+				cellsRow[x] = mapFieldElementToScreenCellProps(fieldElement, theme)
+			}
+		}
+		fieldCells[y] = cellsRow
+	}
+
+	revisitedCellCount := 0
+	for _, count := range visitCounts {
+		if count > 1 {
+			revisitedCellCount++
+		}
+	}
+	moveCount := 0
+	if len(visitedPositions) > 0 {
+		moveCount = len(visitedPositions) - 1
+	}
+	props.Heading = translator.Translate("heading.heatmapOfFloor", floorNumber, floorCount)
+	props.FieldCells = fieldCells
+	props.Rows = []*views.ResultsRowProps{
+		&views.ResultsRowProps{
+			Label: translator.Translate("heatmap.moves"),
+			Value: fmt.Sprintf("%d", moveCount),
+		},
+		&views.ResultsRowProps{
+			Label: translator.Translate("heatmap.shortestPath"),
+			Value: fmt.Sprintf("%d", field.MeasureShortestPathLength(field.GetStartPosition(), field.GetUpstairsPosition())),
+		},
+		&views.ResultsRowProps{
+			Label: translator.Translate("heatmap.visitedCells"),
+			Value: fmt.Sprintf("%d", len(visitCounts)),
+		},
+		&views.ResultsRowProps{
+			Label: translator.Translate("heatmap.revisitedCells"),
+			Value: fmt.Sprintf("%d", revisitedCellCount),
+		},
+	}
+	return props
+}
+
+func (controller *Controller) handleHeatmapScene(
+	ch rune, key termbox.Key, elapsedTime time.Duration) (*models.State, error) {
+	state := *controller.state
+	floorCount := state.GetGame().CountKeptFloorFields()
+	switch {
+	case key == termbox.KeyArrowLeft || key == termbox.KeyArrowUp || ch == 'h' || ch == 'k':
+		return reducers.MoveMenuCursor(state, elapsedTime, -1, floorCount)
+	case key == termbox.KeyArrowRight || key == termbox.KeyArrowDown || ch == 'l' || ch == 'j':
+		return reducers.MoveMenuCursor(state, elapsedTime, 1, floorCount)
+	}
+	return reducers.AdvanceOnlyTime(state, elapsedTime)
+}
@@ -0,0 +1,345 @@
+package controller
+
+import (
+	"github.com/kjirou/tower-of-go/config"
+	"github.com/kjirou/tower-of-go/i18n"
+	"github.com/kjirou/tower-of-go/models"
+	"github.com/kjirou/tower-of-go/reducers"
+	"github.com/kjirou/tower-of-go/themes"
+	"github.com/kjirou/tower-of-go/views"
+	"github.com/nsf/termbox-go"
+	"github.com/pkg/errors"
+	"sort"
+	"time"
+)
+
+type menuItem struct {
+	label string
+	// Called when the item is decided.
+	decide func(state models.State, elapsedTime time.Duration) (*models.State, error)
+	// Called when the value of the item is changed with the left or right key. The delta is -1 or 1.
+	// If the item does not have "decide", the decision key also changes the value.
+	cycle func(delta int) error
+}
+
+func findNextOption(options []string, current string, delta int) string {
+	index := 0
+	for i, option := range options {
+		if option == current {
+			index = i
+		}
+	}
+	return options[((index + delta) % len(options) + len(options)) % len(options)]
+}
+
+// Apply the changed config to the screen and save it.
+func (controller *Controller) changeConfig(change func(cfg *config.Config)) error {
+	cfg := *controller.cfg
+	change(&cfg)
+	err := controller.applyConfig(&cfg)
+	if err != nil {
+		return err
+	}
+	controller.screen = views.CreateScreen(
+		controller.screen.MeasureRowLength(),
+		controller.screen.MeasureColumnLength(),
+		controller.translator,
+		controller.theme,
+	)
+	return config.SaveConfig(&cfg)
+}
+
+func (controller *Controller) cycleLanguage(delta int) error {
+	language := findNextOption(i18n.GetSupportedLanguages(), controller.translator.GetLanguage(), delta)
+	return controller.changeConfig(func(cfg *config.Config) {
+		cfg.Language = language
+	})
+}
+
+func (controller *Controller) cycleTheme(delta int) error {
+	nameSet := make(map[string]bool)
+	for _, name := range themes.GetBuiltinThemeNames() {
+		nameSet[name] = true
+	}
+	for name := range controller.cfg.Themes {
+		nameSet[name] = true
+	}
+	names := make([]string, 0)
+	for name := range nameSet {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	themeName := findNextOption(names, controller.theme.GetName(), delta)
+	return controller.changeConfig(func(cfg *config.Config) {
+		cfg.Theme = themeName
+	})
+}
+
+func (controller *Controller) cycleDifficulty(delta int) error {
+	difficultyName := findNextOption(models.DifficultyNames, controller.difficulty.GetName(), delta)
+	return controller.changeConfig(func(cfg *config.Config) {
+		cfg.Difficulty = difficultyName
+	})
+}
+
+// Flip a switch of the display such as the trail.
+// It does not change the screen or the difficulty, so the config is only saved.
+func (controller *Controller) toggleSetting(setting func(cfg *config.Config) *bool) error {
+	cfg := *controller.cfg
+	*setting(&cfg) = !*setting(&cfg)
+	controller.cfg = &cfg
+	return config.SaveConfig(&cfg)
+}
+
+func (controller *Controller) toggleTrail(delta int) error {
+	return controller.toggleSetting(func(cfg *config.Config) *bool {
+		return &cfg.ShowsTrail
+	})
+}
+
+func (controller *Controller) toggleDeadEnds(delta int) error {
+	return controller.toggleSetting(func(cfg *config.Config) *bool {
+		return &cfg.ShadesDeadEnds
+	})
+}
+
+func (controller *Controller) createMenuItems(scene models.Scene) []*menuItem {
+	translator := controller.translator
+	switch scene {
+	case models.SceneTitle:
+		return []*menuItem{
+			&menuItem{
+				label: translator.Translate("menu.start"),
+				decide: func(state models.State, elapsedTime time.Duration) (*models.State, error) {
+					return reducers.PushScene(state, elapsedTime, models.SceneModeSelect)
+				},
+			},
+			&menuItem{
+				label: translator.Translate("menu.settings"),
+				decide: func(state models.State, elapsedTime time.Duration) (*models.State, error) {
+					return reducers.PushScene(state, elapsedTime, models.SceneSettings)
+				},
+			},
+			&menuItem{
+				label: translator.Translate("menu.achievements"),
+				decide: func(state models.State, elapsedTime time.Duration) (*models.State, error) {
+					return reducers.PushScene(state, elapsedTime, models.SceneAchievements)
+				},
+			},
+			&menuItem{
+				label: translator.Translate("menu.stats"),
+				decide: func(state models.State, elapsedTime time.Duration) (*models.State, error) {
+					return reducers.PushScene(state, elapsedTime, models.SceneStats)
+				},
+			},
+			&menuItem{
+				label: translator.Translate("menu.quit"),
+				decide: func(state models.State, elapsedTime time.Duration) (*models.State, error) {
+					controller.isQuitRequested = true
+					return reducers.AdvanceOnlyTime(state, elapsedTime)
+				},
+			},
+		}
+	case models.SceneModeSelect:
+		modes := append([]models.GameMode{}, controller.extraGameModes...)
+		for _, modeName := range models.GameModeNames {
+			mode, _ := models.CreateGameMode(modeName)
+			modes = append(modes, mode)
+		}
+		items := make([]*menuItem, 0)
+		for _, mode := range modes {
+			mode := mode
+			label := translator.Translate("mode." + mode.GetName())
+			if customTowerMode, ok := mode.(*models.CustomTowerMode); ok {
+				label = translator.Translate("mode.customWithName", customTowerMode.GetTowerName())
+			}
+			items = append(items, &menuItem{
+				label: label,
+				decide: func(state models.State, elapsedTime time.Duration) (*models.State, error) {
+					setup, err := controller.createGameSetup(mode)
+					if err != nil {
+						return &state, err
+					}
+					return reducers.EnterGameScene(state, elapsedTime, setup)
+				},
+			})
+		}
+		return items
+	// The items only show the achievements, they can not be decided.
+	case models.SceneAchievements:
+		items := make([]*menuItem, 0)
+		for _, name := range models.AchievementNames {
+			labelKey := "achievement.locked"
+			if controller.achievementStore.IsUnlocked(name) {
+				labelKey = "achievement.unlocked"
+			}
+			items = append(items, &menuItem{
+				label: translator.Translate(
+					labelKey,
+					translator.Translate("achievement." + name + ".title"),
+					translator.Translate("achievement." + name + ".description"),
+				),
+			})
+		}
+		return items
+	case models.SceneSettings:
+		trailKey := "settings.off"
+		if controller.cfg.ShowsTrail {
+			trailKey = "settings.on"
+		}
+		deadEndsKey := "settings.off"
+		if controller.cfg.ShadesDeadEnds {
+			deadEndsKey = "settings.on"
+		}
+		return []*menuItem{
+			&menuItem{
+				label: translator.Translate(
+					"settings.language", translator.Translate("language." + translator.GetLanguage())),
+				cycle: controller.cycleLanguage,
+			},
+			&menuItem{
+				label: translator.Translate("settings.theme", controller.theme.GetName()),
+				cycle: controller.cycleTheme,
+			},
+			&menuItem{
+				label: translator.Translate(
+					"settings.difficulty", translator.Translate("difficulty." + controller.difficulty.GetName())),
+				cycle: controller.cycleDifficulty,
+			},
+			&menuItem{
+				label: translator.Translate("settings.trail", translator.Translate(trailKey)),
+				cycle: controller.toggleTrail,
+			},
+			&menuItem{
+				label: translator.Translate("settings.deadEnds", translator.Translate(deadEndsKey)),
+				cycle: controller.toggleDeadEnds,
+			},
+			&menuItem{
+				label: translator.Translate("menu.back"),
+				decide: reducers.PopScene,
+			},
+		}
+	}
+	return []*menuItem{}
+}
+
+func (controller *Controller) mapStateModelToMenuSceneProps(state *models.State) *views.MenuSceneProps {
+	translator := controller.translator
+	scene := state.GetCurrentScene()
+	items := controller.createMenuItems(scene)
+	labels := make([]string, 0)
+	for _, item := range items {
+		labels = append(labels, item.label)
+	}
+	props := &views.MenuSceneProps{
+		Items: labels,
+		CursorIndex: state.GetMenuCursorIndex(),
+		Hint: translator.Translate("hint.menu"),
+	}
+	switch scene {
+	case models.SceneTitle:
+		props.Heading = translator.Translate("heading.title")
+		props.Hint = translator.Translate("hint.title")
+	case models.SceneModeSelect:
+		props.Heading = translator.Translate("heading.modeSelect")
+		if !controller.dailyStartedAt.IsZero() {
+			props.Heading = translator.Translate(
+				"heading.daily", models.FormatDailyDate(controller.dailyStartedAt))
+		}
+	case models.SceneSettings:
+		props.Heading = translator.Translate("heading.settings")
+		props.Hint = translator.Translate("hint.settings")
+	case models.SceneAchievements:
+		unlockedCount := 0
+		for _, name := range models.AchievementNames {
+			if controller.achievementStore.IsUnlocked(name) {
+				unlockedCount++
+			}
+		}
+		props.Heading = translator.Translate("heading.achievements", unlockedCount, len(models.AchievementNames))
+		props.Hint = translator.Translate("hint.achievements")
+	}
+	return props
+}
+
+func (controller *Controller) handleMenuScene(
+	ch rune, key termbox.Key, elapsedTime time.Duration) (*models.State, error) {
+	state := *controller.state
+	items := controller.createMenuItems(state.GetCurrentScene())
+	if len(items) == 0 {
+		return reducers.AdvanceOnlyTime(state, elapsedTime)
+	}
+	item := items[state.GetMenuCursorIndex() % len(items)]
+
+	cycleDelta := 0
+	switch {
+	case key == termbox.KeyArrowUp || ch == 'k':
+		return reducers.MoveMenuCursor(state, elapsedTime, -1, len(items))
+	case key == termbox.KeyArrowDown || ch == 'j':
+		return reducers.MoveMenuCursor(state, elapsedTime, 1, len(items))
+	case key == termbox.KeyArrowLeft || ch == 'h':
+		cycleDelta = -1
+	case key == termbox.KeyArrowRight || ch == 'l':
+		cycleDelta = 1
+	case key == termbox.KeyEnter || key == termbox.KeySpace:
+		if item.decide != nil {
+			return item.decide(state, elapsedTime)
+		}
+		cycleDelta = 1
+	}
+	if cycleDelta != 0 && item.cycle != nil {
+		err := item.cycle(cycleDelta)
+		if err != nil {
+			return &state, errors.WithStack(err)
+		}
+	}
+	return reducers.AdvanceOnlyTime(state, elapsedTime)
+}
+
+func (controller *Controller) handleGameScene(
+	ch rune, key termbox.Key, elapsedTime time.Duration) (*models.State, error) {
+	state := *controller.state
+	switch {
+	// Any key restarts the paused game, it is not applied to the game.
+	case state.IsPaused():
+		if ch != 0 || key != 0 {
+			return reducers.ResumeGame(state, elapsedTime)
+		}
+	// Start or restart a game.
+	case ch == 's':
+		return reducers.StartOrRestartGame(state, elapsedTime)
+	// Show or hide the cells that the hero has visited.
+	case ch == 't':
+		if err := controller.toggleTrail(1); err != nil {
+			return &state, err
+		}
+		return reducers.AdvanceOnlyTime(state, elapsedTime)
+	// Mark the cell of the hero, to remember the way.
+	case ch == 'm':
+		return reducers.ToggleBreadcrumb(state, elapsedTime)
+	// Go back to the previous move, in a mode for practice.
+	case ch == 'u' || key == termbox.KeyBackspace || key == termbox.KeyBackspace2:
+		return reducers.RewindGame(state, elapsedTime)
+	// Move the hero.
+	case key == termbox.KeyArrowUp || ch == 'k':
+		return reducers.WalkHero(state, elapsedTime, reducers.FourDirectionUp)
+	case key == termbox.KeyArrowRight || ch == 'l':
+		return reducers.WalkHero(state, elapsedTime, reducers.FourDirectionRight)
+	case key == termbox.KeyArrowDown || ch == 'j':
+		return reducers.WalkHero(state, elapsedTime, reducers.FourDirectionDown)
+	case key == termbox.KeyArrowLeft || ch == 'h':
+		return reducers.WalkHero(state, elapsedTime, reducers.FourDirectionLeft)
+	}
+	return reducers.AdvanceOnlyTime(state, elapsedTime)
+}
+
+func (controller *Controller) handleResultsScene(
+	ch rune, key termbox.Key, elapsedTime time.Duration) (*models.State, error) {
+	state := *controller.state
+	if key == termbox.KeyEnter || key == termbox.KeySpace || ch == 's' {
+		return reducers.RetryGame(state, elapsedTime)
+	} else if ch == 'v' {
+		return reducers.PushScene(state, elapsedTime, models.SceneHeatmap)
+	}
+	return reducers.AdvanceOnlyTime(state, elapsedTime)
+}
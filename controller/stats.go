@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/kjirou/tower-of-go/i18n"
+	"github.com/kjirou/tower-of-go/models"
+	"github.com/kjirou/tower-of-go/reducers"
+	"github.com/kjirou/tower-of-go/stats"
+	"github.com/kjirou/tower-of-go/views"
+	"github.com/mattn/go-runewidth"
+	"github.com/nsf/termbox-go"
+	"github.com/pkg/errors"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// The sparklines show the last games that fit in a row.
+	statsSparklineLength = 40
+	// The number of games of the mean score, it is compared with the games before them.
+	statsMeanGameCount = 10
+	statsHistogramBinCount = 6
+	statsHistogramBarLength = 24
+)
+
+func formatStatsScore(scoreUnit models.ScoreUnit, score float64, translator *i18n.Translator) string {
+	if scoreUnit == models.ScoreUnitSeconds {
+		return translator.Translate("unit.seconds", score)
+	}
+	return translator.Translate("unit.floors", score)
+}
+
+// The rows of the games of a mode and a difficulty.
+// The trends of the scores and the histogram of the floor times tell whether the player is improving.
+func createStatsRows(group *stats.GameGroup, translator *i18n.Translator) []*views.ResultsRowProps {
+	games := group.Games
+	lastGame := games[len(games)-1]
+	scores := make([]float64, 0)
+	efficiencies := make([]float64, 0)
+	for _, game := range games {
+		scores = append(scores, game.Score)
+		efficiencies = append(efficiencies, game.CalculateEfficiency())
+	}
+	bestTrend := stats.CalculateBestTrend(games)
+	format := func(score float64) string {
+		return formatStatsScore(lastGame.ScoreUnit, score, translator)
+	}
+
+	recentGames := games
+	previousGames := []*stats.GameRecord{}
+	if len(games) > statsMeanGameCount {
+		recentGames = games[len(games)-statsMeanGameCount:]
+		previousGames = games[:len(games)-statsMeanGameCount]
+		if len(previousGames) > statsMeanGameCount {
+			previousGames = previousGames[len(previousGames)-statsMeanGameCount:]
+		}
+	}
+	mean := format(stats.CalculateMeanScore(recentGames))
+	if len(previousGames) > 0 {
+		mean = translator.Translate("stats.meanWithPrevious", mean, format(stats.CalculateMeanScore(previousGames)))
+	}
+
+	rows := []*views.ResultsRowProps{
+		&views.ResultsRowProps{
+			Label: translator.Translate("stats.games"),
+			Value: fmt.Sprintf("%d", len(games)),
+		},
+		&views.ResultsRowProps{
+			Label: translator.Translate("stats.scores"),
+			Value: views.FormatSparkline(scores, statsSparklineLength) + "  " + format(lastGame.Score),
+		},
+		&views.ResultsRowProps{
+			Label: translator.Translate("stats.best"),
+			Value: views.FormatSparkline(bestTrend, statsSparklineLength) + "  " + format(bestTrend[len(bestTrend)-1]),
+		},
+		&views.ResultsRowProps{
+			Label: translator.Translate("stats.mean", statsMeanGameCount),
+			Value: mean,
+		},
+		&views.ResultsRowProps{
+			Label: translator.Translate("stats.efficiency"),
+			Value: fmt.Sprintf("%s  %.0f%%",
+				views.FormatSparkline(efficiencies, statsSparklineLength), lastGame.CalculateEfficiency() * 100),
+		},
+	}
+
+	bins := stats.CalculateFloorTimeHistogram(games, statsHistogramBinCount)
+	if len(bins) > 0 {
+		rows = append(rows, &views.ResultsRowProps{}, &views.ResultsRowProps{Label: translator.Translate("stats.floorTimes")})
+		maxCount := 0
+		for _, bin := range bins {
+			if bin.Count > maxCount {
+				maxCount = bin.Count
+			}
+		}
+		for _, bin := range bins {
+			rows = append(rows, &views.ResultsRowProps{
+				Label: translator.Translate("stats.timeRange", bin.Min.Seconds(), bin.Max.Seconds()),
+				Value: fmt.Sprintf("%s %d",
+					views.FormatBar(float64(bin.Count), float64(maxCount), statsHistogramBarLength), bin.Count),
+			})
+		}
+	}
+	return rows
+}
+
+// The menu cursor selects a group of the games.
+func (controller *Controller) mapStateModelToStatsSceneProps(state *models.State) *views.StatsSceneProps {
+	translator := controller.translator
+	groups := controller.statsStore.ListGroups()
+	if len(groups) == 0 {
+		return &views.StatsSceneProps{
+			Heading: translator.Translate("heading.stats"),
+			Rows: []*views.ResultsRowProps{&views.ResultsRowProps{Label: translator.Translate("stats.empty")}},
+			Hint: translator.Translate("hint.stats"),
+		}
+	}
+	index := state.GetMenuCursorIndex() % len(groups)
+	group := groups[index]
+	return &views.StatsSceneProps{
+		Heading: translator.Translate(
+			"heading.statsOfGroup",
+			translator.Translate("mode." + group.Mode),
+			translator.Translate("difficulty." + group.Difficulty),
+			index + 1,
+			len(groups),
+		),
+		Rows: createStatsRows(group, translator),
+		Hint: translator.Translate("hint.stats"),
+	}
+}
+
+func (controller *Controller) handleStatsScene(
+	ch rune, key termbox.Key, elapsedTime time.Duration) (*models.State, error) {
+	state := *controller.state
+	groupCount := len(controller.statsStore.ListGroups())
+	switch {
+	case key == termbox.KeyArrowLeft || key == termbox.KeyArrowUp || ch == 'h' || ch == 'k':
+		return reducers.MoveMenuCursor(state, elapsedTime, -1, groupCount)
+	case key == termbox.KeyArrowRight || key == termbox.KeyArrowDown || ch == 'l' || ch == 'j':
+		return reducers.MoveMenuCursor(state, elapsedTime, 1, groupCount)
+	}
+	return reducers.AdvanceOnlyTime(state, elapsedTime)
+}
+
+// Write the same rows as the stats scene for every group of the games, as plain text.
+func WriteStats(writer io.Writer, store *stats.Store, translator *i18n.Translator) error {
+	groups := store.ListGroups()
+	if len(groups) == 0 {
+		_, err := fmt.Fprintln(writer, translator.Translate("stats.empty"))
+		return errors.WithStack(err)
+	}
+	for i, group := range groups {
+		if i > 0 {
+			fmt.Fprintln(writer)
+		}
+		fmt.Fprintf(writer, "%s / %s\n",
+			translator.Translate("mode." + group.Mode), translator.Translate("difficulty." + group.Difficulty))
+		rows := createStatsRows(group, translator)
+		labelColumnLength := 0
+		for _, row := range rows {
+			if width := runewidth.StringWidth(row.Label); width > labelColumnLength {
+				labelColumnLength = width
+			}
+		}
+		for _, row := range rows {
+			if row.Label == "" && row.Value == "" {
+				fmt.Fprintln(writer)
+				continue
+			}
+			line := strings.TrimRight("  " + runewidth.FillRight(row.Label, labelColumnLength) + "  " + row.Value, " ")
+			if _, err := fmt.Fprintln(writer, line); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+	return nil
+}
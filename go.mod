@@ -3,7 +3,7 @@ module github.com/kjirou/tower-of-go
 go 1.14
 
 require (
-	github.com/mattn/go-runewidth v0.0.9 // indirect
+	github.com/mattn/go-runewidth v0.0.9
 	github.com/nsf/termbox-go v0.0.0-20200204031403-4d2b513ad8be
 	github.com/pkg/errors v0.9.1
 )
@@ -0,0 +1,145 @@
+package highscores
+
+//
+// The "highscores" package keeps the scores of the daily challenges in a JSON file.
+// The file is optional, it is created when the first score is recorded.
+//
+
+import (
+	"encoding/json"
+	"github.com/kjirou/tower-of-go/models"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// The best score of a daily challenge. There is one per date, mode and difficulty.
+type DailyScore struct {
+	// In the format of models.DailyDateLayout.
+	Date string `json:"date"`
+	Mode string `json:"mode"`
+	Difficulty string `json:"difficulty"`
+	Score float64 `json:"score"`
+	Points int `json:"points"`
+	ScoreUnit models.ScoreUnit `json:"scoreUnit"`
+}
+
+// Returns the score of the finished game.
+func CreateDailyScore(date string, game *models.Game, executionTime time.Duration) *DailyScore {
+	return &DailyScore{
+		Date: date,
+		Mode: game.GetMode().GetName(),
+		Difficulty: game.GetDifficulty().GetName(),
+		Score: game.CalculateScore(executionTime),
+		Points: game.CalculateScoreBreakdown().CalculateTotal(),
+		ScoreUnit: game.GetMode().GetScoreUnit(),
+	}
+}
+
+func (dailyScore *DailyScore) IsBetterThan(other *DailyScore) bool {
+	return other == nil ||
+		models.IsBetterScore(dailyScore.ScoreUnit, dailyScore.Score, dailyScore.Points, other.Score, other.Points)
+}
+
+type Store struct {
+	// The file that the store was loaded from. It is also the destination of saving.
+	filePath string
+	// In order of recording.
+	Dailies []*DailyScore `json:"dailies"`
+}
+
+// Returns nil if the daily challenge has not been finished.
+func (store *Store) FindDaily(date string, mode string, difficulty string) *DailyScore {
+	for _, dailyScore := range store.Dailies {
+		if dailyScore.Date == date && dailyScore.Mode == mode && dailyScore.Difficulty == difficulty {
+			return dailyScore
+		}
+	}
+	return nil
+}
+
+// Keep the score if it is better than the one of the same date, mode and difficulty. Returns whether it is kept.
+func (store *Store) SubmitDaily(dailyScore *DailyScore) bool {
+	for i, recorded := range store.Dailies {
+		if recorded.Date == dailyScore.Date && recorded.Mode == dailyScore.Mode &&
+			recorded.Difficulty == dailyScore.Difficulty {
+			if !dailyScore.IsBetterThan(recorded) {
+				return false
+			}
+			store.Dailies[i] = dailyScore
+			return true
+		}
+	}
+	store.Dailies = append(store.Dailies, dailyScore)
+	return true
+}
+
+// Returns the number of consecutive days that have a daily score until the date.
+// The streak continues through the date even if the date has no score yet.
+func (store *Store) CalculateStreak(now time.Time) int {
+	dates := make(map[string]bool)
+	for _, dailyScore := range store.Dailies {
+		dates[dailyScore.Date] = true
+	}
+	day := now.UTC()
+	if !dates[models.FormatDailyDate(day)] {
+		day = day.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for dates[models.FormatDailyDate(day)] {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+// Returns "$XDG_CONFIG_HOME/tower-of-go/highscores.json" or the equivalent of the OS.
+func GetDefaultStoreFilePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return filepath.Join(configDir, "tower-of-go", "highscores.json"), nil
+}
+
+// A store that is not saved to a file.
+func CreateStore() *Store {
+	return &Store{Dailies: make([]*DailyScore, 0)}
+}
+
+// Returns an empty store if the file does not exist.
+func LoadStore(filePath string) (*Store, error) {
+	store := CreateStore()
+	store.filePath = filePath
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return store, nil
+	} else if err != nil {
+		return store, errors.WithStack(err)
+	}
+	if err := json.Unmarshal(content, store); err != nil {
+		return store, errors.Wrapf(err, "The high score file (%s) is invalid.", filePath)
+	}
+	return store, nil
+}
+
+// Write the store to the file that it was loaded from.
+// A store that was not loaded from a file is not saved.
+func SaveStore(store *Store) error {
+	if store.filePath == "" {
+		return nil
+	}
+	content, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(store.filePath), 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := ioutil.WriteFile(store.filePath, append(content, '\n'), 0644); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package highscores
+
+import (
+	"github.com/kjirou/tower-of-go/models"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func createDailyScore(date string, score float64) *DailyScore {
+	return &DailyScore{
+		Date: date,
+		Mode: "sprint",
+		Difficulty: "normal",
+		Score: score,
+		ScoreUnit: models.ScoreUnitSeconds,
+	}
+}
+
+func TestStore_SubmitDaily_NotTD(t *testing.T) {
+	t.Run("同じ日付、モード、難易度の得点より良いときだけ置き換える", func(t *testing.T) {
+		store := CreateStore()
+		if !store.SubmitDaily(createDailyScore("2024-01-31", 30)) {
+			t.Fatal("最初の得点を保持しない")
+		} else if store.SubmitDaily(createDailyScore("2024-01-31", 40)) {
+			t.Fatal("悪い得点を保持する")
+		} else if !store.SubmitDaily(createDailyScore("2024-01-31", 20)) {
+			t.Fatal("良い得点を保持しない")
+		} else if len(store.Dailies) != 1 || store.FindDaily("2024-01-31", "sprint", "normal").Score != 20 {
+			t.Fatal("得点が違う")
+		}
+	})
+
+	t.Run("日付が違えば別の得点として保持する", func(t *testing.T) {
+		store := CreateStore()
+		store.SubmitDaily(createDailyScore("2024-01-30", 30))
+		store.SubmitDaily(createDailyScore("2024-01-31", 40))
+		if len(store.Dailies) != 2 {
+			t.Fatal("保持していない")
+		}
+	})
+}
+
+func TestStore_CalculateStreak_NotTD(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("今日まで続く日数を返す", func(t *testing.T) {
+		store := CreateStore()
+		for _, date := range []string{"2024-02-26", "2024-02-28", "2024-02-29", "2024-03-01"} {
+			store.SubmitDaily(createDailyScore(date, 30))
+		}
+		if store.CalculateStreak(now) != 3 {
+			t.Fatal("日数が違う")
+		}
+	})
+
+	t.Run("今日がまだなら、昨日まで続く日数を返す", func(t *testing.T) {
+		store := CreateStore()
+		store.SubmitDaily(createDailyScore("2024-02-29", 30))
+		if store.CalculateStreak(now) != 1 {
+			t.Fatal("日数が違う")
+		}
+	})
+
+	t.Run("昨日も今日もなければ0を返す", func(t *testing.T) {
+		store := CreateStore()
+		store.SubmitDaily(createDailyScore("2024-02-28", 30))
+		if store.CalculateStreak(now) != 0 {
+			t.Fatal("日数が違う")
+		}
+	})
+}
+
+func TestLoadStore_NotTD(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "tower-of-go")
+	defer os.RemoveAll(dir)
+
+	t.Run("ファイルが存在しないとき、空の保管庫を返す", func(t *testing.T) {
+		store, err := LoadStore(filepath.Join(dir, "missing.json"))
+		if err != nil {
+			t.Fatal(err)
+		} else if len(store.Dailies) != 0 {
+			t.Fatal("空ではない")
+		}
+	})
+
+	t.Run("保存した得点を読み込む", func(t *testing.T) {
+		filePath := filepath.Join(dir, "highscores.json")
+		store, _ := LoadStore(filePath)
+		store.SubmitDaily(createDailyScore("2024-01-31", 30))
+		if err := SaveStore(store); err != nil {
+			t.Fatal(err)
+		}
+		loadedStore, err := LoadStore(filePath)
+		if err != nil {
+			t.Fatal(err)
+		} else if loadedStore.FindDaily("2024-01-31", "sprint", "normal") == nil {
+			t.Fatal("得点を読み込んでいない")
+		}
+	})
+}
@@ -0,0 +1,265 @@
+package i18n
+
+var catalogs = map[string]map[string]string{
+	"en": map[string]string{
+		"title": "[ A Tower of Go ]",
+		"status.time": "Time : %4.1f",
+		"status.floor": "Floor: %2d",
+		"help.title": "[ Operations ]",
+		"help.start": "Start or restart a new game.",
+		"help.arrowKeys": "Arrow keys",
+		"help.or": "or",
+		"help.move": "Move the player.",
+		"help.back": "Back to the menu.",
+		"help.rewind": "Rewind a move.",
+		"help.trail": "Show or hide the trail.",
+		"help.breadcrumb": "Drop or pick up a breadcrumb.",
+		"description.goal": "Move the player in the upper left to reach the stairs in the lower right.",
+		"description.timeAttack": "The score is the number of floors that can be reached within %d seconds.",
+		"description.sprint": "The score is the time to reach the floor %d.",
+		"description.survival": "Starts with %d seconds, and each floor adds time.",
+		"description.zen": "There is no timer. Climb at your own pace.",
+		"description.goalOfTower": "Move the player to reach the stairs on each floor.",
+		"description.custom": "The score is the time to reach the top, the floor %d.",
+		"status.elapsedTime": "Time : %4.1f",
+		"status.floorWithGoal": "Floor: %2d/%d",
+		"status.ghost": "Ghost: %2d",
+		"status.paused": "Paused, press a key",
+		"mode.sprint": "Sprint",
+		"mode.survival": "Endless survival",
+		"mode.zen": "Zen",
+		"mode.custom": "Custom tower",
+		"mode.customWithName": "Tower: %s",
+		"results.mode": "Mode",
+		"results.time": "Time",
+		"results.splits": "Splits",
+		"results.floorPoints": "Floor points",
+		"results.timeBonus": "Time bonus",
+		"results.efficiencyBonus": "Efficiency bonus",
+		"results.total": "Total score",
+		"unit.seconds": "%.1fs",
+		"rank.noGood": "No good...",
+		"rank.good": "Good!",
+		"rank.excellent": "Excellent!",
+		"rank.marvelous": "Marvelous!",
+		"rank.gopher": "Gopher!!",
+		"screen.tooSmall": "Terminal too small",
+		"screen.need": "Need %dx%d",
+		"screen.have": "Have %dx%d",
+		"heading.title": "A Tower of Go",
+		"heading.modeSelect": "Select a mode",
+		"heading.settings": "Settings",
+		"menu.start": "Start",
+		"menu.settings": "Settings",
+		"menu.quit": "Quit",
+		"menu.back": "Back",
+		"mode.timeAttack": "Time attack",
+		"settings.language": "Language  : %s",
+		"settings.theme": "Theme     : %s",
+		"settings.difficulty": "Difficulty: %s",
+		"settings.trail": "Trail     : %s",
+		"settings.deadEnds": "Dead ends : %s",
+		"settings.on": "On",
+		"settings.off": "Off",
+		"difficulty.easy": "Easy",
+		"difficulty.normal": "Normal",
+		"difficulty.hard": "Hard",
+		"difficulty.custom": "Custom",
+		"results.difficulty": "Difficulty",
+		"language.en": "English",
+		"language.ja": "日本語",
+		"hint.title": "Up/Down: Select  Enter: Decide  Esc: Quit",
+		"hint.menu": "Up/Down: Select  Enter: Decide  Esc: Back",
+		"hint.settings": "Up/Down: Select  Left/Right: Change  Esc: Back",
+		"hint.results": "Enter: Retry  v: Heatmap  Esc: Back",
+		"results.heading": "Results",
+		"results.floor": "Floor",
+		"editor.heading": "Editing \"%s\" (%s)",
+		"editor.palette": "[ Palette ]",
+		"editor.tile.wall": "Wall",
+		"editor.tile.floor": "Floor",
+		"editor.tile.start": "Start",
+		"editor.tile.upstairs": "Stairs",
+		"editor.cannotPlace": "Can not place it. %s",
+		"editor.valid": "The floor can be cleared in %s steps.",
+		"editor.invalid": "The floor can not be cleared. %s",
+		"editor.saved": "Saved to %s.",
+		"editor.saveFailed": "Failed to save. %s",
+		"heading.daily": "Daily challenge %s",
+		"results.daily": "Daily",
+		"results.streak": "Streak",
+		"unit.day": "%d day",
+		"unit.days": "%d days",
+		"results.challenge": "Challenge code",
+		"menu.achievements": "Achievements",
+		"heading.achievements": "Achievements %d/%d",
+		"hint.achievements": "Up/Down: Select  Esc: Back",
+		"achievement.unlocked": "[*] %s - %s",
+		"achievement.locked": "[ ] %s - %s",
+		"notice.achievement": "Unlocked: %s",
+		"achievement.firstFloor.title": "First steps",
+		"achievement.firstFloor.description": "Clear a floor.",
+		"achievement.floor10.title": "Ten floors up",
+		"achievement.floor10.description": "Reach the floor 10 in a game.",
+		"achievement.optimalPath.title": "Shortest path",
+		"achievement.optimalPath.description": "Clear a floor along the shortest path.",
+		"achievement.noBacktracking.title": "No looking back",
+		"achievement.noBacktracking.description": "Finish a game without stepping on a cell twice.",
+		"achievement.sprinter.title": "Sprinter",
+		"achievement.sprinter.description": "Finish the sprint within 60 seconds.",
+		"achievement.dailyStreak7.title": "Week of dailies",
+		"achievement.dailyStreak7.description": "Play the daily challenge 7 days in a row.",
+		"menu.stats": "Statistics",
+		"heading.stats": "Statistics",
+		"heading.statsOfGroup": "Statistics: %s / %s (%d/%d)",
+		"hint.stats": "Left/Right: Mode  Esc: Back",
+		"stats.empty": "No game has been finished yet.",
+		"stats.games": "Games",
+		"stats.scores": "Scores",
+		"stats.best": "Personal best",
+		"stats.mean": "Mean of last %d",
+		"stats.meanWithPrevious": "%s (previous %s)",
+		"stats.efficiency": "Efficiency",
+		"stats.floorTimes": "Floor times",
+		"stats.timeRange": "  %.1f-%.1fs",
+		"unit.floors": "%.0f floors",
+		"heading.heatmap": "Heatmap",
+		"heading.heatmapOfFloor": "Heatmap of floor %d/%d",
+		"hint.heatmap": "Left/Right: Floor  Esc: Back",
+		"heatmap.legend": "Visits",
+		"heatmap.moves": "Moves",
+		"heatmap.shortestPath": "Shortest path",
+		"heatmap.visitedCells": "Visited cells",
+		"heatmap.revisitedCells": "Revisited cells",
+		"hint.editor": "Arrows: Move  Space: Place  Tab/1-4: Tile  c: Check  w: Save  p: Playtest  Esc: Back",
+	},
+	"ja": map[string]string{
+		"title": "[ A Tower of Go ]",
+		"status.time": "時間: %4.1f",
+		"status.floor": "階層: %2d",
+		"help.title": "[ 操作方法 ]",
+		"help.start": "新しいゲームを開始・再開する。",
+		"help.arrowKeys": "矢印キー",
+		"help.or": "または",
+		"help.move": "プレイヤーを移動する。",
+		"help.back": "メニューへ戻る。",
+		"help.rewind": "1手戻す。",
+		"help.trail": "軌跡を表示・非表示にする。",
+		"help.breadcrumb": "目印を置く・拾う。",
+		"description.goal": "左上のプレイヤーを動かして、右下の階段を目指しましょう。",
+		"description.timeAttack": "%d秒以内に到達できた階数がスコアになります。",
+		"description.sprint": "%d階へ到達するまでの時間がスコアになります。",
+		"description.survival": "%d秒から始まり、階を上るごとに時間が増えます。",
+		"description.zen": "制限時間はありません。自分のペースで上りましょう。",
+		"description.goalOfTower": "各階でプレイヤーを動かして、階段を目指しましょう。",
+		"description.custom": "頂上の%d階へ到達するまでの時間がスコアになります。",
+		"status.elapsedTime": "時間: %4.1f",
+		"status.floorWithGoal": "階層: %2d/%d",
+		"status.ghost": "幽霊: %2d",
+		"status.paused": "一時停止中、キーで再開",
+		"mode.sprint": "スプリント",
+		"mode.survival": "エンドレスサバイバル",
+		"mode.zen": "禅",
+		"mode.custom": "カスタムの塔",
+		"mode.customWithName": "塔: %s",
+		"results.mode": "モード",
+		"results.time": "時間",
+		"results.splits": "各階の時間",
+		"results.floorPoints": "階層点",
+		"results.timeBonus": "時間ボーナス",
+		"results.efficiencyBonus": "効率ボーナス",
+		"results.total": "合計スコア",
+		"unit.seconds": "%.1f秒",
+		"rank.noGood": "残念...",
+		"rank.good": "良い!",
+		"rank.excellent": "素晴らしい!",
+		"rank.marvelous": "驚異的!",
+		"rank.gopher": "Gopher!!",
+		"screen.tooSmall": "端末が小さすぎます",
+		"screen.need": "必要 %dx%d",
+		"screen.have": "現在 %dx%d",
+		"heading.modeSelect": "モードを選んでください",
+		"heading.settings": "設定",
+		"menu.start": "はじめる",
+		"menu.settings": "設定",
+		"menu.quit": "終了",
+		"menu.back": "戻る",
+		"mode.timeAttack": "タイムアタック",
+		"settings.language": "言語    : %s",
+		"settings.theme": "テーマ  : %s",
+		"settings.difficulty": "難易度  : %s",
+		"settings.trail": "軌跡    : %s",
+		"settings.deadEnds": "袋小路  : %s",
+		"settings.on": "表示",
+		"settings.off": "非表示",
+		"difficulty.easy": "かんたん",
+		"difficulty.normal": "ふつう",
+		"difficulty.hard": "むずかしい",
+		"difficulty.custom": "カスタム",
+		"results.difficulty": "難易度",
+		"hint.title": "上下: 選択  Enter: 決定  Esc: 終了",
+		"hint.menu": "上下: 選択  Enter: 決定  Esc: 戻る",
+		"hint.settings": "上下: 選択  左右: 変更  Esc: 戻る",
+		"hint.results": "Enter: 再挑戦  v: ヒートマップ  Esc: 戻る",
+		"results.heading": "結果",
+		"results.floor": "階層",
+		"editor.heading": "「%s」を編集中 (%s)",
+		"editor.palette": "[ パレット ]",
+		"editor.tile.wall": "壁",
+		"editor.tile.floor": "床",
+		"editor.tile.start": "開始位置",
+		"editor.tile.upstairs": "階段",
+		"editor.cannotPlace": "置けません。%s",
+		"editor.valid": "%s歩でクリアできます。",
+		"editor.invalid": "クリアできません。%s",
+		"editor.saved": "%s に保存しました。",
+		"editor.saveFailed": "保存できませんでした。%s",
+		"heading.daily": "デイリーチャレンジ %s",
+		"results.daily": "デイリー",
+		"results.streak": "連続",
+		"unit.day": "%d日",
+		"unit.days": "%d日",
+		"results.challenge": "挑戦コード",
+		"menu.achievements": "実績",
+		"heading.achievements": "実績 %d/%d",
+		"hint.achievements": "上下: 選択  Esc: 戻る",
+		"achievement.unlocked": "[*] %s - %s",
+		"achievement.locked": "[ ] %s - %s",
+		"notice.achievement": "実績解除: %s",
+		"achievement.firstFloor.title": "最初の一歩",
+		"achievement.firstFloor.description": "階を登る。",
+		"achievement.floor10.title": "十階へ",
+		"achievement.floor10.description": "1回のゲームで10階へ到達する。",
+		"achievement.optimalPath.title": "最短経路",
+		"achievement.optimalPath.description": "最短経路で階を登る。",
+		"achievement.noBacktracking.title": "振り返らない",
+		"achievement.noBacktracking.description": "同じ階で同じマスを2度踏まずにゲームを終える。",
+		"achievement.sprinter.title": "スプリンター",
+		"achievement.sprinter.description": "スプリントを60秒以内に終える。",
+		"achievement.dailyStreak7.title": "デイリーの一週間",
+		"achievement.dailyStreak7.description": "デイリーチャレンジを7日連続で遊ぶ。",
+		"menu.stats": "統計",
+		"heading.stats": "統計",
+		"heading.statsOfGroup": "統計: %s / %s (%d/%d)",
+		"hint.stats": "左右: モード  Esc: 戻る",
+		"stats.empty": "まだ終えたゲームがありません。",
+		"stats.games": "ゲーム数",
+		"stats.scores": "スコア",
+		"stats.best": "自己ベスト",
+		"stats.mean": "直近%d回の平均",
+		"stats.meanWithPrevious": "%s (その前 %s)",
+		"stats.efficiency": "効率",
+		"stats.floorTimes": "各階の時間",
+		"stats.timeRange": "  %.1f-%.1f秒",
+		"unit.floors": "%.0f階",
+		"heading.heatmap": "ヒートマップ",
+		"heading.heatmapOfFloor": "%d/%d階のヒートマップ",
+		"hint.heatmap": "左右: 階  Esc: 戻る",
+		"heatmap.legend": "訪問回数",
+		"heatmap.moves": "移動数",
+		"heatmap.shortestPath": "最短経路",
+		"heatmap.visitedCells": "訪れたマス",
+		"heatmap.revisitedCells": "再訪したマス",
+		"hint.editor": "矢印: 移動  Space: 配置  Tab/1-4: タイル  c: 検査  w: 保存  p: 試遊  Esc: 戻る",
+	},
+}
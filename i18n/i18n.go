@@ -0,0 +1,82 @@
+package i18n
+
+//
+// The "i18n" package translates on-screen strings into the player's language.
+//
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const DefaultLanguage = "en"
+
+type Translator struct {
+	language string
+}
+
+func (translator *Translator) GetLanguage() string {
+	return translator.language
+}
+
+// Returns the message of the key in the language, it is formatted with args like fmt.Sprintf.
+// A missing message falls back to the default language, and then to the key itself.
+func (translator *Translator) Translate(key string, args ...interface{}) string {
+	message, ok := catalogs[translator.language][key]
+	if !ok {
+		message, ok = catalogs[DefaultLanguage][key]
+	}
+	if !ok {
+		message = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+func IsSupportedLanguage(language string) bool {
+	_, ok := catalogs[language]
+	return ok
+}
+
+func GetSupportedLanguages() []string {
+	return []string{"en", "ja"}
+}
+
+// Extract a language code from a locale string such as "ja_JP.UTF-8".
+func parseLocale(locale string) string {
+	language := locale
+	if index := strings.IndexAny(language, "_.@"); index >= 0 {
+		language = language[:index]
+	}
+	return strings.ToLower(language)
+}
+
+// Decide the language in order of the given preferences and the locale environment variables.
+// Unsupported or empty preferences are skipped.
+func DetectLanguage(preferences ...string) string {
+	candidates := make([]string, 0)
+	candidates = append(candidates, preferences...)
+	// The same precedence as POSIX locale categories.
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		candidates = append(candidates, parseLocale(os.Getenv(name)))
+	}
+	for _, candidate := range candidates {
+		if IsSupportedLanguage(candidate) {
+			return candidate
+		}
+	}
+	return DefaultLanguage
+}
+
+// An unsupported language is replaced with the default language.
+func CreateTranslator(language string) *Translator {
+	if !IsSupportedLanguage(language) {
+		language = DefaultLanguage
+	}
+	return &Translator{
+		language: language,
+	}
+}
@@ -0,0 +1,75 @@
+package i18n
+
+import (
+	"os"
+	"testing"
+)
+
+func TestTranslator_Translate_NotTD(t *testing.T) {
+	t.Run("指定した言語のメッセージを返す", func(t *testing.T) {
+		translator := CreateTranslator("ja")
+		if translator.Translate("help.title") != "[ 操作方法 ]" {
+			t.Fatal("日本語のメッセージではない")
+		}
+	})
+
+	t.Run("引数で書式化する", func(t *testing.T) {
+		translator := CreateTranslator("en")
+		if translator.Translate("status.floor", 3) != "Floor:  3" {
+			t.Fatal("書式化されていない")
+		}
+	})
+
+	t.Run("指定した言語にメッセージが存在しないとき、既定の言語のメッセージを返す", func(t *testing.T) {
+		catalogs["en"]["test.onlyInEnglish"] = "Only in English"
+		defer delete(catalogs["en"], "test.onlyInEnglish")
+		translator := CreateTranslator("ja")
+		if translator.Translate("test.onlyInEnglish") != "Only in English" {
+			t.Fatal("既定の言語のメッセージではない")
+		}
+	})
+
+	t.Run("どの言語にもメッセージが存在しないとき、キーを返す", func(t *testing.T) {
+		translator := CreateTranslator("ja")
+		if translator.Translate("test.unknown") != "test.unknown" {
+			t.Fatal("キーではない")
+		}
+	})
+
+	t.Run("未対応の言語を指定したとき、既定の言語になる", func(t *testing.T) {
+		translator := CreateTranslator("xx")
+		if translator.GetLanguage() != DefaultLanguage {
+			t.Fatal("既定の言語ではない")
+		}
+	})
+}
+
+func TestDetectLanguage_NotTD(t *testing.T) {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		original, ok := os.LookupEnv(name)
+		os.Unsetenv(name)
+		if ok {
+			defer os.Setenv(name, original)
+		}
+	}
+
+	t.Run("対応している最初の指定を返す", func(t *testing.T) {
+		if DetectLanguage("", "xx", "ja", "en") != "ja" {
+			t.Fatal("ja ではない")
+		}
+	})
+
+	t.Run("指定がないとき、ロケールの環境変数から判定する", func(t *testing.T) {
+		os.Setenv("LANG", "ja_JP.UTF-8")
+		defer os.Unsetenv("LANG")
+		if DetectLanguage("") != "ja" {
+			t.Fatal("ja ではない")
+		}
+	})
+
+	t.Run("判定できないとき、既定の言語を返す", func(t *testing.T) {
+		if DetectLanguage() != DefaultLanguage {
+			t.Fatal("既定の言語ではない")
+		}
+	})
+}
@@ -0,0 +1,288 @@
+package levels
+
+//
+// The "levels" package reads and writes hand-made floors as text.
+//
+// A map file has an optional header of "key: value" lines and a grid, separated by a line of "---".
+//
+//   name: First steps
+//   timeLimit: 20
+//   ---
+//   #######
+//   #@...<#
+//   #######
+//
+// The glyphs are "#" (wall), "." (floor), "@" (start) and "<" (upstairs).
+// The header may also have "start" and "goal" as "row,column" from 0, they have priority over "@" and "<".
+//
+
+import (
+	"fmt"
+	"github.com/kjirou/tower-of-go/models"
+	"github.com/kjirou/tower-of-go/utils"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const headerSeparator = "---"
+
+// The extension of map files in a map set directory.
+const MapFileExtension = ".txt"
+
+type Level struct {
+	Name string
+	// Zero means that the floor adds no time.
+	TimeLimit time.Duration
+	// The hero and the upstairs are not placed on it.
+	Field *models.Field
+}
+
+func parsePosition(value string) (*utils.MatrixPosition, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return nil, errors.Errorf("The position \"%s\" is not \"row,column\".", value)
+	}
+	y, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return nil, errors.Errorf("The row of the position \"%s\" is not a number.", value)
+	}
+	x, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return nil, errors.Errorf("The column of the position \"%s\" is not a number.", value)
+	}
+	return &utils.MatrixPosition{Y: y, X: x}, nil
+}
+
+// The source is a file path or the like, it is used in error messages.
+func ParseLevel(content string, source string) (*Level, error) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	level := &Level{Name: strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))}
+	var startPosition *utils.MatrixPosition
+	var upstairsPosition *utils.MatrixPosition
+
+	gridStartIndex := 0
+	for i, line := range lines {
+		if strings.TrimSpace(line) == headerSeparator {
+			gridStartIndex = i + 1
+			break
+		}
+	}
+	for i := 0; i < gridStartIndex - 1; i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("%s:%d: The header line is not \"key: value\".", source, i + 1)
+		}
+		key := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+		var err error
+		switch key {
+		case "name":
+			level.Name = value
+		case "timeLimit":
+			seconds, parseErr := strconv.ParseFloat(value, 64)
+			if parseErr != nil || seconds < 0 {
+				err = errors.Errorf("The time limit \"%s\" is not seconds.", value)
+			}
+			level.TimeLimit = time.Duration(seconds * float64(time.Second))
+		case "start":
+			startPosition, err = parsePosition(value)
+		case "goal":
+			upstairsPosition, err = parsePosition(value)
+		default:
+			err = errors.Errorf("The header key \"%s\" is unknown.", key)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s:%d", source, i + 1)
+		}
+	}
+
+	gridLines := make([]string, 0)
+	for _, line := range lines[gridStartIndex:] {
+		line = strings.TrimRight(line, " \t")
+		if line != "" {
+			gridLines = append(gridLines, line)
+		}
+	}
+	if len(gridLines) == 0 {
+		return nil, errors.Errorf("%s: The map has no grid.", source)
+	}
+	columnLength := len([]rune(gridLines[0]))
+	for i, line := range gridLines {
+		if len([]rune(line)) != columnLength {
+			return nil, errors.Errorf("%s: The row %d of the grid has a different length.", source, i)
+		}
+	}
+
+	field := models.CreateField(len(gridLines), columnLength)
+	var glyphStartPosition *utils.MatrixPosition
+	var glyphUpstairsPosition *utils.MatrixPosition
+	for y, line := range gridLines {
+		for x, glyph := range []rune(line) {
+			position := &utils.MatrixPosition{Y: y, X: x}
+			element, _ := field.At(position)
+			switch glyph {
+			case '#':
+				element.UpdateObjectClass("wall")
+			case '.':
+			case '@':
+				if glyphStartPosition != nil {
+					return nil, errors.Errorf("%s: The map has multiple \"@\".", source)
+				}
+				glyphStartPosition = position
+			case '<':
+				if glyphUpstairsPosition != nil {
+					return nil, errors.Errorf("%s: The map has multiple \"<\".", source)
+				}
+				glyphUpstairsPosition = position
+			default:
+				return nil, errors.Errorf("%s: The glyph \"%c\" at %d,%d is unknown.", source, glyph, y, x)
+			}
+		}
+	}
+
+	if startPosition == nil {
+		startPosition = glyphStartPosition
+	}
+	if upstairsPosition == nil {
+		upstairsPosition = glyphUpstairsPosition
+	}
+	if startPosition == nil || upstairsPosition == nil {
+		return nil, errors.Errorf("%s: The map needs both the start and the goal.", source)
+	}
+	field.SetStartPosition(startPosition)
+	field.SetUpstairsPosition(upstairsPosition)
+	if err := ValidateField(field); err != nil {
+		return nil, errors.Wrapf(err, "%s", source)
+	}
+	level.Field = field
+
+	return level, nil
+}
+
+// Check that the floor can be cleared.
+func ValidateField(field *models.Field) error {
+	startPosition := field.GetStartPosition()
+	upstairsPosition := field.GetUpstairsPosition()
+	for _, position := range []*utils.MatrixPosition{startPosition, upstairsPosition} {
+		element, err := field.At(position)
+		if err != nil {
+			return err
+		} else if element.GetObjectClass() == "wall" {
+			return errors.Errorf("The start or the goal at %d,%d is a wall.", position.Y, position.X)
+		}
+	}
+	if startPosition.Y == upstairsPosition.Y && startPosition.X == upstairsPosition.X {
+		return errors.Errorf("The start and the goal are at the same position.")
+	}
+	if field.MeasureShortestPathLength(startPosition, upstairsPosition) < 0 {
+		return errors.Errorf("The goal can not be reached from the start.")
+	}
+	return nil
+}
+
+// A room surrounded by walls, the start is the upper left and the goal is the lower right.
+func CreateBlankLevel(name string, rowLength int, columnLength int) *Level {
+	field := models.CreateField(rowLength, columnLength)
+	for y := 0; y < rowLength; y++ {
+		for x := 0; x < columnLength; x++ {
+			if y == 0 || y == rowLength-1 || x == 0 || x == columnLength-1 {
+				element, _ := field.At(&utils.MatrixPosition{Y: y, X: x})
+				element.UpdateObjectClass("wall")
+			}
+		}
+	}
+	return &Level{Name: name, Field: field}
+}
+
+// The inverse of ParseLevel.
+func FormatLevel(level *Level) string {
+	field := level.Field
+	startPosition := field.GetStartPosition()
+	upstairsPosition := field.GetUpstairsPosition()
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "name: %s\n", level.Name)
+	if level.TimeLimit > 0 {
+		fmt.Fprintf(&builder, "timeLimit: %s\n", strconv.FormatFloat(level.TimeLimit.Seconds(), 'f', -1, 64))
+	}
+	builder.WriteString(headerSeparator + "\n")
+	for y := 0; y < field.MeasureRowLength(); y++ {
+		for x := 0; x < field.MeasureColumnLength(); x++ {
+			element, _ := field.At(&utils.MatrixPosition{Y: y, X: x})
+			switch {
+			case y == startPosition.Y && x == startPosition.X:
+				builder.WriteRune('@')
+			case y == upstairsPosition.Y && x == upstairsPosition.X:
+				builder.WriteRune('<')
+			case element.GetObjectClass() == "wall":
+				builder.WriteRune('#')
+			default:
+				builder.WriteRune('.')
+			}
+		}
+		builder.WriteRune('\n')
+	}
+	return builder.String()
+}
+
+func LoadLevel(filePath string) (*Level, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return ParseLevel(string(content), filePath)
+}
+
+func SaveLevel(filePath string, level *Level) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := ioutil.WriteFile(filePath, []byte(FormatLevel(level)), 0644); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Load the map files in the directory in order of the file names.
+func LoadLevelSet(dirPath string) ([]*Level, error) {
+	fileInfos, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	fileNames := make([]string, 0)
+	for _, fileInfo := range fileInfos {
+		if !fileInfo.IsDir() && filepath.Ext(fileInfo.Name()) == MapFileExtension {
+			fileNames = append(fileNames, fileInfo.Name())
+		}
+	}
+	sort.Strings(fileNames)
+	levels := make([]*Level, 0)
+	for _, fileName := range fileNames {
+		level, err := LoadLevel(filepath.Join(dirPath, fileName))
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, level)
+	}
+	if len(levels) == 0 {
+		return nil, errors.Errorf("The directory (%s) has no map files.", dirPath)
+	}
+	return levels, nil
+}
+
+func CreateTowerMode(name string, levels []*Level) (*models.CustomTowerMode, error) {
+	floors := make([]*models.CustomFloor, 0)
+	for _, level := range levels {
+		floors = append(floors, &models.CustomFloor{Name: level.Name, TimeLimit: level.TimeLimit, Field: level.Field})
+	}
+	return models.CreateCustomTowerMode(name, floors)
+}
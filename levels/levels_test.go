@@ -0,0 +1,135 @@
+package levels
+
+import (
+	"github.com/kjirou/tower-of-go/utils"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLevel_NotTD(t *testing.T) {
+	t.Run("ヘッダとグリッドを読み込む", func(t *testing.T) {
+		level, err := ParseLevel("name: Test\ntimeLimit: 12.5\n---\n#####\n#@.<#\n#####\n", "test.txt")
+		if err != nil {
+			t.Fatal(err)
+		} else if level.Name != "Test" || level.TimeLimit != 12500*time.Millisecond {
+			t.Fatal("ヘッダが違う")
+		}
+		field := level.Field
+		if field.MeasureRowLength() != 3 || field.MeasureColumnLength() != 5 {
+			t.Fatal("広さが違う")
+		} else if field.GetStartPosition().X != 1 || field.GetUpstairsPosition().X != 3 {
+			t.Fatal("開始位置か上り階段の位置が違う")
+		}
+		element, _ := field.At(field.GetStartPosition())
+		if element.GetObjectClass() != "empty" {
+			t.Fatal("開始位置が空ではない")
+		}
+	})
+
+	t.Run("ヘッダがなければグリッドだけとして読み込み、名前はファイル名になる", func(t *testing.T) {
+		level, err := ParseLevel("#####\n#@.<#\n#####\n", "maps/first.txt")
+		if err != nil {
+			t.Fatal(err)
+		} else if level.Name != "first" {
+			t.Fatal("名前がファイル名ではない")
+		}
+	})
+
+	t.Run("ヘッダの位置を記号より優先する", func(t *testing.T) {
+		level, err := ParseLevel("start: 1,3\ngoal: 1,1\n---\n#####\n#@.<#\n#####\n", "test.txt")
+		if err != nil {
+			t.Fatal(err)
+		} else if level.Field.GetStartPosition().X != 3 || level.Field.GetUpstairsPosition().X != 1 {
+			t.Fatal("ヘッダの位置ではない")
+		}
+	})
+
+	t.Run("不正なマップはエラーを返す", func(t *testing.T) {
+		testCases := map[string]string{
+			"no goal": "#####\n#@..#\n#####\n",
+			"unreachable": "#####\n#@#<#\n#####\n",
+			"unknown glyph": "#####\n#@x<#\n#####\n",
+			"ragged rows": "#####\n#@.<#\n####\n",
+			"unknown key": "color: red\n---\n#####\n#@.<#\n#####\n",
+		}
+		for name, content := range testCases {
+			_, err := ParseLevel(content, "test.txt")
+			if err == nil {
+				t.Fatalf("%s のときにエラーを返さない", name)
+			} else if !strings.Contains(err.Error(), "test.txt") {
+				t.Fatalf("%s のときにエラーにファイル名がない", name)
+			}
+		}
+	})
+}
+
+func TestValidateField_NotTD(t *testing.T) {
+	t.Run("壁で囲まれた部屋はクリアできる", func(t *testing.T) {
+		level := CreateBlankLevel("blank", 5, 7)
+		if err := ValidateField(level.Field); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("階段へ到達できないとき、エラーを返す", func(t *testing.T) {
+		level := CreateBlankLevel("blank", 5, 5)
+		for y := 0; y < 5; y++ {
+			element, _ := level.Field.At(&utils.MatrixPosition{Y: y, X: 2})
+			element.UpdateObjectClass("wall")
+		}
+		level.Field.SetUpstairsPosition(&utils.MatrixPosition{Y: 3, X: 3})
+		if err := ValidateField(level.Field); err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+}
+
+func TestFormatLevel_NotTD(t *testing.T) {
+	t.Run("読み込んだ内容に戻せる", func(t *testing.T) {
+		content := "name: Test\ntimeLimit: 10\n---\n#####\n#@.<#\n#####\n"
+		level, _ := ParseLevel(content, "test.txt")
+		if FormatLevel(level) != content {
+			t.Fatal("元の内容と違う")
+		}
+	})
+}
+
+func TestLoadLevelSet_NotTD(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "tower-of-go")
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "02.txt"), []byte("name: Second\n---\n#@<#\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "01.txt"), []byte("name: First\n---\n#@.<#\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "notes.md"), []byte("memo"), 0644)
+
+	t.Run("ファイル名の順にマップだけを読み込む", func(t *testing.T) {
+		levelSet, err := LoadLevelSet(dir)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(levelSet) != 2 || levelSet[0].Name != "First" || levelSet[1].Name != "Second" {
+			t.Fatal("読み込んだマップが違う")
+		}
+	})
+
+	t.Run("塔のモードにできる", func(t *testing.T) {
+		levelSet, _ := LoadLevelSet(dir)
+		mode, err := CreateTowerMode("test", levelSet)
+		if err != nil {
+			t.Fatal(err)
+		} else if mode.GetGoalFloorNumber() != 3 {
+			t.Fatal("頂上の階が違う")
+		}
+	})
+}
+
+func TestLoadLevelSet_Examples_NotTD(t *testing.T) {
+	t.Run("同梱の例を読み込める", func(t *testing.T) {
+		_, err := LoadLevelSet(filepath.Join("..", "examples", "maps", "onboarding"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
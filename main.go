@@ -3,14 +3,27 @@ package main
 import (
 	"flag"
 	"fmt"
+	"github.com/kjirou/tower-of-go/achievements"
+	"github.com/kjirou/tower-of-go/config"
 	"github.com/kjirou/tower-of-go/controller"
+	"github.com/kjirou/tower-of-go/highscores"
+	"github.com/kjirou/tower-of-go/levels"
+	"github.com/kjirou/tower-of-go/models"
+	"github.com/kjirou/tower-of-go/replays"
+	"github.com/kjirou/tower-of-go/saves"
+	"github.com/kjirou/tower-of-go/stats"
+	"github.com/kjirou/tower-of-go/themes"
 	"github.com/kjirou/tower-of-go/views"
 	"github.com/nsf/termbox-go"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"time"
 )
 
 func drawTerminal(screen *views.Screen) {
+	// Clear the previous output, because it may remain outside the screen after the terminal is resized.
+	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
 	screen.ForEachCells(func (y int, x int, symbol rune, fg termbox.Attribute, bg termbox.Attribute) {
 		// NOTE: Probably, termbox.SetCell's outputs are asynchronous.
 		//       Therefore, multiple executions on the same cell at the same time will nest the output buffers.
@@ -33,7 +46,28 @@ func convertScreenToText(screen *views.Screen) string {
 	return output
 }
 
-func runMainLoop(controller *controller.Controller) {
+// Returns nil if no map is specified.
+func loadCustomTowerMode(mapFilePath string, mapSetDirPath string) (*models.CustomTowerMode, error) {
+	switch {
+	case mapSetDirPath != "":
+		levelSet, err := levels.LoadLevelSet(mapSetDirPath)
+		if err != nil {
+			return nil, err
+		}
+		return levels.CreateTowerMode(filepath.Base(filepath.Clean(mapSetDirPath)), levelSet)
+	case mapFilePath != "":
+		level, err := levels.LoadLevel(mapFilePath)
+		if err != nil {
+			return nil, err
+		}
+		return levels.CreateTowerMode(level.Name, []*levels.Level{level})
+	}
+	return nil, nil
+}
+
+// It returns when the stop channel is closed or the quit is requested, and then closes the finished channel.
+func runMainLoop(controller *controller.Controller, stop <-chan struct{}, finished chan<- struct{}) {
+	defer close(finished)
 	for {
 		// TODO: Expecting 60fps. However, it is behind the real time.
 		//       For example, my computer needs 33-36 seconds of real time for 30 seconds of a game.
@@ -41,6 +75,12 @@ func runMainLoop(controller *controller.Controller) {
 		interval := controller.CalculateIntervalToNextMainLoop(time.Now())
 		time.Sleep(interval)
 
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
 		newState, err := controller.HandleMainLoop(interval)
 
 		if err != nil {
@@ -51,22 +91,168 @@ func runMainLoop(controller *controller.Controller) {
 			controller.Dispatch(newState)
 			drawTerminal(controller.GetScreen())
 		}
+
+		if controller.IsQuitRequested() {
+			// Wake up the event loop in the main goroutine to quit.
+			termbox.Interrupt()
+			return
+		}
+	}
+}
+
+// The command line options that are applied to the controller after it is created.
+type setupFlags struct {
+	mapFilePath string
+	mapSetDirPath string
+	seed int64
+	isDaily bool
+	challengeCode string
+	isResume bool
+	editFilePath string
+}
+
+// Load the stores and apply the options in the same way, with or without the terminal.
+func setUpController(controller *controller.Controller, flags *setupFlags) error {
+	customTowerMode, err := loadCustomTowerMode(flags.mapFilePath, flags.mapSetDirPath)
+	if err != nil {
+		return err
+	}
+	if customTowerMode != nil {
+		controller.AddGameMode(customTowerMode)
+	}
+	controller.SetSeed(flags.seed)
+	if flags.isDaily {
+		controller.StartDailyChallenge(time.Now())
+	}
+
+	replayFilePath, err := replays.GetDefaultStoreFilePath()
+	if err != nil {
+		return err
+	}
+	if err := controller.LoadReplays(replayFilePath); err != nil {
+		return err
+	}
+	highScoreFilePath, err := highscores.GetDefaultStoreFilePath()
+	if err != nil {
+		return err
+	}
+	if err := controller.LoadHighScores(highScoreFilePath); err != nil {
+		return err
+	}
+	achievementFilePath, err := achievements.GetDefaultStoreFilePath()
+	if err != nil {
+		return err
+	}
+	if err := controller.LoadAchievements(achievementFilePath); err != nil {
+		return err
+	}
+	statsFilePath, err := stats.GetDefaultStoreFilePath()
+	if err != nil {
+		return err
+	}
+	if err := controller.LoadStats(statsFilePath); err != nil {
+		return err
+	}
+
+	if flags.challengeCode != "" {
+		if err := controller.StartChallenge(flags.challengeCode); err != nil {
+			return err
+		}
+	}
+	saveFilePath, err := saves.GetDefaultSaveFilePath()
+	if err != nil {
+		return err
+	}
+	controller.SetSaveFilePath(saveFilePath)
+	if flags.isResume {
+		if err := controller.ResumeGame(); err != nil {
+			return err
+		}
+	}
+	if flags.editFilePath != "" {
+		if err := controller.OpenEditor(flags.editFilePath); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 func main() {
+	isSubcommand, subcommandErr := runSubcommand(os.Args[1:])
+	if subcommandErr != nil {
+		exitWithError(subcommandErr)
+	} else if isSubcommand {
+		return
+	}
+
 	var debugMode bool
 	flag.BoolVar(&debugMode, "debug", false, "Runs with debug mode.")
+	var configFilePath string
+	flag.StringVar(&configFilePath, "config", "", "Path to the config file. Defaults to \"tower-of-go/config.json\" in the user config directory.")
+	var language string
+	flag.StringVar(&language, "lang", "", "Language of the UI, \"en\" or \"ja\". Defaults to the config or the locale.")
+	var themeName string
+	flag.StringVar(&themeName, "theme", "", "Name of the color theme, such as \"default\", \"colorblind\" or \"monochrome\".")
+	var difficultyName string
+	flag.StringVar(&difficultyName, "difficulty", "", "Difficulty, \"easy\", \"normal\", \"hard\" or \"custom\".")
+	var mapFilePath string
+	flag.StringVar(&mapFilePath, "map", "", "Path to a map file to play as a tower of one floor.")
+	var mapSetDirPath string
+	flag.StringVar(&mapSetDirPath, "mapset", "", "Path to a directory of map files to play as a tower, in order of the file names.")
+	var editFilePath string
+	flag.StringVar(&editFilePath, "edit", "", "Path to a map file to edit in the terminal. A new map is created if it does not exist.")
+	var seed int64
+	flag.Int64Var(&seed, "seed", 0, "Non-zero seed of the generated floors. Every game has the same floors, and the best run is raced as a ghost.")
+	var isDaily bool
+	flag.BoolVar(&isDaily, "daily", false, "Plays the daily challenge. The seed is derived from the current UTC date, and the scores are kept per date.")
+	var challengeCode string
+	flag.StringVar(&challengeCode, "challenge", "", "Starts the game of a challenge code, that is shown on the results of a game.")
+	var isResume bool
+	flag.BoolVar(&isResume, "resume", false, "Resumes the game that was quit in progress. It is paused until a key is pressed. It is ignored with -debug.")
 	flag.Parse()
 
+	if configFilePath == "" {
+		defaultConfigFilePath, err := config.GetDefaultConfigFilePath()
+		if err != nil {
+			panic(err)
+		}
+		configFilePath = defaultConfigFilePath
+	}
+	cfg, loadConfigErr := config.LoadConfig(configFilePath)
+	if loadConfigErr != nil {
+		panic(loadConfigErr)
+	}
+	if language != "" {
+		cfg.Language = language
+	}
+	if themeName != "" {
+		cfg.Theme = themeName
+	}
+	if difficultyName != "" {
+		cfg.Difficulty = difficultyName
+	}
+
 	rand.Seed(time.Now().UnixNano())
 
-	controller, createControllerErr := controller.CreateController()
-	if createControllerErr != nil {
-		panic(createControllerErr)
+	flags := &setupFlags{
+		mapFilePath: mapFilePath,
+		mapSetDirPath: mapSetDirPath,
+		seed: seed,
+		isDaily: isDaily,
+		challengeCode: challengeCode,
+		// The debug mode does not suspend the game again, so the run would be lost if it were resumed.
+		isResume: isResume && !debugMode,
+		editFilePath: editFilePath,
 	}
 
 	if debugMode {
+		controller, createControllerErr := controller.CreateController(24, 80, cfg)
+		if createControllerErr != nil {
+			panic(createControllerErr)
+		}
+		if setUpErr := setUpController(controller, flags); setUpErr != nil {
+			panic(setUpErr)
+		}
 		fmt.Println(convertScreenToText(controller.GetScreen()))
 	} else {
 		termboxErr := termbox.Init()
@@ -76,8 +262,23 @@ func main() {
 		termbox.SetInputMode(termbox.InputEsc)
 		termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
 		defer termbox.Close()
+		width, height := termbox.Size()
+		controller, createControllerErr := controller.CreateController(height, width, cfg)
+		if createControllerErr != nil {
+			termbox.Close()
+			panic(createControllerErr)
+		}
+		if setUpErr := setUpController(controller, flags); setUpErr != nil {
+			termbox.Close()
+			panic(setUpErr)
+		}
+		if controller.GetColorMode() == themes.ColorMode256 {
+			termbox.SetOutputMode(termbox.Output256)
+		}
 		drawTerminal(controller.GetScreen())
-		go runMainLoop(controller)
+		stopMainLoop := make(chan struct{})
+		mainLoopFinished := make(chan struct{})
+		go runMainLoop(controller, stopMainLoop, mainLoopFinished)
 		// Observe termbox events.
 		didQuitApplication := false
 		for !didQuitApplication {
@@ -85,12 +286,25 @@ func main() {
 			switch event.Type {
 			case termbox.EventKey:
 				// Quit the application. Only this operation is resolved with priority.
-				if event.Key == termbox.KeyEsc || event.Key == termbox.KeyCtrlC || event.Key == termbox.KeyCtrlQ {
+				// The Esc key goes back to the previous scene, it quits at the root scene.
+				if event.Key == termbox.KeyCtrlC || event.Key == termbox.KeyCtrlQ {
 					didQuitApplication = true
 					break
 				}
 				controller.HandleKeyPress(event.Ch, event.Key)
+			case termbox.EventResize:
+				controller.HandleResize(event.Height, event.Width)
+			case termbox.EventInterrupt:
+				didQuitApplication = controller.IsQuitRequested()
 			}
 		}
+		// Wait for the last frame, so that the state is not changed while it is saved.
+		close(stopMainLoop)
+		<-mainLoopFinished
+		// Keep the game that is quit in progress, such as with Ctrl-C.
+		if suspendGameErr := controller.SuspendGame(); suspendGameErr != nil {
+			termbox.Close()
+			panic(suspendGameErr)
+		}
 	}
 }
@@ -0,0 +1,157 @@
+package mazes
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// The formats of Export, in order of the help.
+var ExportFormats = []string{"json", "svg", "png"}
+
+// The default length of a side of a cell in pixels, for the image formats.
+const DefaultCellSize = 16
+
+type jsonPosition struct {
+	Row int `json:"row"`
+	Column int `json:"column"`
+}
+
+type jsonMaze struct {
+	Rows int `json:"rows"`
+	Columns int `json:"columns"`
+	// The parameters of the generation are omitted if the maze is made from a field.
+	Algorithm string `json:"algorithm,omitempty"`
+	LoopDensity *float64 `json:"loopDensity,omitempty"`
+	Seed *int64 `json:"seed,omitempty"`
+	Start jsonPosition `json:"start"`
+	Goal jsonPosition `json:"goal"`
+	// -1 means that the goal can not be reached.
+	SolutionLength int `json:"solutionLength"`
+	// A row per string, "#" is a wall and "." is a passage.
+	Cells []string `json:"cells"`
+}
+
+// Write the cells and the metadata as JSON.
+func WriteJSON(writer io.Writer, maze *Maze) error {
+	cells := make([]string, 0)
+	for _, row := range maze.Walls {
+		var builder strings.Builder
+		for _, isWall := range row {
+			if isWall {
+				builder.WriteRune('#')
+			} else {
+				builder.WriteRune('.')
+			}
+		}
+		cells = append(cells, builder.String())
+	}
+	encoded := &jsonMaze{
+		Rows: maze.MeasureRowLength(),
+		Columns: maze.MeasureColumnLength(),
+		Start: jsonPosition{Row: maze.Start.Y, Column: maze.Start.X},
+		Goal: jsonPosition{Row: maze.Goal.Y, Column: maze.Goal.X},
+		SolutionLength: maze.MeasureSolutionLength(),
+		Cells: cells,
+	}
+	if maze.Algorithm != "" {
+		encoded.Algorithm = maze.Algorithm
+		encoded.LoopDensity = &maze.LoopDensity
+		encoded.Seed = &maze.Seed
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return errors.WithStack(encoder.Encode(encoded))
+}
+
+// Write the walls as vector rectangles, for printing on paper.
+// Consecutive walls in a row are joined into a rectangle.
+// The start is a circle and the goal is a square.
+func WriteSVG(writer io.Writer, maze *Maze, cellSize int) error {
+	var builder strings.Builder
+	width := maze.MeasureColumnLength() * cellSize
+	height := maze.MeasureRowLength() * cellSize
+	fmt.Fprintf(&builder,
+		"<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		width, height, width, height)
+	fmt.Fprintf(&builder, "<rect width=\"%d\" height=\"%d\" fill=\"white\"/>\n", width, height)
+	builder.WriteString("<g fill=\"black\">\n")
+	for y, row := range maze.Walls {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			length := 1
+			for x + length < len(row) && row[x + length] {
+				length++
+			}
+			fmt.Fprintf(&builder, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\"/>\n",
+				x * cellSize, y * cellSize, length * cellSize, cellSize)
+			x += length - 1
+		}
+	}
+	builder.WriteString("</g>\n")
+	half := float64(cellSize) / 2
+	fmt.Fprintf(&builder,
+		"<circle cx=\"%g\" cy=\"%g\" r=\"%g\" fill=\"none\" stroke=\"black\" stroke-width=\"%g\"/>\n",
+		float64(maze.Start.X * cellSize) + half, float64(maze.Start.Y * cellSize) + half, half * 0.6, half * 0.2)
+	fmt.Fprintf(&builder,
+		"<rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" fill=\"none\" stroke=\"black\" stroke-width=\"%g\"/>\n",
+		float64(maze.Goal.X * cellSize) + half * 0.4, float64(maze.Goal.Y * cellSize) + half * 0.4,
+		half * 1.2, half * 1.2, half * 0.2)
+	builder.WriteString("</svg>\n")
+	_, err := io.WriteString(writer, builder.String())
+	return errors.WithStack(err)
+}
+
+// Write the maze as an image, the start is green and the goal is red.
+func WritePNG(writer io.Writer, maze *Maze, cellSize int) error {
+	palette := color.Palette{
+		color.White,
+		color.Black,
+		color.RGBA{R: 0x00, G: 0x9e, B: 0x73, A: 0xff},
+		color.RGBA{R: 0xd5, G: 0x5e, B: 0x00, A: 0xff},
+	}
+	img := image.NewPaletted(
+		image.Rect(0, 0, maze.MeasureColumnLength() * cellSize, maze.MeasureRowLength() * cellSize), palette)
+	for y, row := range maze.Walls {
+		for x, isWall := range row {
+			var colorIndex uint8
+			switch {
+			case y == maze.Start.Y && x == maze.Start.X:
+				colorIndex = 2
+			case y == maze.Goal.Y && x == maze.Goal.X:
+				colorIndex = 3
+			case isWall:
+				colorIndex = 1
+			}
+			for dy := 0; dy < cellSize; dy++ {
+				for dx := 0; dx < cellSize; dx++ {
+					img.SetColorIndex(x * cellSize + dx, y * cellSize + dy, colorIndex)
+				}
+			}
+		}
+	}
+	return errors.WithStack(png.Encode(writer, img))
+}
+
+// The cell size is ignored by the formats that are not images.
+func Export(writer io.Writer, maze *Maze, format string, cellSize int) error {
+	if cellSize <= 0 {
+		return errors.Errorf("The cell size must be positive.")
+	}
+	switch format {
+	case "json":
+		return WriteJSON(writer, maze)
+	case "svg":
+		return WriteSVG(writer, maze, cellSize)
+	case "png":
+		return WritePNG(writer, maze, cellSize)
+	}
+	return errors.Errorf("The export format \"%s\" does not exist.", format)
+}
@@ -0,0 +1,63 @@
+package mazes
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestExport_NotTD(t *testing.T) {
+	maze, _ := GenerateMaze(&GenerationOptions{RowLength: 7, ColumnLength: 9, Seed: 3})
+
+	t.Run("JSONはセルとメタデータを持つ", func(t *testing.T) {
+		var buffer bytes.Buffer
+		if err := Export(&buffer, maze, "json", DefaultCellSize); err != nil {
+			t.Fatal(err)
+		}
+		var decoded jsonMaze
+		if err := json.Unmarshal(buffer.Bytes(), &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if len(decoded.Cells) != 7 || decoded.Cells[0] != "#########" {
+			t.Fatal("セルが違う")
+		} else if *decoded.Seed != 3 || decoded.SolutionLength != maze.MeasureSolutionLength() {
+			t.Fatal("メタデータが違う")
+		}
+	})
+
+	t.Run("SVGは連続した壁を1つの矩形にまとめる", func(t *testing.T) {
+		var buffer bytes.Buffer
+		if err := Export(&buffer, maze, "svg", 10); err != nil {
+			t.Fatal(err)
+		}
+		svg := buffer.String()
+		if !strings.HasPrefix(svg, "<svg") {
+			t.Fatal("SVGではない")
+		} else if !strings.Contains(svg, "<rect x=\"0\" y=\"0\" width=\"90\" height=\"10\"/>") {
+			t.Fatal("上端の壁がまとまっていない")
+		}
+	})
+
+	t.Run("PNGはセルの大きさに従った画像になる", func(t *testing.T) {
+		var buffer bytes.Buffer
+		if err := Export(&buffer, maze, "png", 4); err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(&buffer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if img.Bounds().Dx() != 36 || img.Bounds().Dy() != 28 {
+			t.Fatal("大きさが違う")
+		}
+	})
+
+	t.Run("存在しない形式はエラーを返す", func(t *testing.T) {
+		var buffer bytes.Buffer
+		if err := Export(&buffer, maze, "gif", DefaultCellSize); err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+}
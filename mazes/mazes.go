@@ -0,0 +1,117 @@
+package mazes
+
+//
+// The "mazes" package handles mazes apart from the game, such as exporting them to files.
+//
+
+import (
+	"github.com/kjirou/tower-of-go/models"
+	"github.com/kjirou/tower-of-go/utils"
+	"math/rand"
+)
+
+// A maze as a matrix of walls.
+type Maze struct {
+	// True means a wall.
+	Walls [][]bool
+	Start *utils.MatrixPosition
+	Goal *utils.MatrixPosition
+	// The parameters of the generation, they are empty if the maze is made from a field.
+	Algorithm string
+	LoopDensity float64
+	Seed int64
+}
+
+func (maze *Maze) MeasureRowLength() int {
+	return len(maze.Walls)
+}
+
+func (maze *Maze) MeasureColumnLength() int {
+	if len(maze.Walls) == 0 {
+		return 0
+	}
+	return len(maze.Walls[0])
+}
+
+func (maze *Maze) IsWall(position *utils.MatrixPosition) bool {
+	return !position.Validate(maze.MeasureRowLength(), maze.MeasureColumnLength()) ||
+		maze.Walls[position.Y][position.X]
+}
+
+// Returns the number of steps from the start to the goal, or -1 if it is unreachable.
+func (maze *Maze) MeasureSolutionLength() int {
+	isPassable := func(position *utils.MatrixPosition) bool {
+		return !maze.IsWall(position)
+	}
+	return utils.FindShortestPathLength(
+		maze.MeasureRowLength(), maze.MeasureColumnLength(), isPassable, maze.Start, maze.Goal)
+}
+
+// The parameters of a generated maze.
+type GenerationOptions struct {
+	RowLength int `json:"rowLength"`
+	ColumnLength int `json:"columnLength"`
+	// "clustering" or "digging", empty means "clustering".
+	Algorithm string `json:"algorithm"`
+	LoopDensity float64 `json:"loopDensity"`
+	// The range of the steps from the start to the goal. Zero means no limit.
+	MinSolutionLength int `json:"minSolutionLength"`
+	MaxSolutionLength int `json:"maxSolutionLength"`
+	Seed int64 `json:"seed"`
+}
+
+// Generate a maze that is the same for the same options.
+// The start is the upper left and the goal is the lower right, as in the game.
+func GenerateMaze(options *GenerationOptions) (*Maze, error) {
+	algorithm, err := utils.ParseMazeAlgorithm(options.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	rowLength := options.RowLength
+	columnLength := options.ColumnLength
+	cells, err := utils.GenerateMazeWithOptions(rowLength, columnLength, &utils.MazeOptions{
+		Algorithm: algorithm,
+		LoopDensity: options.LoopDensity,
+		Random: rand.New(rand.NewSource(options.Seed)),
+		MinSolutionLength: options.MinSolutionLength,
+		MaxSolutionLength: options.MaxSolutionLength,
+	})
+	if err != nil {
+		return nil, err
+	}
+	walls := make([][]bool, rowLength)
+	for y, row := range cells {
+		walls[y] = make([]bool, columnLength)
+		for x, cell := range row {
+			walls[y][x] = cell.Content != utils.MazeCellContentEmpty
+		}
+	}
+	algorithmName := options.Algorithm
+	if algorithmName == "" {
+		algorithmName = "clustering"
+	}
+	return &Maze{
+		Walls: walls,
+		Start: &utils.MatrixPosition{Y: 1, X: 1},
+		Goal: &utils.MatrixPosition{Y: rowLength - 2, X: columnLength - 2},
+		Algorithm: algorithmName,
+		LoopDensity: options.LoopDensity,
+		Seed: options.Seed,
+	}, nil
+}
+
+func CreateMazeFromField(field *models.Field) *Maze {
+	walls := make([][]bool, field.MeasureRowLength())
+	for y := range walls {
+		walls[y] = make([]bool, field.MeasureColumnLength())
+		for x := range walls[y] {
+			element, _ := field.At(&utils.MatrixPosition{Y: y, X: x})
+			walls[y][x] = element.GetObjectClass() == "wall"
+		}
+	}
+	return &Maze{
+		Walls: walls,
+		Start: field.GetStartPosition(),
+		Goal: field.GetUpstairsPosition(),
+	}
+}
@@ -0,0 +1,50 @@
+package mazes
+
+import (
+	"github.com/kjirou/tower-of-go/models"
+	"github.com/kjirou/tower-of-go/utils"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateMaze_NotTD(t *testing.T) {
+	t.Run("同じ種からは同じ迷路を生成する", func(t *testing.T) {
+		a, err := GenerateMaze(&GenerationOptions{RowLength: 13, ColumnLength: 21, Algorithm: "digging", LoopDensity: 0.1, Seed: 42})
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := GenerateMaze(&GenerationOptions{RowLength: 13, ColumnLength: 21, Algorithm: "digging", LoopDensity: 0.1, Seed: 42})
+		if !reflect.DeepEqual(a, b) {
+			t.Fatal("迷路が違う")
+		}
+	})
+
+	t.Run("ゴールへ到達できる", func(t *testing.T) {
+		maze, _ := GenerateMaze(&GenerationOptions{RowLength: 13, ColumnLength: 21, Seed: 1})
+		if maze.MeasureSolutionLength() < 0 {
+			t.Fatal("到達できない")
+		} else if maze.Algorithm != "clustering" {
+			t.Fatal("生成方法が記録されていない")
+		}
+	})
+
+	t.Run("存在しない生成方法はエラーを返す", func(t *testing.T) {
+		if _, err := GenerateMaze(&GenerationOptions{RowLength: 13, ColumnLength: 21, Algorithm: "unknown"}); err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+}
+
+func TestCreateMazeFromField_NotTD(t *testing.T) {
+	t.Run("壁と開始位置とゴールを写す", func(t *testing.T) {
+		field := models.CreateField(3, 5)
+		element, _ := field.At(&utils.MatrixPosition{Y: 0, X: 2})
+		element.UpdateObjectClass("wall")
+		maze := CreateMazeFromField(field)
+		if !maze.Walls[0][2] || maze.Walls[1][2] {
+			t.Fatal("壁が違う")
+		} else if maze.Goal.Y != 1 || maze.Goal.X != 3 {
+			t.Fatal("ゴールが違う")
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package mazes
+
+import (
+	"github.com/kjirou/tower-of-go/utils"
+)
+
+// The shape of a maze in numbers, to compare generators and sizes.
+//
+// The passages are seen as a graph. The nodes are dead ends (one way out) and junctions (three or more),
+// and the corridors are the passages that connect the nodes.
+type MazeStats struct {
+	PassageCount int
+	DeadEndCount int
+	JunctionCount int
+	// The number of corridors per number of steps. A corridor that passes the start or the goal is not split.
+	CorridorLengths map[int]int
+	// The number of steps from the start to the goal, or -1 if it is unreachable.
+	SolutionLength int
+	// The number of junctions on the shortest paths, that is the chances to take a wrong way.
+	DecisionCount int
+	// The mean steps of the corridors that end in a dead end.
+	// High values mean few long dead ends like rivers, low values mean many short ones.
+	RiverFactor float64
+	// The ratio of the walk of a player who enters every side passage once and comes back, to the solution.
+	// It is 1 if the maze has no side passages, and 0 if the goal is unreachable.
+	DifficultyRating float64
+}
+
+func (maze *Maze) countExits(position *utils.MatrixPosition) int {
+	count := 0
+	for _, direction := range utils.FourDirections {
+		if !maze.IsWall(&utils.MatrixPosition{Y: position.Y + direction.Y, X: position.X + direction.X}) {
+			count++
+		}
+	}
+	return count
+}
+
+// Walk from the node to the next node through the corridor.
+// Returns the steps and whether the end is a dead end.
+func (maze *Maze) walkCorridor(node *utils.MatrixPosition, direction utils.MatrixPosition) (int, bool) {
+	previous := node
+	current := &utils.MatrixPosition{Y: node.Y + direction.Y, X: node.X + direction.X}
+	steps := 1
+	for maze.countExits(current) == 2 && !(current.Y == node.Y && current.X == node.X) {
+		for _, nextDirection := range utils.FourDirections {
+			next := &utils.MatrixPosition{Y: current.Y + nextDirection.Y, X: current.X + nextDirection.X}
+			if !maze.IsWall(next) && !(next.Y == previous.Y && next.X == previous.X) {
+				previous, current = current, next
+				break
+			}
+		}
+		steps++
+	}
+	return steps, maze.countExits(current) == 1
+}
+
+func AnalyzeMaze(maze *Maze) *MazeStats {
+	stats := &MazeStats{CorridorLengths: make(map[int]int)}
+	rowLength := maze.MeasureRowLength()
+	columnLength := maze.MeasureColumnLength()
+
+	// Every corridor is walked twice, once from each end.
+	walkedCorridorLengths := make(map[int]int)
+	deadEndCorridorSteps := 0
+	deadEndCorridorCount := 0
+	for y := 0; y < rowLength; y++ {
+		for x := 0; x < columnLength; x++ {
+			position := &utils.MatrixPosition{Y: y, X: x}
+			if maze.IsWall(position) {
+				continue
+			}
+			stats.PassageCount++
+			exitCount := maze.countExits(position)
+			switch {
+			case exitCount == 1:
+				stats.DeadEndCount++
+			case exitCount >= 3:
+				stats.JunctionCount++
+			case exitCount == 2:
+				continue
+			}
+			for _, direction := range utils.FourDirections {
+				if maze.IsWall(&utils.MatrixPosition{Y: y + direction.Y, X: x + direction.X}) {
+					continue
+				}
+				steps, endsInDeadEnd := maze.walkCorridor(position, direction)
+				walkedCorridorLengths[steps]++
+				if exitCount == 1 || endsInDeadEnd {
+					deadEndCorridorSteps += steps
+					deadEndCorridorCount++
+				}
+			}
+		}
+	}
+	for steps, count := range walkedCorridorLengths {
+		stats.CorridorLengths[steps] = count / 2
+	}
+	if deadEndCorridorCount > 0 {
+		stats.RiverFactor = float64(deadEndCorridorSteps) / float64(deadEndCorridorCount)
+	}
+
+	// The cells on any of the shortest paths are the solution.
+	isPassable := func(position *utils.MatrixPosition) bool {
+		return !maze.IsWall(position)
+	}
+	distancesFromStart := utils.MeasureDistances(rowLength, columnLength, isPassable, maze.Start)
+	distancesFromGoal := utils.MeasureDistances(rowLength, columnLength, isPassable, maze.Goal)
+	stats.SolutionLength = maze.MeasureSolutionLength()
+	if stats.SolutionLength < 0 {
+		return stats
+	}
+	solutionCellCount := 0
+	for y := 0; y < rowLength; y++ {
+		for x := 0; x < columnLength; x++ {
+			fromStart := distancesFromStart[y][x]
+			fromGoal := distancesFromGoal[y][x]
+			if fromStart < 0 || fromGoal < 0 || fromStart + fromGoal != stats.SolutionLength {
+				continue
+			}
+			solutionCellCount++
+			if maze.countExits(&utils.MatrixPosition{Y: y, X: x}) >= 3 {
+				stats.DecisionCount++
+			}
+		}
+	}
+	if stats.SolutionLength > 0 {
+		sidePassageCount := stats.PassageCount - solutionCellCount
+		stats.DifficultyRating =
+			float64(stats.SolutionLength + sidePassageCount * 2) / float64(stats.SolutionLength)
+	}
+	return stats
+}
+
+// The means of the stats of mazes that are generated with the same parameters.
+type MazeStatsSummary struct {
+	// The seeds are from Seed of it to Seed + SampleCount - 1.
+	Options *GenerationOptions `json:"options"`
+	SampleCount int `json:"sampleCount"`
+	DeadEndCount float64 `json:"deadEndCount"`
+	JunctionCount float64 `json:"junctionCount"`
+	// The total of all samples.
+	CorridorLengths map[int]int `json:"corridorLengths"`
+	// The unreachable mazes are not included, it is -1 if all are unreachable.
+	SolutionLength float64 `json:"solutionLength"`
+	DecisionCount float64 `json:"decisionCount"`
+	RiverFactor float64 `json:"riverFactor"`
+	DifficultyRating float64 `json:"difficultyRating"`
+}
+
+// Generate and analyze the mazes of the consecutive seeds from the seed of the options.
+func SummarizeGeneratedMazes(options *GenerationOptions, sampleCount int) (*MazeStatsSummary, error) {
+	summaryOptions := *options
+	if summaryOptions.Algorithm == "" {
+		summaryOptions.Algorithm = "clustering"
+	}
+	summary := &MazeStatsSummary{
+		Options: &summaryOptions,
+		SampleCount: sampleCount,
+		CorridorLengths: make(map[int]int),
+	}
+	reachableCount := 0
+	for i := 0; i < sampleCount; i++ {
+		sampleOptions := summaryOptions
+		sampleOptions.Seed += int64(i)
+		maze, err := GenerateMaze(&sampleOptions)
+		if err != nil {
+			return nil, err
+		}
+		stats := AnalyzeMaze(maze)
+		summary.DeadEndCount += float64(stats.DeadEndCount)
+		summary.JunctionCount += float64(stats.JunctionCount)
+		summary.DecisionCount += float64(stats.DecisionCount)
+		summary.RiverFactor += stats.RiverFactor
+		summary.DifficultyRating += stats.DifficultyRating
+		for steps, count := range stats.CorridorLengths {
+			summary.CorridorLengths[steps] += count
+		}
+		if stats.SolutionLength >= 0 {
+			summary.SolutionLength += float64(stats.SolutionLength)
+			reachableCount++
+		}
+	}
+	if sampleCount > 0 {
+		summary.DeadEndCount /= float64(sampleCount)
+		summary.JunctionCount /= float64(sampleCount)
+		summary.DecisionCount /= float64(sampleCount)
+		summary.RiverFactor /= float64(sampleCount)
+		summary.DifficultyRating /= float64(sampleCount)
+	}
+	if reachableCount > 0 {
+		summary.SolutionLength /= float64(reachableCount)
+	} else {
+		summary.SolutionLength = -1
+	}
+	return summary, nil
+}
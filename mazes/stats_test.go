@@ -0,0 +1,97 @@
+package mazes
+
+import (
+	"github.com/kjirou/tower-of-go/utils"
+	"testing"
+)
+
+// "#" is a wall.
+func createMazeFromLines(lines []string, start *utils.MatrixPosition, goal *utils.MatrixPosition) *Maze {
+	walls := make([][]bool, len(lines))
+	for y, line := range lines {
+		walls[y] = make([]bool, len(line))
+		for x, glyph := range line {
+			walls[y][x] = glyph == '#'
+		}
+	}
+	return &Maze{Walls: walls, Start: start, Goal: goal}
+}
+
+func TestAnalyzeMaze_NotTD(t *testing.T) {
+	// The start is the upper left and the goal is the lower right.
+	maze := createMazeFromLines([]string{
+		"#######",
+		"#.....#",
+		"###.###",
+		"#.....#",
+		"#######",
+	}, &utils.MatrixPosition{Y: 1, X: 1}, &utils.MatrixPosition{Y: 3, X: 5})
+
+	t.Run("行き止まりと分岐を数える", func(t *testing.T) {
+		stats := AnalyzeMaze(maze)
+		if stats.DeadEndCount != 4 {
+			t.Fatalf("行き止まりが %d である", stats.DeadEndCount)
+		} else if stats.JunctionCount != 2 {
+			t.Fatalf("分岐が %d である", stats.JunctionCount)
+		}
+	})
+
+	t.Run("通路の長さの分布を数える", func(t *testing.T) {
+		stats := AnalyzeMaze(maze)
+		if len(stats.CorridorLengths) != 1 || stats.CorridorLengths[2] != 5 {
+			t.Fatalf("分布が %v である", stats.CorridorLengths)
+		} else if stats.RiverFactor != 2 {
+			t.Fatal("行き止まりの長さの平均が違う")
+		}
+	})
+
+	t.Run("解の長さと選択の数と難しさを求める", func(t *testing.T) {
+		stats := AnalyzeMaze(maze)
+		if stats.SolutionLength != 6 {
+			t.Fatalf("解の長さが %d である", stats.SolutionLength)
+		} else if stats.DecisionCount != 2 {
+			t.Fatalf("選択が %d である", stats.DecisionCount)
+		} else if stats.DifficultyRating != 14.0 / 6.0 {
+			t.Fatalf("難しさが %f である", stats.DifficultyRating)
+		}
+	})
+
+	t.Run("脇道がなければ難しさは1である", func(t *testing.T) {
+		corridor := createMazeFromLines([]string{
+			"#####",
+			"#...#",
+			"#####",
+		}, &utils.MatrixPosition{Y: 1, X: 1}, &utils.MatrixPosition{Y: 1, X: 3})
+		if AnalyzeMaze(corridor).DifficultyRating != 1 {
+			t.Fatal("1ではない")
+		}
+	})
+
+	t.Run("ゴールへ到達できないとき、解の長さは-1である", func(t *testing.T) {
+		closed := createMazeFromLines([]string{
+			"#####",
+			"#.#.#",
+			"#####",
+		}, &utils.MatrixPosition{Y: 1, X: 1}, &utils.MatrixPosition{Y: 1, X: 3})
+		stats := AnalyzeMaze(closed)
+		if stats.SolutionLength != -1 || stats.DifficultyRating != 0 {
+			t.Fatal("到達できる扱いになっている")
+		}
+	})
+}
+
+func TestSummarizeGeneratedMazes_NotTD(t *testing.T) {
+	t.Run("穴掘り法はクラスタリング法より行き止まりが少なく長い", func(t *testing.T) {
+		clustering, err := SummarizeGeneratedMazes(&GenerationOptions{RowLength: 13, ColumnLength: 21, Seed: 1}, 20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		digging, _ := SummarizeGeneratedMazes(
+			&GenerationOptions{RowLength: 13, ColumnLength: 21, Algorithm: "digging", Seed: 1}, 20)
+		if digging.DeadEndCount >= clustering.DeadEndCount {
+			t.Fatal("行き止まりが少なくない")
+		} else if digging.RiverFactor <= clustering.RiverFactor {
+			t.Fatal("行き止まりが長くない")
+		}
+	})
+}
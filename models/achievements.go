@@ -0,0 +1,87 @@
+package models
+
+import (
+	"github.com/kjirou/tower-of-go/utils"
+	"time"
+)
+
+// The names of the achievements in order of the list.
+var AchievementNames = []string{"firstFloor", "floor10", "optimalPath", "noBacktracking", "sprinter", "dailyStreak7"}
+
+const (
+	// The floor to reach for "floor10".
+	achievementFloorNumber = 10
+	// The time to finish the sprint mode within for "sprinter".
+	achievementSprintTime = 60 * time.Second
+	// The number of consecutive days of the daily challenge for "dailyStreak7".
+	achievementDailyStreak = 7
+)
+
+// Follows the events of the games, and tells the names of the achievements that the events meet.
+// The achievements may be told repeatedly, the caller keeps which ones are already unlocked.
+type AchievementTracker struct {
+	// The positions that the hero has stepped on in the current floor.
+	visitedPositions map[utils.MatrixPosition]bool
+	// The hero has stepped on a visited position in the current game.
+	hasBacktracked bool
+	// A move has been undone in the current game. The game does not unlock achievements after it,
+	// because the moves and the floors can be tried again.
+	hasRewound bool
+}
+
+func (tracker *AchievementTracker) Track(event Event) []string {
+	names := make([]string, 0)
+	switch event := event.(type) {
+	case *GameStartedEvent:
+		tracker.visitedPositions = make(map[utils.MatrixPosition]bool)
+		tracker.hasBacktracked = false
+		tracker.hasRewound = false
+	case *HeroRewoundEvent:
+		tracker.hasRewound = true
+	case *HeroMovedEvent:
+		if tracker.visitedPositions == nil {
+			tracker.visitedPositions = make(map[utils.MatrixPosition]bool)
+		}
+		tracker.visitedPositions[*event.From] = true
+		if tracker.visitedPositions[*event.To] {
+			tracker.hasBacktracked = true
+		}
+		tracker.visitedPositions[*event.To] = true
+	case *FloorClearedEvent:
+		if tracker.hasRewound {
+			break
+		}
+		record := event.Record
+		names = append(names, "firstFloor")
+		if record.FloorNumber + 1 >= achievementFloorNumber {
+			names = append(names, "floor10")
+		}
+		if record.OptimalPathLength > 0 && record.MoveCount == record.OptimalPathLength {
+			names = append(names, "optimalPath")
+		}
+		tracker.visitedPositions = make(map[utils.MatrixPosition]bool)
+	case *GameFinishedEvent:
+		if tracker.hasRewound {
+			break
+		}
+		game := event.Game
+		if !tracker.hasBacktracked && len(game.GetFloorRecords()) > 0 {
+			names = append(names, "noBacktracking")
+		}
+		mode := game.GetMode()
+		if _, ok := mode.(*SprintMode); ok &&
+			game.GetFloorNumber() >= mode.GetGoalFloorNumber() &&
+			game.CalculatePlaytime(event.Time) <= achievementSprintTime {
+			names = append(names, "sprinter")
+		}
+	}
+	return names
+}
+
+// The achievements of the number of consecutive days that have a daily score.
+func (tracker *AchievementTracker) TrackDailyStreak(streak int) []string {
+	if streak >= achievementDailyStreak {
+		return []string{"dailyStreak7"}
+	}
+	return []string{}
+}
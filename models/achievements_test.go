@@ -0,0 +1,112 @@
+package models
+
+import (
+	"github.com/kjirou/tower-of-go/utils"
+	"testing"
+	"time"
+)
+
+func containsAchievementName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAchievementTracker_Track_NotTD(t *testing.T) {
+	t.Run("階を登ると、最短経路や到達した階の実績を伝える", func(t *testing.T) {
+		tracker := &AchievementTracker{}
+		names := tracker.Track(&FloorClearedEvent{Record: &FloorRecord{FloorNumber: 9, MoveCount: 12, OptimalPathLength: 12}})
+		if !containsAchievementName(names, "firstFloor") ||
+			!containsAchievementName(names, "floor10") ||
+			!containsAchievementName(names, "optimalPath") {
+			t.Fatalf("%v is wrong", names)
+		}
+		names = tracker.Track(&FloorClearedEvent{Record: &FloorRecord{FloorNumber: 1, MoveCount: 13, OptimalPathLength: 12}})
+		if containsAchievementName(names, "floor10") || containsAchievementName(names, "optimalPath") {
+			t.Fatalf("%v is wrong", names)
+		}
+	})
+
+	t.Run("同じ階で踏んだ位置へ戻らずに終了すると、後戻りなしの実績を伝える", func(t *testing.T) {
+		game := createGameOfMode(t, "timeAttack")
+		game.Start(0)
+		game.ClearFloor(time.Second)
+		game.Finish(2 * time.Second)
+		moves := []*HeroMovedEvent{
+			&HeroMovedEvent{From: &utils.MatrixPosition{Y: 1, X: 1}, To: &utils.MatrixPosition{Y: 1, X: 2}},
+			&HeroMovedEvent{From: &utils.MatrixPosition{Y: 1, X: 2}, To: &utils.MatrixPosition{Y: 1, X: 3}},
+		}
+
+		tracker := &AchievementTracker{}
+		tracker.Track(&GameStartedEvent{})
+		for _, move := range moves {
+			tracker.Track(move)
+		}
+		// The same positions in the next floor are not a backtrack.
+		tracker.Track(&FloorClearedEvent{Record: &FloorRecord{FloorNumber: 1}})
+		tracker.Track(moves[0])
+		if !containsAchievementName(tracker.Track(&GameFinishedEvent{Game: game}), "noBacktracking") {
+			t.Fatal("伝えない")
+		}
+
+		tracker.Track(&GameStartedEvent{})
+		tracker.Track(moves[0])
+		tracker.Track(&HeroMovedEvent{From: moves[0].To, To: moves[0].From})
+		if containsAchievementName(tracker.Track(&GameFinishedEvent{Game: game}), "noBacktracking") {
+			t.Fatal("後戻りしたのに伝える")
+		}
+	})
+
+	t.Run("戻したゲームでは、次のゲームまで実績を伝えない", func(t *testing.T) {
+		game := createGameOfMode(t, "zen")
+		game.Start(0)
+		game.ClearFloor(time.Second)
+		game.Finish(2 * time.Second)
+		record := &FloorRecord{FloorNumber: 9, MoveCount: 12, OptimalPathLength: 12}
+
+		tracker := &AchievementTracker{}
+		tracker.Track(&GameStartedEvent{})
+		tracker.Track(&HeroRewoundEvent{FloorNumber: 9, Position: &utils.MatrixPosition{Y: 1, X: 1}})
+		if names := tracker.Track(&FloorClearedEvent{Record: record}); len(names) != 0 {
+			t.Fatalf("%v is told", names)
+		} else if names := tracker.Track(&GameFinishedEvent{Game: game}); len(names) != 0 {
+			t.Fatalf("%v is told", names)
+		}
+
+		tracker.Track(&GameStartedEvent{})
+		if !containsAchievementName(tracker.Track(&FloorClearedEvent{Record: record}), "optimalPath") {
+			t.Fatal("次のゲームでも伝えない")
+		}
+	})
+
+	t.Run("スプリントを制限時間内に終えると、スプリンターの実績を伝える", func(t *testing.T) {
+		game := createGameOfMode(t, "sprint")
+		game.Start(time.Second)
+		for game.GetFloorNumber() < game.GetMode().GetGoalFloorNumber() {
+			game.ClearFloor(time.Second)
+		}
+		game.Finish(achievementSprintTime + time.Second)
+		tracker := &AchievementTracker{}
+		if !containsAchievementName(tracker.Track(&GameFinishedEvent{Time: game.finishedAt, Game: game}), "sprinter") {
+			t.Fatal("伝えない")
+		}
+		game.Finish(achievementSprintTime + 2*time.Second)
+		if containsAchievementName(tracker.Track(&GameFinishedEvent{Time: game.finishedAt, Game: game}), "sprinter") {
+			t.Fatal("遅いのに伝える")
+		}
+	})
+}
+
+func TestAchievementTracker_TrackDailyStreak_NotTD(t *testing.T) {
+	t.Run("連続日数が足りたときだけ伝える", func(t *testing.T) {
+		tracker := &AchievementTracker{}
+		if len(tracker.TrackDailyStreak(achievementDailyStreak - 1)) != 0 {
+			t.Fatal("足りないのに伝える")
+		} else if !containsAchievementName(tracker.TrackDailyStreak(achievementDailyStreak), "dailyStreak7") {
+			t.Fatal("伝えない")
+		}
+	})
+}
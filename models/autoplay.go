@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	// The speed of the autoplay, it is about a skilled player who does not get lost.
+	AutoplayMovesPerSecond = 6
+	// Mazes are random, so the score is the average of the plays.
+	autoplaySampleCount = 5
+	// It stops the modes that the autoplay never finishes.
+	autoplayMaxFloorNumber = 50
+)
+
+// The optimum play that walks the shortest paths at a constant speed.
+type AutoplayResult struct {
+	// The average of the scores of the mode.
+	Score float64
+}
+
+func playAutomatically(mode GameMode, difficulty *Difficulty, seed int64) (float64, error) {
+	game := &Game{}
+	game.SetMode(mode)
+	game.SetDifficulty(difficulty)
+	game.SetFixedSeed(seed)
+	game.Reset()
+	// The zero time means that the game has not started.
+	executionTime := time.Second
+	game.Start(executionTime)
+
+	for game.GetFloorNumber() < autoplayMaxFloorNumber {
+		field, err := CreateFloorField(game)
+		if err != nil {
+			return 0, err
+		}
+		pathLength := field.MeasureShortestPathLength(field.GetStartPosition(), field.GetUpstairsPosition())
+		nextExecutionTime := executionTime +
+			time.Duration(float64(pathLength) / AutoplayMovesPerSecond * float64(time.Second))
+		// The time runs out on the way.
+		if game.HasTimeLimit() && game.CalculateRemainingTime(nextExecutionTime) == 0 {
+			break
+		}
+		executionTime = nextExecutionTime
+		game.BeginFloor(executionTime, pathLength)
+		game.ClearFloor(executionTime)
+		if mode.IsFinished(game, executionTime) {
+			break
+		}
+	}
+
+	game.Finish(executionTime)
+	return game.CalculateScore(executionTime), nil
+}
+
+// The plays have fixed seeds, so that the same mode and difficulty always have the same result.
+func SimulateAutoplay(mode GameMode, difficulty *Difficulty) (*AutoplayResult, error) {
+	total := 0.0
+	for i := 0; i < autoplaySampleCount; i++ {
+		score, err := playAutomatically(mode, difficulty, int64(i + 1))
+		if err != nil {
+			return nil, err
+		}
+		total += score
+	}
+	return &AutoplayResult{Score: total / autoplaySampleCount}, nil
+}
@@ -0,0 +1,242 @@
+package models
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/binary"
+	"github.com/kjirou/tower-of-go/config"
+	"github.com/kjirou/tower-of-go/utils"
+	"github.com/pkg/errors"
+	"hash/crc32"
+	"math"
+	"math/bits"
+	"strings"
+)
+
+// The rules of a game with generated floors. It is shared as a short code, and the same game is started from it.
+type Challenge struct {
+	ModeName string
+	DifficultyName string
+	// The field size of the first floor.
+	RowLength int
+	ColumnLength int
+	Algorithm utils.MazeAlgorithm
+	Seed int64
+	// All settings of the "custom" difficulty, because the config of the receiver is different.
+	// It is nil for the other difficulties.
+	CustomDifficulty *config.DifficultyConfig
+}
+
+const (
+	challengeCodeVersion = 2
+	challengeChecksumLength = 2
+	// The code is split into groups of the length with "-" to be read easily.
+	challengeCodeGroupLength = 4
+	// The largest field length of a challenge. The values of a code are rejected above it before they are used.
+	maxChallengeFieldLength = 255
+)
+
+var challengeCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func findNameIndex(names []string, name string) int {
+	for i, candidate := range names {
+		if candidate == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func calculateChallengeChecksum(payload []byte) []byte {
+	checksum := make([]byte, 4)
+	binary.BigEndian.PutUint32(checksum, crc32.ChecksumIEEE(payload))
+	return checksum[:challengeChecksumLength]
+}
+
+// The bytes of a float are reversed, so that the round numbers such as 1.5 become short varints.
+func encodeChallengeFloat(value float64) uint64 {
+	return bits.ReverseBytes64(math.Float64bits(value))
+}
+
+func decodeChallengeFloat(value uint64) float64 {
+	return math.Float64frombits(bits.ReverseBytes64(value))
+}
+
+// Returns nil if the floors of the game are not generated, such as a hand-made tower.
+func (game *Game) CreateChallenge() *Challenge {
+	if _, ok := game.GetMode().(FloorProvider); ok {
+		return nil
+	} else if findNameIndex(GameModeNames, game.GetMode().GetName()) == -1 {
+		return nil
+	}
+	difficulty := game.GetDifficulty()
+	challenge := &Challenge{
+		ModeName: game.GetMode().GetName(),
+		DifficultyName: difficulty.GetName(),
+		RowLength: difficulty.rowLength,
+		ColumnLength: difficulty.columnLength,
+		Algorithm: difficulty.algorithm,
+		Seed: game.GetSeed(),
+	}
+	if difficulty.GetName() == "custom" {
+		challenge.CustomDifficulty = difficulty.ToConfig()
+	}
+	return challenge
+}
+
+// Returns a code such as "AIAA-CAAN-CWVK-VXGJ-AME3-6".
+func (challenge *Challenge) Encode() string {
+	payload := make([]byte, 0)
+	buffer := make([]byte, binary.MaxVarintLen64)
+	for _, value := range []int{
+		challengeCodeVersion,
+		findNameIndex(GameModeNames, challenge.ModeName),
+		findNameIndex(DifficultyNames, challenge.DifficultyName),
+		int(challenge.Algorithm),
+		challenge.RowLength,
+		challenge.ColumnLength,
+	} {
+		payload = append(payload, buffer[:binary.PutUvarint(buffer, uint64(value))]...)
+	}
+	if challenge.DifficultyName == "custom" {
+		// The zero values inherit the settings of "normal", as in the config.
+		customDifficulty := challenge.CustomDifficulty
+		if customDifficulty == nil {
+			customDifficulty = &config.DifficultyConfig{}
+		}
+		for _, value := range []uint64{
+			uint64(customDifficulty.GrowthInterval),
+			uint64(customDifficulty.MaxRowLength),
+			uint64(customDifficulty.MaxColumnLength),
+			encodeChallengeFloat(customDifficulty.LoopDensity),
+			encodeChallengeFloat(customDifficulty.TimeLimitRate),
+			encodeChallengeFloat(customDifficulty.LoopDensityDelta),
+			encodeChallengeFloat(customDifficulty.MinSolutionRatio),
+			encodeChallengeFloat(customDifficulty.MaxSolutionRatio),
+		} {
+			payload = append(payload, buffer[:binary.PutUvarint(buffer, value)]...)
+		}
+	}
+	payload = append(payload, buffer[:binary.PutVarint(buffer, challenge.Seed)]...)
+	encoded := challengeCodeEncoding.EncodeToString(append(payload, calculateChallengeChecksum(payload)...))
+
+	groups := make([]string, 0)
+	for len(encoded) > challengeCodeGroupLength {
+		groups = append(groups, encoded[:challengeCodeGroupLength])
+		encoded = encoded[challengeCodeGroupLength:]
+	}
+	return strings.Join(append(groups, encoded), "-")
+}
+
+// The difficulty of the name that has the field size and the generator of the challenge.
+// A custom difficulty is created from the settings of the sharer.
+func (challenge *Challenge) CreateDifficulty() (*Difficulty, error) {
+	var customDifficulty *config.DifficultyConfig
+	if challenge.DifficultyName == "custom" {
+		customDifficulty = &config.DifficultyConfig{}
+		if challenge.CustomDifficulty != nil {
+			copiedCustomDifficulty := *challenge.CustomDifficulty
+			customDifficulty = &copiedCustomDifficulty
+		}
+		customDifficulty.RowLength = challenge.RowLength
+		customDifficulty.ColumnLength = challenge.ColumnLength
+		customDifficulty.Algorithm = utils.MazeAlgorithmNames[challenge.Algorithm]
+	}
+	difficulty, err := CreateDifficulty(challenge.DifficultyName, customDifficulty)
+	if err != nil {
+		return nil, err
+	}
+	if difficulty.rowLength != challenge.RowLength || difficulty.columnLength != challenge.ColumnLength ||
+		difficulty.algorithm != challenge.Algorithm {
+		return nil, errors.Errorf("The challenge does not match the difficulty \"%s\".", challenge.DifficultyName)
+	}
+	return difficulty, nil
+}
+
+// The letters are case-insensitive, and the separators are ignored.
+func DecodeChallenge(code string) (*Challenge, error) {
+	normalizedCode := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	data, err := challengeCodeEncoding.DecodeString(normalizedCode)
+	if err != nil || len(data) <= challengeChecksumLength {
+		return nil, errors.Errorf("The challenge code \"%s\" is invalid.", code)
+	}
+	payload := data[:len(data)-challengeChecksumLength]
+	if !bytes.Equal(data[len(payload):], calculateChallengeChecksum(payload)) {
+		return nil, errors.Errorf("The challenge code \"%s\" is mistyped.", code)
+	}
+
+	reader := bytes.NewReader(payload)
+	values := make([]int, 6)
+	for i := range values {
+		value, err := binary.ReadUvarint(reader)
+		// The values are the version, indexes and field lengths, they are never larger than a field length.
+		if err != nil || value > maxChallengeFieldLength {
+			return nil, errors.Errorf("The challenge code \"%s\" is invalid.", code)
+		}
+		values[i] = int(value)
+	}
+	if values[0] != challengeCodeVersion {
+		return nil, errors.Errorf("The challenge code \"%s\" is for another version.", code)
+	}
+	if values[1] < 0 || values[1] >= len(GameModeNames) ||
+		values[2] < 0 || values[2] >= len(DifficultyNames) ||
+		values[3] < 0 || values[3] >= len(utils.MazeAlgorithmNames) {
+		return nil, errors.Errorf("The challenge code \"%s\" is invalid.", code)
+	}
+	for _, length := range values[4:6] {
+		if err := validateFieldLength(length); err != nil {
+			return nil, errors.Wrapf(err, "The challenge code \"%s\" is invalid.", code)
+		}
+	}
+	// The other settings of the custom difficulty follow, the counts and lengths and then the rates.
+	var customDifficulty *config.DifficultyConfig
+	if values[2] == findNameIndex(DifficultyNames, "custom") {
+		customValues := make([]uint64, 8)
+		for i := range customValues {
+			value, err := binary.ReadUvarint(reader)
+			if err != nil {
+				return nil, errors.Errorf("The challenge code \"%s\" is invalid.", code)
+			}
+			customValues[i] = value
+		}
+		customDifficulty = &config.DifficultyConfig{
+			RowLength: values[4],
+			ColumnLength: values[5],
+			Algorithm: utils.MazeAlgorithmNames[values[3]],
+		}
+		for _, value := range customValues[:3] {
+			if value > maxChallengeFieldLength {
+				return nil, errors.Errorf("The challenge code \"%s\" is invalid.", code)
+			}
+		}
+		customDifficulty.GrowthInterval = int(customValues[0])
+		customDifficulty.MaxRowLength = int(customValues[1])
+		customDifficulty.MaxColumnLength = int(customValues[2])
+		floatValues := make([]float64, 0)
+		for _, value := range customValues[3:] {
+			floatValue := decodeChallengeFloat(value)
+			if math.IsNaN(floatValue) || math.IsInf(floatValue, 0) {
+				return nil, errors.Errorf("The challenge code \"%s\" is invalid.", code)
+			}
+			floatValues = append(floatValues, floatValue)
+		}
+		customDifficulty.LoopDensity = floatValues[0]
+		customDifficulty.TimeLimitRate = floatValues[1]
+		customDifficulty.LoopDensityDelta = floatValues[2]
+		customDifficulty.MinSolutionRatio = floatValues[3]
+		customDifficulty.MaxSolutionRatio = floatValues[4]
+	}
+	seed, err := binary.ReadVarint(reader)
+	if err != nil || reader.Len() > 0 {
+		return nil, errors.Errorf("The challenge code \"%s\" is invalid.", code)
+	}
+	return &Challenge{
+		ModeName: GameModeNames[values[1]],
+		DifficultyName: DifficultyNames[values[2]],
+		Algorithm: utils.MazeAlgorithm(values[3]),
+		RowLength: values[4],
+		ColumnLength: values[5],
+		Seed: seed,
+		CustomDifficulty: customDifficulty,
+	}, nil
+}
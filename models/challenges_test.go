@@ -0,0 +1,173 @@
+package models
+
+import (
+	"encoding/binary"
+	"github.com/kjirou/tower-of-go/config"
+	"github.com/kjirou/tower-of-go/utils"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChallenge_Encode_NotTD(t *testing.T) {
+	t.Run("復号すると同じ内容になる", func(t *testing.T) {
+		challenge := &Challenge{
+			ModeName: "survival",
+			DifficultyName: "custom",
+			RowLength: 21,
+			ColumnLength: 41,
+			Algorithm: utils.MazeAlgorithmDigging,
+			Seed: -12345678901,
+			CustomDifficulty: &config.DifficultyConfig{
+				RowLength: 21,
+				ColumnLength: 41,
+				Algorithm: "digging",
+				LoopDensity: 0.05,
+				TimeLimitRate: 1.5,
+				GrowthInterval: 3,
+				MaxRowLength: 31,
+				MaxColumnLength: 61,
+				LoopDensityDelta: -0.01,
+				MinSolutionRatio: 1.2,
+				MaxSolutionRatio: 2,
+			},
+		}
+		decoded, err := DecodeChallenge(challenge.Encode())
+		if err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(decoded, challenge) {
+			t.Fatal("内容が違う")
+		}
+	})
+
+	t.Run("小文字や区切りがなくても復号できる", func(t *testing.T) {
+		challenge := &Challenge{ModeName: "sprint", DifficultyName: "hard", RowLength: 13, ColumnLength: 21,
+			Algorithm: utils.MazeAlgorithmDigging, Seed: 20240131}
+		code := strings.ToLower(strings.Replace(challenge.Encode(), "-", "", -1))
+		if decoded, err := DecodeChallenge(code); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(decoded, challenge) {
+			t.Fatal("内容が違う")
+		}
+	})
+
+	t.Run("引いた種のコードは短い", func(t *testing.T) {
+		game := &Game{}
+		game.Reset()
+		if code := game.CreateChallenge().Encode(); len(strings.Replace(code, "-", "", -1)) > 21 {
+			t.Fatalf("%s is too long", code)
+		}
+	})
+}
+
+func TestDecodeChallenge_NotTD(t *testing.T) {
+	challenge := &Challenge{ModeName: "timeAttack", DifficultyName: "normal", RowLength: 13, ColumnLength: 21, Seed: 1}
+
+	t.Run("1文字違うと検査値で弾く", func(t *testing.T) {
+		code := []byte(challenge.Encode())
+		if code[0] == 'A' {
+			code[0] = 'B'
+		} else {
+			code[0] = 'A'
+		}
+		if _, err := DecodeChallenge(string(code)); err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+
+	t.Run("検査値が合っていても値が範囲外ならエラーを返す", func(t *testing.T) {
+		encode := func(values ...uint64) string {
+			payload := make([]byte, 0)
+			buffer := make([]byte, binary.MaxVarintLen64)
+			for _, value := range values {
+				payload = append(payload, buffer[:binary.PutUvarint(buffer, value)]...)
+			}
+			payload = append(payload, buffer[:binary.PutVarint(buffer, 1)]...)
+			return challengeCodeEncoding.EncodeToString(append(payload, calculateChallengeChecksum(payload)...))
+		}
+		for _, code := range []string{
+			"AH77777777777777AEAAADIVBJ4FK",
+			encode(challengeCodeVersion, math.MaxUint64, 1, 0, 13, 21),
+			encode(challengeCodeVersion, 0, uint64(len(DifficultyNames)), 0, 13, 21),
+			encode(challengeCodeVersion, 0, 3, 0, 4, 21),
+			encode(challengeCodeVersion, 0, 3, 0, 13, maxChallengeFieldLength + 2),
+			encode(challengeCodeVersion, 0, 3, 0, 13, 1 << 40),
+		} {
+			if _, err := DecodeChallenge(code); err == nil {
+				t.Fatalf("%s is decoded", code)
+			}
+		}
+	})
+
+	t.Run("カスタムの難易度の割合が有限の数でなければエラーを返す", func(t *testing.T) {
+		code := (&Challenge{ModeName: "timeAttack", DifficultyName: "custom", RowLength: 13, ColumnLength: 21,
+			CustomDifficulty: &config.DifficultyConfig{TimeLimitRate: math.NaN()}}).Encode()
+		if _, err := DecodeChallenge(code); err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+
+	t.Run("base32ではないときエラーを返す", func(t *testing.T) {
+		if _, err := DecodeChallenge("not a code!"); err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+}
+
+func TestChallenge_CreateDifficulty_NotTD(t *testing.T) {
+	t.Run("組み込みの難易度と広さが合わないときエラーを返す", func(t *testing.T) {
+		challenge := &Challenge{ModeName: "timeAttack", DifficultyName: "normal", RowLength: 15, ColumnLength: 21, Seed: 1}
+		if _, err := challenge.CreateDifficulty(); err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+
+	t.Run("カスタムの難易度は広さと生成方法と共有された設定を使う", func(t *testing.T) {
+		challenge := &Challenge{ModeName: "timeAttack", DifficultyName: "custom", RowLength: 7, ColumnLength: 9,
+			Algorithm: utils.MazeAlgorithmDigging, Seed: 1,
+			CustomDifficulty: &config.DifficultyConfig{TimeLimitRate: 3, GrowthInterval: 1, MaxRowLength: 9}}
+		difficulty, err := challenge.CreateDifficulty()
+		if err != nil {
+			t.Fatal(err)
+		}
+		floorSettings := difficulty.CalculateFloorSettings(1)
+		if floorSettings.RowLength != 7 || floorSettings.ColumnLength != 9 ||
+			floorSettings.MazeOptions.Algorithm != utils.MazeAlgorithmDigging {
+			t.Fatal("設定が違う")
+		} else if difficulty.timeLimitRate != 3 || difficulty.growthInterval != 1 || difficulty.maxRowLength != 9 {
+			t.Fatal("共有された設定を使っていない")
+		}
+	})
+
+	t.Run("カスタムの難易度で作ったコードから同じ難易度に戻る", func(t *testing.T) {
+		original, err := CreateDifficulty("custom", &config.DifficultyConfig{
+			RowLength: 9, ColumnLength: 15, Algorithm: "digging", LoopDensity: 0.3, TimeLimitRate: 2.5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		game := &Game{}
+		game.Reset()
+		game.SetDifficulty(original)
+		challenge, err := DecodeChallenge(game.CreateChallenge().Encode())
+		if err != nil {
+			t.Fatal(err)
+		}
+		difficulty, err := challenge.CreateDifficulty()
+		if err != nil {
+			t.Fatal(err)
+		} else if *difficulty != *original {
+			t.Fatal("難易度が違う")
+		}
+	})
+}
+
+func TestGame_CreateChallenge_NotTD(t *testing.T) {
+	t.Run("手作りの塔はnilを返す", func(t *testing.T) {
+		game := &Game{}
+		game.SetMode(&CustomTowerMode{})
+		if game.CreateChallenge() != nil {
+			t.Fatal("nilではない")
+		}
+	})
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// The format of the dates of the daily challenges.
+const DailyDateLayout = "2006-01-02"
+
+// Returns the date of the daily challenge, it changes at midnight in UTC for all players.
+func FormatDailyDate(now time.Time) string {
+	return now.UTC().Format(DailyDateLayout)
+}
+
+// Returns the seed of the daily challenge, such as 20240131. All players get the same floors on the date.
+func CalculateDailySeed(now time.Time) int64 {
+	year, month, day := now.UTC().Date()
+	return int64(year * 10000 + int(month) * 100 + day)
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateDailySeed_NotTD(t *testing.T) {
+	t.Run("UTCの日付から決まる", func(t *testing.T) {
+		tokyo := time.FixedZone("Asia/Tokyo", 9 * 60 * 60)
+		if CalculateDailySeed(time.Date(2024, time.February, 1, 8, 0, 0, 0, tokyo)) != 20240131 {
+			t.Fatal("種が違う")
+		}
+	})
+
+	t.Run("同じ日付なら同じ種になる", func(t *testing.T) {
+		morning := time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+		night := time.Date(2024, time.January, 31, 23, 59, 59, 0, time.UTC)
+		if CalculateDailySeed(morning) != CalculateDailySeed(night) {
+			t.Fatal("種が違う")
+		}
+	})
+}
+
+func TestFormatDailyDate_NotTD(t *testing.T) {
+	t.Run("UTCの日付を返す", func(t *testing.T) {
+		tokyo := time.FixedZone("Asia/Tokyo", 9 * 60 * 60)
+		if FormatDailyDate(time.Date(2024, time.February, 1, 8, 0, 0, 0, tokyo)) != "2024-01-31" {
+			t.Fatal("日付が違う")
+		}
+	})
+}
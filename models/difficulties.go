@@ -0,0 +1,216 @@
+package models
+
+import (
+	"github.com/kjirou/tower-of-go/config"
+	"github.com/kjirou/tower-of-go/utils"
+	"github.com/pkg/errors"
+	"math"
+)
+
+// The generator settings of a floor.
+type FloorSettings struct {
+	RowLength int
+	ColumnLength int
+	MazeOptions *utils.MazeOptions
+}
+
+// A difficulty profile. It defines the first floor and how the following floors progress.
+type Difficulty struct {
+	name string
+	// The field size of the first floor. Both must be odd numbers of at least 5.
+	rowLength int
+	columnLength int
+	algorithm utils.MazeAlgorithm
+	loopDensity float64
+	// The rate to multiply the time limits of the game mode.
+	timeLimitRate float64
+	// The progression curve.
+	// Every growthInterval floors, the field grows by 2 rows and 4 columns up to the max size,
+	// and the loop density changes by loopDensityDelta within 0 to 1.
+	growthInterval int
+	maxRowLength int
+	maxColumnLength int
+	loopDensityDelta float64
+	// The band of the solution length, as the ratio to the shortest possible steps on the field.
+	// It keeps floors from being trivial or brutal. Zero means no limit.
+	minSolutionRatio float64
+	maxSolutionRatio float64
+}
+
+func (difficulty *Difficulty) GetName() string {
+	return difficulty.name
+}
+
+func (difficulty *Difficulty) GetTimeLimitRate() float64 {
+	return difficulty.timeLimitRate
+}
+
+func (difficulty *Difficulty) CalculateFloorSettings(floorNumber int) *FloorSettings {
+	steps := 0
+	if difficulty.growthInterval > 0 && floorNumber > 1 {
+		steps = (floorNumber - 1) / difficulty.growthInterval
+	}
+
+	rowLength := difficulty.rowLength + steps * 2
+	if rowLength > difficulty.maxRowLength {
+		rowLength = difficulty.maxRowLength
+	}
+	columnLength := difficulty.columnLength + steps * 4
+	if columnLength > difficulty.maxColumnLength {
+		columnLength = difficulty.maxColumnLength
+	}
+	loopDensity := difficulty.loopDensity + difficulty.loopDensityDelta * float64(steps)
+	if loopDensity < 0 {
+		loopDensity = 0
+	} else if loopDensity > 1 {
+		loopDensity = 1
+	}
+
+	// The steps from the start to the upstairs without walls.
+	straightLength := float64(rowLength - 3 + columnLength - 3)
+
+	return &FloorSettings{
+		RowLength: rowLength,
+		ColumnLength: columnLength,
+		MazeOptions: &utils.MazeOptions{
+			Algorithm: difficulty.algorithm,
+			LoopDensity: loopDensity,
+			MinSolutionLength: int(math.Ceil(straightLength * difficulty.minSolutionRatio)),
+			MaxSolutionLength: int(math.Floor(straightLength * difficulty.maxSolutionRatio)),
+		},
+	}
+}
+
+func validateFieldLength(length int) error {
+	if length < 5 || length % 2 != 1 {
+		return errors.Errorf("The field length (%d) must be an odd number of at least 5.", length)
+	}
+	return nil
+}
+
+// The names of the difficulties in order of the settings.
+var DifficultyNames = []string{"easy", "normal", "hard", "custom"}
+
+const DefaultDifficultyName = "normal"
+
+var builtinDifficulties = map[string]Difficulty{
+	"easy": Difficulty{
+		rowLength: 11,
+		columnLength: 17,
+		algorithm: utils.MazeAlgorithmClustering,
+		loopDensity: 0.2,
+		timeLimitRate: 1.5,
+		growthInterval: 4,
+		maxRowLength: 13,
+		maxColumnLength: 21,
+		loopDensityDelta: -0.05,
+		maxSolutionRatio: 1.4,
+	},
+	"normal": Difficulty{
+		rowLength: 13,
+		columnLength: 21,
+		algorithm: utils.MazeAlgorithmClustering,
+		loopDensity: 0,
+		timeLimitRate: 1,
+		growthInterval: 5,
+		maxRowLength: 15,
+		maxColumnLength: 25,
+		loopDensityDelta: 0,
+		minSolutionRatio: 1.15,
+		maxSolutionRatio: 1.7,
+	},
+	"hard": Difficulty{
+		rowLength: 13,
+		columnLength: 21,
+		algorithm: utils.MazeAlgorithmDigging,
+		loopDensity: 0,
+		timeLimitRate: 0.8,
+		growthInterval: 3,
+		maxRowLength: 17,
+		maxColumnLength: 31,
+		loopDensityDelta: 0,
+		minSolutionRatio: 2,
+		maxSolutionRatio: 3.2,
+	},
+}
+
+// The "custom" difficulty is created from customDifficulty, a nil value means the same as "normal".
+func CreateDifficulty(name string, customDifficulty *config.DifficultyConfig) (*Difficulty, error) {
+	if name == "" {
+		name = DefaultDifficultyName
+	}
+	if name != "custom" {
+		builtinDifficulty, ok := builtinDifficulties[name]
+		if !ok {
+			return nil, errors.Errorf("The difficulty \"%s\" does not exist.", name)
+		}
+		difficulty := builtinDifficulty
+		difficulty.name = name
+		return &difficulty, nil
+	}
+
+	difficulty := builtinDifficulties[DefaultDifficultyName]
+	difficulty.name = name
+	if customDifficulty == nil {
+		return &difficulty, nil
+	}
+	if customDifficulty.RowLength != 0 {
+		difficulty.rowLength = customDifficulty.RowLength
+	}
+	if customDifficulty.ColumnLength != 0 {
+		difficulty.columnLength = customDifficulty.ColumnLength
+	}
+	algorithm, err := utils.ParseMazeAlgorithm(customDifficulty.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	difficulty.algorithm = algorithm
+	if customDifficulty.LoopDensity != 0 {
+		difficulty.loopDensity = customDifficulty.LoopDensity
+	}
+	if customDifficulty.TimeLimitRate != 0 {
+		difficulty.timeLimitRate = customDifficulty.TimeLimitRate
+	}
+	if customDifficulty.GrowthInterval != 0 {
+		difficulty.growthInterval = customDifficulty.GrowthInterval
+	}
+	if customDifficulty.MaxRowLength != 0 {
+		difficulty.maxRowLength = customDifficulty.MaxRowLength
+	}
+	if customDifficulty.MaxColumnLength != 0 {
+		difficulty.maxColumnLength = customDifficulty.MaxColumnLength
+	}
+	// The field does not shrink.
+	if difficulty.maxRowLength < difficulty.rowLength {
+		difficulty.maxRowLength = difficulty.rowLength
+	}
+	if difficulty.maxColumnLength < difficulty.columnLength {
+		difficulty.maxColumnLength = difficulty.columnLength
+	}
+	if customDifficulty.LoopDensityDelta != 0 {
+		difficulty.loopDensityDelta = customDifficulty.LoopDensityDelta
+	}
+	if customDifficulty.MinSolutionRatio != 0 {
+		difficulty.minSolutionRatio = customDifficulty.MinSolutionRatio
+	}
+	if customDifficulty.MaxSolutionRatio != 0 {
+		difficulty.maxSolutionRatio = customDifficulty.MaxSolutionRatio
+	}
+
+	for _, length := range []int{
+		difficulty.rowLength, difficulty.columnLength, difficulty.maxRowLength, difficulty.maxColumnLength} {
+		if err := validateFieldLength(length); err != nil {
+			return nil, err
+		}
+	}
+	if difficulty.timeLimitRate < 0 {
+		return nil, errors.Errorf("The time limit rate must not be negative.")
+	}
+	if difficulty.minSolutionRatio < 0 || difficulty.maxSolutionRatio < 0 {
+		return nil, errors.Errorf("The solution ratios must not be negative.")
+	} else if difficulty.maxSolutionRatio > 0 && difficulty.minSolutionRatio > difficulty.maxSolutionRatio {
+		return nil, errors.Errorf("The min solution ratio must not be greater than the max one.")
+	}
+
+	return &difficulty, nil
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"github.com/kjirou/tower-of-go/config"
+	"github.com/kjirou/tower-of-go/utils"
+	"testing"
+	"time"
+)
+
+func TestDifficulty_CalculateFloorSettings_NotTD(t *testing.T) {
+	t.Run("ふつうの1階は従来の広さである", func(t *testing.T) {
+		difficulty, _ := CreateDifficulty("normal", nil)
+		settings := difficulty.CalculateFloorSettings(1)
+		if settings.RowLength != 13 || settings.ColumnLength != 21 {
+			t.Fatal("広さが違う")
+		}
+	})
+
+	t.Run("一定の階ごとに広くなり、最大で止まる", func(t *testing.T) {
+		difficulty, _ := CreateDifficulty("hard", nil)
+		if difficulty.CalculateFloorSettings(3).RowLength != 13 {
+			t.Fatal("間隔の前に広くなっている")
+		}
+		settings := difficulty.CalculateFloorSettings(4)
+		if settings.RowLength != 15 || settings.ColumnLength != 25 {
+			t.Fatal("広くなっていない")
+		}
+		settings = difficulty.CalculateFloorSettings(100)
+		if settings.RowLength != 17 || settings.ColumnLength != 31 {
+			t.Fatal("最大で止まっていない")
+		}
+	})
+
+	t.Run("解の長さの範囲は広さに比例する", func(t *testing.T) {
+		difficulty, _ := CreateDifficulty("normal", nil)
+		options := difficulty.CalculateFloorSettings(1).MazeOptions
+		if options.MinSolutionLength != 33 || options.MaxSolutionLength != 47 {
+			t.Fatal("範囲が違う")
+		}
+		options = difficulty.CalculateFloorSettings(100).MazeOptions
+		if options.MinSolutionLength != 40 || options.MaxSolutionLength != 57 {
+			t.Fatal("広い階の範囲が違う")
+		}
+	})
+
+	t.Run("ループ密度は0未満にならない", func(t *testing.T) {
+		difficulty, _ := CreateDifficulty("easy", nil)
+		if difficulty.CalculateFloorSettings(100).MazeOptions.LoopDensity != 0 {
+			t.Fatal("0ではない")
+		}
+	})
+}
+
+func TestCreateDifficulty_NotTD(t *testing.T) {
+	t.Run("存在しない難易度はエラーを返す", func(t *testing.T) {
+		_, err := CreateDifficulty("unknown", nil)
+		if err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+
+	t.Run("カスタムは指定しない値をふつうから継承する", func(t *testing.T) {
+		difficulty, err := CreateDifficulty("custom", &config.DifficultyConfig{
+			RowLength: 21,
+			Algorithm: "digging",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		settings := difficulty.CalculateFloorSettings(1)
+		if settings.RowLength != 21 || settings.ColumnLength != 21 {
+			t.Fatal("広さが違う")
+		} else if settings.MazeOptions.Algorithm != utils.MazeAlgorithmDigging {
+			t.Fatal("生成方法が違う")
+		} else if difficulty.CalculateFloorSettings(100).RowLength != 21 {
+			t.Fatal("最大が1階より狭い")
+		}
+	})
+
+	t.Run("カスタムの解の長さの下限が上限より大きいとき、エラーを返す", func(t *testing.T) {
+		_, err := CreateDifficulty("custom", &config.DifficultyConfig{MinSolutionRatio: 2, MaxSolutionRatio: 1.5})
+		if err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+
+	t.Run("カスタムの広さが偶数のとき、エラーを返す", func(t *testing.T) {
+		_, err := CreateDifficulty("custom", &config.DifficultyConfig{ColumnLength: 20})
+		if err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+}
+
+func TestGame_CalculateTimeLimit_NotTD(t *testing.T) {
+	t.Run("難易度の倍率を掛ける", func(t *testing.T) {
+		game := &Game{}
+		difficulty, _ := CreateDifficulty("easy", nil)
+		game.SetDifficulty(difficulty)
+		if game.CalculateTimeLimit(1) != 45*time.Second {
+			t.Fatal("制限時間が違う")
+		}
+	})
+}
+
+func TestCreateFloorField_NotTD(t *testing.T) {
+	t.Run("難易度に従った広さの迷路を生成する", func(t *testing.T) {
+		game := &Game{}
+		difficulty, _ := CreateDifficulty("hard", nil)
+		game.SetDifficulty(difficulty)
+		game.Reset()
+		for i := 0; i < 3; i++ {
+			game.IncrementFloorNumber()
+		}
+		field, err := CreateFloorField(game)
+		if err != nil {
+			t.Fatal(err)
+		} else if field.MeasureRowLength() != 15 || field.MeasureColumnLength() != 25 {
+			t.Fatal("広さが違う")
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"github.com/kjirou/tower-of-go/utils"
+	"github.com/pkg/errors"
+	"time"
+)
+
+type EditorTile int
+const (
+	EditorTileWall EditorTile = iota
+	EditorTileFloor
+	EditorTileStart
+	EditorTileUpstairs
+)
+
+// In order of the palette.
+var EditorTiles = []EditorTile{EditorTileWall, EditorTileFloor, EditorTileStart, EditorTileUpstairs}
+
+// A message to the author, such as the result of the check or the save.
+type EditorNotice struct {
+	// A key of the message catalog.
+	MessageKey string
+	// It is passed to the message, such as the reason of an error.
+	Detail string
+	IsError bool
+}
+
+// A floor that is being edited in the terminal.
+type Editor struct {
+	// The map file that is saved to.
+	filePath string
+	name string
+	timeLimit time.Duration
+	// The hero and the upstairs are not placed on it, the start and the upstairs positions are used instead.
+	field *Field
+	cursor *utils.MatrixPosition
+	selectedTileIndex int
+	// Nil means that there is no notice.
+	notice *EditorNotice
+}
+
+// Returns a copy that does not share the field.
+func (editor *Editor) Clone() *Editor {
+	cloned := *editor
+	cloned.field = editor.field.Clone()
+	cloned.cursor = &utils.MatrixPosition{Y: editor.cursor.Y, X: editor.cursor.X}
+	return &cloned
+}
+
+func (editor *Editor) GetFilePath() string {
+	return editor.filePath
+}
+
+func (editor *Editor) GetName() string {
+	return editor.name
+}
+
+func (editor *Editor) GetTimeLimit() time.Duration {
+	return editor.timeLimit
+}
+
+func (editor *Editor) GetField() *Field {
+	return editor.field
+}
+
+func (editor *Editor) GetCursor() *utils.MatrixPosition {
+	return editor.cursor
+}
+
+// Move the cursor by the deltas, it stops at the edges of the field.
+func (editor *Editor) MoveCursor(deltaY int, deltaX int) {
+	position := &utils.MatrixPosition{Y: editor.cursor.Y + deltaY, X: editor.cursor.X + deltaX}
+	if position.Validate(editor.field.MeasureRowLength(), editor.field.MeasureColumnLength()) {
+		editor.cursor = position
+	}
+}
+
+func (editor *Editor) GetSelectedTileIndex() int {
+	return editor.selectedTileIndex
+}
+
+func (editor *Editor) GetSelectedTile() EditorTile {
+	return EditorTiles[editor.selectedTileIndex]
+}
+
+// Select the tile of the palette by delta, it loops at both ends.
+func (editor *Editor) CycleTile(delta int) {
+	tileCount := len(EditorTiles)
+	editor.selectedTileIndex = ((editor.selectedTileIndex + delta) % tileCount + tileCount) % tileCount
+}
+
+func (editor *Editor) SelectTile(index int) error {
+	if index < 0 || index >= len(EditorTiles) {
+		return errors.Errorf("The tile (%d) does not exist in the palette.", index)
+	}
+	editor.selectedTileIndex = index
+	return nil
+}
+
+// Place the selected tile at the cursor.
+// The start and the upstairs are moved there, and the wall under them is removed.
+// Returns an error if the tile would cover the start or the upstairs.
+func (editor *Editor) PlaceTile() error {
+	field := editor.field
+	cursor := editor.cursor
+	element, err := field.At(cursor)
+	if err != nil {
+		return err
+	}
+	isStart := field.GetStartPosition().Y == cursor.Y && field.GetStartPosition().X == cursor.X
+	isUpstairs := field.GetUpstairsPosition().Y == cursor.Y && field.GetUpstairsPosition().X == cursor.X
+	switch editor.GetSelectedTile() {
+	case EditorTileWall:
+		if isStart || isUpstairs {
+			return errors.Errorf("A wall can not be placed on the start or the upstairs.")
+		}
+		element.UpdateObjectClass("wall")
+	case EditorTileFloor:
+		element.UpdateObjectClass("empty")
+	case EditorTileStart:
+		if isUpstairs {
+			return errors.Errorf("The start can not be placed on the upstairs.")
+		}
+		element.UpdateObjectClass("empty")
+		field.SetStartPosition(cursor)
+	case EditorTileUpstairs:
+		if isStart {
+			return errors.Errorf("The upstairs can not be placed on the start.")
+		}
+		element.UpdateObjectClass("empty")
+		field.SetUpstairsPosition(cursor)
+	}
+	return nil
+}
+
+func (editor *Editor) GetNotice() *EditorNotice {
+	return editor.notice
+}
+
+func (editor *Editor) SetNotice(notice *EditorNotice) {
+	editor.notice = notice
+}
+
+// The field is edited directly, clone it if it must be kept.
+func CreateEditor(filePath string, name string, timeLimit time.Duration, field *Field) *Editor {
+	return &Editor{
+		filePath: filePath,
+		name: name,
+		timeLimit: timeLimit,
+		field: field,
+		cursor: &utils.MatrixPosition{Y: field.GetStartPosition().Y, X: field.GetStartPosition().X},
+	}
+}
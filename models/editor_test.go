@@ -0,0 +1,60 @@
+package models
+
+import (
+	"github.com/kjirou/tower-of-go/utils"
+	"testing"
+)
+
+func TestEditor_PlaceTile_NotTD(t *testing.T) {
+	createEditor := func() *Editor {
+		return CreateEditor("test.txt", "test", 0, createField(5, 7))
+	}
+
+	t.Run("カーソルの位置へ壁を置ける", func(t *testing.T) {
+		editor := createEditor()
+		editor.MoveCursor(1, 1)
+		editor.SelectTile(0)
+		if err := editor.PlaceTile(); err != nil {
+			t.Fatal(err)
+		}
+		element, _ := editor.GetField().At(&utils.MatrixPosition{Y: 2, X: 2})
+		if element.GetObjectClass() != "wall" {
+			t.Fatal("壁ではない")
+		}
+	})
+
+	t.Run("開始位置へ壁を置けない", func(t *testing.T) {
+		editor := createEditor()
+		editor.SelectTile(0)
+		if err := editor.PlaceTile(); err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+
+	t.Run("開始位置を移すと、その位置の壁を取り除く", func(t *testing.T) {
+		editor := createEditor()
+		editor.MoveCursor(-1, 0)
+		editor.SelectTile(0)
+		editor.PlaceTile()
+		editor.SelectTile(2)
+		if err := editor.PlaceTile(); err != nil {
+			t.Fatal(err)
+		}
+		element, _ := editor.GetField().At(&utils.MatrixPosition{Y: 0, X: 1})
+		if element.GetObjectClass() == "wall" {
+			t.Fatal("壁が残っている")
+		} else if editor.GetField().GetStartPosition().Y != 0 {
+			t.Fatal("開始位置が移っていない")
+		}
+	})
+}
+
+func TestEditor_MoveCursor_NotTD(t *testing.T) {
+	t.Run("フィールドの端で止まる", func(t *testing.T) {
+		editor := CreateEditor("test.txt", "test", 0, createField(5, 7))
+		editor.MoveCursor(-5, 0)
+		if editor.GetCursor().Y != 1 {
+			t.Fatal("フィールドの外へ出ている")
+		}
+	})
+}
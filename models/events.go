@@ -0,0 +1,104 @@
+package models
+
+import (
+	"github.com/kjirou/tower-of-go/utils"
+	"time"
+)
+
+// An occurrence in a game. The reducers emit them to the state, and the controller publishes them.
+// The subscribers can react to the occurrences without comparing the states before and after.
+type Event interface {
+	// Returns the execution time when it occurred.
+	GetTime() time.Duration
+}
+
+type GameStartedEvent struct {
+	Time time.Duration
+	ModeName string
+	DifficultyName string
+	Seed int64
+}
+
+func (event *GameStartedEvent) GetTime() time.Duration {
+	return event.Time
+}
+
+// It is emitted for every step, but not for a bump against a wall.
+type HeroMovedEvent struct {
+	Time time.Duration
+	FloorNumber int
+	From *utils.MatrixPosition
+	To *utils.MatrixPosition
+}
+
+func (event *HeroMovedEvent) GetTime() time.Duration {
+	return event.Time
+}
+
+// It is emitted when the last move is undone, such as in the zen mode.
+// The floor and the position are the ones after going back.
+type HeroRewoundEvent struct {
+	Time time.Duration
+	FloorNumber int
+	Position *utils.MatrixPosition
+}
+
+func (event *HeroRewoundEvent) GetTime() time.Duration {
+	return event.Time
+}
+
+type FloorClearedEvent struct {
+	Time time.Duration
+	Record *FloorRecord
+}
+
+func (event *FloorClearedEvent) GetTime() time.Duration {
+	return event.Time
+}
+
+type GameFinishedEvent struct {
+	Time time.Duration
+	// The finished game, it is not changed after the event.
+	Game *Game
+	// The game is a playtest in the editor.
+	IsPlaytest bool
+}
+
+func (event *GameFinishedEvent) GetTime() time.Duration {
+	return event.Time
+}
+
+// The items are not placed on the floors yet, so no reducer emits it.
+// It is a part of the events, so that the subscribers such as the sound cues can handle it from the start.
+type ItemPickedEvent struct {
+	Time time.Duration
+	ItemClass string
+	Position *utils.MatrixPosition
+}
+
+func (event *ItemPickedEvent) GetTime() time.Duration {
+	return event.Time
+}
+
+type EventSubscriber func(event Event)
+
+// Delivers the events to the subscribers in order of the subscription.
+type EventBus struct {
+	subscribers []EventSubscriber
+}
+
+func (eventBus *EventBus) Subscribe(subscriber EventSubscriber) {
+	eventBus.subscribers = append(eventBus.subscribers, subscriber)
+}
+
+func (eventBus *EventBus) Publish(events []Event) {
+	for _, event := range events {
+		for _, subscriber := range eventBus.subscribers {
+			subscriber(event)
+		}
+	}
+}
+
+func CreateEventBus() *EventBus {
+	return &EventBus{subscribers: make([]EventSubscriber, 0)}
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestEventBus_NotTD(t *testing.T) {
+	t.Run("全ての購読者へ、発生した順に届ける", func(t *testing.T) {
+		eventBus := CreateEventBus()
+		received := make([]string, 0)
+		eventBus.Subscribe(func(event Event) {
+			switch event.(type) {
+			case *GameStartedEvent:
+				received = append(received, "first:started")
+			case *HeroMovedEvent:
+				received = append(received, "first:moved")
+			}
+		})
+		eventBus.Subscribe(func(event Event) {
+			received = append(received, "second")
+		})
+		eventBus.Publish([]Event{&GameStartedEvent{}, &HeroMovedEvent{}})
+		if len(received) != 4 || received[0] != "first:started" || received[1] != "second" ||
+			received[2] != "first:moved" || received[3] != "second" {
+			t.Fatalf("%v is wrong", received)
+		}
+	})
+}
+
+func TestState_DrainEvents_NotTD(t *testing.T) {
+	t.Run("取り出した後は空になり、複製は取り出しの影響を受けない", func(t *testing.T) {
+		state := CreateState()
+		state.EmitEvent(&GameStartedEvent{})
+		cloned := state.Clone()
+		if len(state.DrainEvents()) != 1 || len(state.DrainEvents()) != 0 {
+			t.Fatal("取り出せていない")
+		} else if len(cloned.DrainEvents()) != 1 {
+			t.Fatal("複製に影響している")
+		}
+	})
+}
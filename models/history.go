@@ -0,0 +1,71 @@
+package models
+
+// A source of random numbers whose state is a single number, so that a snapshot of the game can copy it.
+// It is the SplitMix64 generator.
+type copyableSource struct {
+	state uint64
+}
+
+func (source *copyableSource) Seed(seed int64) {
+	source.state = uint64(seed)
+}
+
+func (source *copyableSource) Uint64() uint64 {
+	source.state += 0x9e3779b97f4a7c15
+	z := source.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (source *copyableSource) Int63() int64 {
+	return int64(source.Uint64() >> 1)
+}
+
+// A bounded stack of the snapshots of the state. The oldest one is dropped when it is full.
+type History struct {
+	snapshots []*State
+	capacity int
+}
+
+func (history *History) Len() int {
+	return len(history.snapshots)
+}
+
+// Keep a copy of the state, so that the following changes of the state do not affect it.
+// The copy does not have the history of its game, the history is given back when it is popped.
+func (history *History) Push(state *State) {
+	if history.capacity <= 0 {
+		return
+	}
+	if len(history.snapshots) >= history.capacity {
+		history.snapshots = history.snapshots[1:]
+	}
+	snapshot := state.Clone()
+	snapshot.game.history = nil
+	history.snapshots = append(history.snapshots, snapshot)
+}
+
+// Returns nil if there is no snapshot. The snapshot must not be changed, it may be kept by the copies of the history.
+func (history *History) Pop() *State {
+	if len(history.snapshots) == 0 {
+		return nil
+	}
+	snapshot := history.snapshots[len(history.snapshots)-1]
+	history.snapshots = history.snapshots[:len(history.snapshots)-1]
+	return snapshot
+}
+
+// The snapshots are not changed after pushed, so they are shared by the copy.
+func (history *History) Clone() *History {
+	cloned := *history
+	cloned.snapshots = append([]*State{}, history.snapshots...)
+	return &cloned
+}
+
+func CreateHistory(capacity int) *History {
+	return &History{
+		snapshots: make([]*State, 0),
+		capacity: capacity,
+	}
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"github.com/kjirou/tower-of-go/utils"
+	"testing"
+)
+
+func TestHistory_NotTD(t *testing.T) {
+	t.Run("容量を超えると古いものから捨てる", func(t *testing.T) {
+		history := CreateHistory(2)
+		for i := 1; i <= 3; i++ {
+			state := CreateState()
+			state.menuCursorIndex = i
+			history.Push(state)
+		}
+		if history.Len() != 2 {
+			t.Fatal("容量を超えている")
+		} else if history.Pop().menuCursorIndex != 3 || history.Pop().menuCursorIndex != 2 {
+			t.Fatal("新しいものから取り出していない")
+		} else if history.Pop() != nil {
+			t.Fatal("空なのにnilではない")
+		}
+	})
+
+	t.Run("積んだ後の変更は影響しない", func(t *testing.T) {
+		history := CreateHistory(1)
+		state := CreateState()
+		state.SetWelcomeData()
+		history.Push(state)
+		state.GetField().MoveObject(&utils.MatrixPosition{Y: 1, X: 1}, &utils.MatrixPosition{Y: 1, X: 2})
+		element, _ := history.Pop().GetField().GetElementOfHero()
+		if element.GetPosition().GetX() != 1 {
+			t.Fatal("変更が影響している")
+		}
+	})
+}
+
+func TestState_Clone_NotTD(t *testing.T) {
+	t.Run("フィールド、ゲーム、シーンを共有しない", func(t *testing.T) {
+		state := CreateState()
+		state.SetWelcomeData()
+		cloned := state.Clone()
+		cloned.GetField().MoveObject(&utils.MatrixPosition{Y: 1, X: 1}, &utils.MatrixPosition{Y: 1, X: 2})
+		cloned.GetGame().IncrementFloorNumber()
+		cloned.PushScene(SceneGame)
+		element, _ := state.GetField().GetElementOfHero()
+		if element.GetPosition().GetX() != 1 {
+			t.Fatal("フィールドを共有している")
+		} else if state.GetGame().GetFloorNumber() != 1 {
+			t.Fatal("ゲームを共有している")
+		} else if state.GetCurrentScene() != SceneTitle {
+			t.Fatal("シーンを共有している")
+		}
+	})
+}
+
+func TestGame_Clone_NotTD(t *testing.T) {
+	t.Run("複製は元と同じ乱数を続けて出す", func(t *testing.T) {
+		game := &Game{}
+		game.Reset()
+		game.random.Int63()
+		cloned := game.Clone()
+		if game.random.Int63() != cloned.random.Int63() || game.random.Int63() != cloned.random.Int63() {
+			t.Fatal("乱数が違う")
+		}
+	})
+
+	t.Run("巻き戻せるモードでは履歴を持つ", func(t *testing.T) {
+		game := &Game{}
+		game.SetMode(&ZenMode{})
+		game.Reset()
+		game.SaveSnapshot(CreateState())
+		if !game.CanRewind() || game.PopSnapshot() == nil {
+			t.Fatal("履歴を持っていない")
+		}
+
+		otherGame := &Game{}
+		otherGame.Reset()
+		otherGame.SaveSnapshot(CreateState())
+		if otherGame.CanRewind() {
+			t.Fatal("巻き戻せる")
+		}
+	})
+
+	t.Run("複製の履歴を取り出しても元の履歴は変わらない", func(t *testing.T) {
+		game := &Game{}
+		game.SetMode(&ZenMode{})
+		game.Reset()
+		state := CreateState()
+		state.SetWelcomeData()
+		game.SaveSnapshot(state)
+		snapshot := game.Clone().PopSnapshot()
+		snapshot.GetField().MoveObject(&utils.MatrixPosition{Y: 1, X: 1}, &utils.MatrixPosition{Y: 1, X: 2})
+		if !game.CanRewind() {
+			t.Fatal("元の履歴が減っている")
+		}
+		element, _ := game.PopSnapshot().GetField().GetElementOfHero()
+		if element.GetPosition().GetX() != 1 {
+			t.Fatal("取り出したものの変更が影響している")
+		}
+	})
+}
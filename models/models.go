@@ -3,15 +3,19 @@ package models
 import (
 	"github.com/kjirou/tower-of-go/utils"
 	"github.com/pkg/errors"
+	"math"
+	"math/rand"
 	"time"
 )
 
+// The default position where the hero starts a floor.
 var HeroPosition = &utils.MatrixPosition{Y: 1, X: 1}
-var UpstairsPosition = &utils.MatrixPosition{Y: 11, X: 19}
 
 type FieldElement struct {
 	floorObjectClass string
 	objectClass string
+	// A mark of the player over the cell, such as a breadcrumb. It does not block the objects.
+	overlayClass string
 	position *utils.MatrixPosition
 }
 
@@ -27,6 +31,10 @@ func (fieldElement *FieldElement) GetFloorObjectClass() string {
 	return fieldElement.floorObjectClass
 }
 
+func (fieldElement *FieldElement) GetOverlayClass() string {
+	return fieldElement.overlayClass
+}
+
 func (fieldElement *FieldElement) IsObjectEmpty() bool {
 	return fieldElement.objectClass == "empty"
 }
@@ -39,8 +47,16 @@ func (fieldElement *FieldElement) UpdateFloorObjectClass(class string) {
 	fieldElement.floorObjectClass = class
 }
 
+func (fieldElement *FieldElement) UpdateOverlayClass(class string) {
+	fieldElement.overlayClass = class
+}
+
 type Field struct {
 	matrix [][]*FieldElement
+	// The entrance where the hero starts the floor.
+	startPosition *utils.MatrixPosition
+	// The exit where the upstairs is placed.
+	upstairsPosition *utils.MatrixPosition
 }
 
 func (field *Field) MeasureRowLength() int {
@@ -103,10 +119,54 @@ func (field *Field) MoveObject(from *utils.MatrixPosition, to *utils.MatrixPosit
 	return nil
 }
 
-func (field *Field) ResetMaze() error {
+func (field *Field) GetStartPosition() *utils.MatrixPosition {
+	return field.startPosition
+}
+
+func (field *Field) SetStartPosition(position *utils.MatrixPosition) {
+	field.startPosition = position
+}
+
+func (field *Field) GetUpstairsPosition() *utils.MatrixPosition {
+	return field.upstairsPosition
+}
+
+func (field *Field) SetUpstairsPosition(position *utils.MatrixPosition) {
+	field.upstairsPosition = position
+}
+
+// Returns a copy that does not share the elements.
+func (field *Field) Clone() *Field {
+	cloned := createField(field.MeasureRowLength(), field.MeasureColumnLength())
+	for y, row := range field.matrix {
+		for x, element := range row {
+			cloned.matrix[y][x].objectClass = element.objectClass
+			cloned.matrix[y][x].floorObjectClass = element.floorObjectClass
+			cloned.matrix[y][x].overlayClass = element.overlayClass
+		}
+	}
+	cloned.startPosition = &utils.MatrixPosition{Y: field.startPosition.Y, X: field.startPosition.X}
+	cloned.upstairsPosition = &utils.MatrixPosition{Y: field.upstairsPosition.Y, X: field.upstairsPosition.X}
+	return cloned
+}
+
+// Copy the overlays of another field of the same size, the objects are not changed.
+func (field *Field) CopyOverlays(source *Field) {
+	for y, row := range field.matrix {
+		for x, element := range row {
+			element.overlayClass = source.matrix[y][x].overlayClass
+		}
+	}
+}
+
+// A nil options generates a perfect maze with the clustering method.
+func (field *Field) ResetMaze(options *utils.MazeOptions) error {
+	if options == nil {
+		options = &utils.MazeOptions{}
+	}
 	rowLength := field.MeasureRowLength()
 	columnLength := field.MeasureColumnLength()
-	mazeCells, err := utils.GenerateMaze(rowLength, columnLength)
+	mazeCells, err := utils.GenerateMazeWithOptions(rowLength, columnLength, options)
 	if err != nil {
 		return err
 	}
@@ -127,6 +187,26 @@ func (field *Field) ResetMaze() error {
 	return nil
 }
 
+// Returns the number of steps of the shortest path that avoids walls, or -1 if it is unreachable.
+func (field *Field) MeasureShortestPathLength(from *utils.MatrixPosition, to *utils.MatrixPosition) int {
+	isPassable := func(position *utils.MatrixPosition) bool {
+		element, err := field.At(position)
+		return err == nil && element.GetObjectClass() != "wall"
+	}
+	return utils.FindShortestPathLength(field.MeasureRowLength(), field.MeasureColumnLength(), isPassable, from, to)
+}
+
+// Remove all objects, floor objects and overlays.
+func (field *Field) Clear() {
+	for _, row := range field.matrix {
+		for _, element := range row {
+			element.UpdateObjectClass("empty")
+			element.UpdateFloorObjectClass("empty")
+			element.UpdateOverlayClass("empty")
+		}
+	}
+}
+
 func createField(y int, x int) *Field {
 	matrix := make([][]*FieldElement, y)
 	for rowIndex := 0; rowIndex < y; rowIndex++ {
@@ -139,27 +219,181 @@ func createField(y int, x int) *Field {
 				},
 				objectClass: "empty",
 				floorObjectClass: "empty",
+				overlayClass: "empty",
 			}
 		}
 		matrix[rowIndex] = row
 	}
 	return &Field{
 		matrix: matrix,
+		startPosition: HeroPosition,
+		// The lower right corner, the farthest from the hero.
+		upstairsPosition: &utils.MatrixPosition{Y: y - 2, X: x - 2},
 	}
 }
 
+// All elements are empty. The start is the upper left and the upstairs is the lower right.
+func CreateField(rowLength int, columnLength int) *Field {
+	return createField(rowLength, columnLength)
+}
+
 type Game struct {
+	mode GameMode
+	difficulty *Difficulty
+	// It is nil if the mode does not rank.
+	rankTable *RankTable
 	floorNumber int
 	isFinished bool
 	// A snapshot of `state.executionTime` when a game has started.
 	startedAt time.Duration
-}
-
+	// A snapshot of `state.executionTime` when a game has finished.
+	finishedAt time.Duration
+	// The records of the cleared floors, in order of the floor number.
+	floorRecords []*FloorRecord
+	// A snapshot of `state.executionTime` when the current floor has started.
+	floorStartedAt time.Duration
+	// The number of steps of the hero in the current floor.
+	moveCount int
+	// The number of steps of the shortest path to the upstairs in the current floor.
+	optimalPathLength int
+	// The seed of all games. Zero means that a new seed is drawn for every game.
+	fixedSeed int64
+	// The seed of the current game, the floors are generated from it in order.
+	seed int64
+	randomSource *copyableSource
+	random *rand.Rand
+	// The positions of the hero in the current game.
+	replaySteps []*ReplayStep
+	// The fields of the floors as they started, in order of the floor number. They are not changed after kept.
+	floorFields []*Field
+	// The best run that is raced, it is nil if there is none.
+	ghost *Replay
+	// The snapshots before the moves, it is nil if the mode can not rewind.
+	history *History
+}
+
+// The mode, the difficulty, the rank table, the fixed seed and the ghost are kept.
 func (game *Game) Reset() {
 	zeroDuration, _ := time.ParseDuration("0s")
 	game.startedAt = zeroDuration
+	game.finishedAt = zeroDuration
 	game.floorNumber = 1
 	game.isFinished = false
+	game.floorRecords = make([]*FloorRecord, 0)
+	game.floorStartedAt = zeroDuration
+	game.moveCount = 0
+	game.optimalPathLength = 0
+	game.seed = game.fixedSeed
+	if game.seed == 0 {
+		// A drawn seed is kept small, so that the challenge code of the game is short.
+		game.seed = rand.Int63n(math.MaxInt32) + 1
+	}
+	game.randomSource = &copyableSource{}
+	game.randomSource.Seed(game.seed)
+	game.random = rand.New(game.randomSource)
+	game.replaySteps = make([]*ReplayStep, 0)
+	game.floorFields = make([]*Field, 0)
+	game.history = nil
+	if rewindableMode, ok := game.GetMode().(RewindableMode); ok {
+		game.history = CreateHistory(rewindableMode.GetRewindLimit())
+	}
+}
+
+// Returns a copy that does not share the changing records, such as the floors, the random numbers and the history.
+// The rules and the ghost are shared, because they do not change in the game.
+func (game *Game) Clone() *Game {
+	cloned := *game
+	cloned.floorRecords = append([]*FloorRecord{}, game.floorRecords...)
+	cloned.replaySteps = append([]*ReplayStep{}, game.replaySteps...)
+	cloned.floorFields = append([]*Field{}, game.floorFields...)
+	if game.history != nil {
+		cloned.history = game.history.Clone()
+	}
+	if game.randomSource != nil {
+		randomSource := *game.randomSource
+		cloned.randomSource = &randomSource
+		cloned.random = rand.New(cloned.randomSource)
+	}
+	return &cloned
+}
+
+func (game *Game) CanRewind() bool {
+	return game.history != nil && game.history.Len() > 0
+}
+
+// Keep the state before a move, it does nothing if the mode can not rewind.
+func (game *Game) SaveSnapshot(state *State) {
+	if game.history != nil {
+		game.history.Push(state)
+	}
+}
+
+// Returns a copy of the state before the last move, or nil if there is none.
+// The game of the copy has the rest of the history, so that it can rewind further.
+func (game *Game) PopSnapshot() *State {
+	if game.history == nil {
+		return nil
+	}
+	snapshot := game.history.Pop()
+	if snapshot == nil {
+		return nil
+	}
+	snapshot = snapshot.Clone()
+	snapshot.game.history = game.history.Clone()
+	return snapshot
+}
+
+// Returns the time attack mode if no mode is set.
+func (game *Game) GetMode() GameMode {
+	if game.mode == nil {
+		mode, _ := CreateGameMode("timeAttack")
+		return mode
+	}
+	return game.mode
+}
+
+func (game *Game) SetMode(mode GameMode) {
+	game.mode = mode
+}
+
+// Returns the normal difficulty if no difficulty is set.
+func (game *Game) GetDifficulty() *Difficulty {
+	if game.difficulty == nil {
+		difficulty, _ := CreateDifficulty(DefaultDifficultyName, nil)
+		return difficulty
+	}
+	return game.difficulty
+}
+
+func (game *Game) SetDifficulty(difficulty *Difficulty) {
+	game.difficulty = difficulty
+}
+
+func (game *Game) GetFixedSeed() int64 {
+	return game.fixedSeed
+}
+
+// It is applied from the next reset.
+func (game *Game) SetFixedSeed(seed int64) {
+	game.fixedSeed = seed
+}
+
+func (game *Game) GetSeed() int64 {
+	return game.seed
+}
+
+func (game *Game) GetRankTable() *RankTable {
+	return game.rankTable
+}
+
+func (game *Game) SetRankTable(rankTable *RankTable) {
+	game.rankTable = rankTable
+}
+
+// Returns the time limit of the mode until the floor, scaled by the difficulty.
+func (game *Game) CalculateTimeLimit(floorNumber int) time.Duration {
+	timeLimit := game.GetMode().CalculateTimeLimit(floorNumber)
+	return time.Duration(float64(timeLimit) * game.GetDifficulty().GetTimeLimitRate())
 }
 
 func (game *Game) IsStarted() bool {
@@ -171,18 +405,30 @@ func (game *Game) IsFinished() bool {
 	return game.isFinished
 }
 
+func (game *Game) HasTimeLimit() bool {
+	return game.CalculateTimeLimit(game.floorNumber) > 0
+}
+
+// Returns the elapsed time since the start. It stops when the game finishes.
+func (game *Game) CalculatePlaytime(executionTime time.Duration) time.Duration {
+	zeroDuration, _ := time.ParseDuration("0s")
+	if !game.IsStarted() {
+		return zeroDuration
+	} else if game.IsFinished() {
+		return game.finishedAt - game.startedAt
+	}
+	return executionTime - game.startedAt
+}
+
+// Returns 0 if the mode has no time limit.
 func (game *Game) CalculateRemainingTime(executionTime time.Duration) time.Duration {
-	oneGameTime, _ := time.ParseDuration("30s")
-	if game.IsStarted() {
-		playtime := executionTime - game.startedAt
-		remainingTime := oneGameTime - playtime
-		if remainingTime < 0 {
-			zeroTime, _ := time.ParseDuration("0s")
-			return zeroTime
-		}
-		return remainingTime
+	timeLimit := game.CalculateTimeLimit(game.floorNumber)
+	remainingTime := timeLimit - game.CalculatePlaytime(executionTime)
+	if remainingTime < 0 {
+		zeroTime, _ := time.ParseDuration("0s")
+		return zeroTime
 	}
-	return oneGameTime
+	return remainingTime
 }
 
 func (game *Game) GetFloorNumber() int{
@@ -197,16 +443,93 @@ func (game *Game) Start(executionTime time.Duration) {
 	game.startedAt = executionTime
 }
 
-func (game *Game) Finish() {
+// Move the times of the game by the delta, as if it had started at another time.
+func (game *Game) ShiftTime(delta time.Duration) {
+	game.startedAt += delta
+	game.floorStartedAt += delta
+}
+
+// The run becomes the ghost if it is better than the ghost.
+func (game *Game) Finish(executionTime time.Duration) {
 	game.isFinished = true
+	game.finishedAt = executionTime
+	if replay := game.CreateReplay(executionTime); replay != nil && replay.IsBetterThan(game.ghost) {
+		game.ghost = replay
+	}
 }
 
+func (game *Game) CalculateScore(executionTime time.Duration) float64 {
+	return game.GetMode().CalculateScore(game, executionTime)
+}
+
+// The rules of the games that are decided before entering the game scene.
+type GameSetup struct {
+	Mode GameMode
+	Difficulty *Difficulty
+	// It is nil if the mode does not rank.
+	RankTable *RankTable
+	// Zero means that a new seed is drawn for every game.
+	Seed int64
+	// The best run of the same replay key, it is nil if there is none.
+	Ghost *Replay
+}
+
+type Scene int
+const (
+	SceneTitle Scene = iota
+	SceneModeSelect
+	SceneGame
+	SceneResults
+	SceneSettings
+	SceneEditor
+	SceneAchievements
+	SceneStats
+	SceneHeatmap
+)
+
 type State struct {
 	// This is the total of main loop intervals.
 	// It is different from the real time.
 	executionTime time.Duration
 	field *Field
 	game *Game
+	// The last element is the current scene. The first element is the root scene, it is never removed.
+	sceneStack []Scene
+	// The selected item of the menu in the current scene.
+	menuCursorIndex int
+	// Nil until the editor is opened.
+	editor *Editor
+	// The time and the game are stopped, such as a resumed game until the player is ready.
+	isPaused bool
+	// The events that have occurred since the last drain, in order of the occurrence.
+	events []Event
+}
+
+// Returns a copy that does not share the field, the game, the scenes and the editor.
+// The history keeps the copy, so that the previous state remains as a snapshot.
+func (state *State) Clone() *State {
+	cloned := *state
+	cloned.field = state.field.Clone()
+	cloned.game = state.game.Clone()
+	cloned.sceneStack = append([]Scene{}, state.sceneStack...)
+	cloned.events = append([]Event{}, state.events...)
+	if state.editor != nil {
+		cloned.editor = state.editor.Clone()
+	}
+	return &cloned
+}
+
+// Copy the field and the game that may be shared with other states, before they are changed.
+func (state *State) CopyFieldAndGame() {
+	state.field = state.field.Clone()
+	state.game = state.game.Clone()
+}
+
+// Copy the editor that may be shared with other states, before it is changed.
+func (state *State) CopyEditor() {
+	if state.editor != nil {
+		state.editor = state.editor.Clone()
+	}
 }
 
 func (state *State) GetExecutionTime() time.Duration {
@@ -221,22 +544,132 @@ func (state *State) GetGame() *Game {
 	return state.game
 }
 
+func (state *State) GetEditor() *Editor {
+	return state.editor
+}
+
+func (state *State) SetEditor(editor *Editor) {
+	state.editor = editor
+}
+
+func (state *State) IsPaused() bool {
+	return state.isPaused
+}
+
+func (state *State) SetPaused(isPaused bool) {
+	state.isPaused = isPaused
+}
+
+// The events may be shared with other states, so they are appended to a new array.
+func (state *State) EmitEvent(event Event) {
+	state.events = append(state.events[:len(state.events):len(state.events)], event)
+}
+
+// Returns the events that have occurred, and forget them.
+func (state *State) DrainEvents() []Event {
+	events := state.events
+	state.events = nil
+	return events
+}
+
+func (state *State) GetCurrentScene() Scene {
+	return state.sceneStack[len(state.sceneStack)-1]
+}
+
+func (state *State) ContainsScene(scene Scene) bool {
+	for _, stackedScene := range state.sceneStack {
+		if stackedScene == scene {
+			return true
+		}
+	}
+	return false
+}
+
+func (state *State) IsAtRootScene() bool {
+	return len(state.sceneStack) == 1
+}
+
+// The scenes may be shared with other states, so they are changed in a new array in the same way as the events.
+func (state *State) PushScene(scene Scene) {
+	state.sceneStack = append(state.sceneStack[:len(state.sceneStack):len(state.sceneStack)], scene)
+	state.menuCursorIndex = 0
+}
+
+// Returns an error if the current scene is the root scene.
+func (state *State) PopScene() error {
+	if state.IsAtRootScene() {
+		return errors.Errorf("The root scene can not be removed.")
+	}
+	state.sceneStack = state.sceneStack[:len(state.sceneStack)-1]
+	state.menuCursorIndex = 0
+	return nil
+}
+
+func (state *State) ReplaceScene(scene Scene) {
+	state.sceneStack = append(append([]Scene{}, state.sceneStack[:len(state.sceneStack)-1]...), scene)
+	state.menuCursorIndex = 0
+}
+
+func (state *State) GetMenuCursorIndex() int {
+	return state.menuCursorIndex
+}
+
+// Move the cursor of the menu by delta, it loops at both ends.
+func (state *State) MoveMenuCursor(delta int, itemCount int) {
+	if itemCount <= 0 {
+		state.menuCursorIndex = 0
+		return
+	}
+	state.menuCursorIndex = ((state.menuCursorIndex + delta) % itemCount + itemCount) % itemCount
+}
+
 func (state *State) AlterExecutionTime(delta time.Duration) {
 	state.executionTime = state.executionTime + delta
 }
 
+// Replace the field and the game with the ones of a snapshot.
+// The execution time does not go back, the game is shifted instead, so that its playtime is the one of the snapshot.
+func (state *State) RestoreSnapshot(snapshot *State) {
+	state.field = snapshot.field
+	state.game = snapshot.game
+	state.game.ShiftTime(state.executionTime - snapshot.executionTime)
+}
+
+// Recreate the field if the size is different.
+func (state *State) ResizeField(rowLength int, columnLength int) {
+	if state.field.MeasureRowLength() != rowLength || state.field.MeasureColumnLength() != columnLength {
+		state.field = createField(rowLength, columnLength)
+	}
+}
+
+// Replace the field with the floor, and place the hero at the entrance and the upstairs at the exit.
+func (state *State) PlaceFloor(field *Field) error {
+	heroFieldElement, err := field.At(field.GetStartPosition())
+	if err != nil {
+		return err
+	}
+	heroFieldElement.UpdateObjectClass("hero")
+	upstairsFieldElement, err := field.At(field.GetUpstairsPosition())
+	if err != nil {
+		return err
+	}
+	upstairsFieldElement.UpdateFloorObjectClass("upstairs")
+	state.field = field
+	return nil
+}
+
 func (state *State) SetWelcomeData() error {
 	field := state.GetField()
 
 	// Place a hero to be the player's alter ego.
-	heroFieldElement, err := field.At(HeroPosition)
+	heroFieldElement, err := field.At(field.GetStartPosition())
 	if err != nil {
 		return err
 	}
 	heroFieldElement.UpdateObjectClass("hero")
 
 	// Place an upstairs.
-	upstairsFieldElement, err := field.At(UpstairsPosition)
+	upstairsFieldElement, err := field.At(field.GetUpstairsPosition())
 	if err != nil {
 		return err
 	}
@@ -265,6 +698,7 @@ func CreateState() *State {
 		executionTime: executionTime,
 		field: createField(13, 21),
 		game: &Game{},
+		sceneStack: []Scene{SceneTitle},
 	}
 	state.game.Reset()
 	return state
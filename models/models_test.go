@@ -109,7 +109,7 @@ func TestField_MoveObject_NotTD(t *testing.T) {
 func TestField_ResetMaze_NotTD(t *testing.T) {
 	t.Run("外周1マスは壁になる", func(t *testing.T) {
 		field := createField(7, 7)
-		field.ResetMaze()
+		field.ResetMaze(nil)
 		for y, row := range field.matrix {
 			for x, element := range row {
 				isTopOrBottomEdge := y == 0 || y == field.MeasureRowLength()-1
@@ -128,7 +128,7 @@ func TestField_ResetMaze_NotTD(t *testing.T) {
 			t.Fatal("ヒーローの配置に失敗する")
 		}
 		element.UpdateObjectClass("hero")
-		field.ResetMaze()
+		field.ResetMaze(nil)
 		for _, row := range field.matrix {
 			for _, element := range row {
 				if element.GetObjectClass() == "hero" {
@@ -177,3 +177,69 @@ func TestGame_Start_NotTD(t *testing.T) {
 		}
 	})
 }
+
+func TestState_PopScene_NotTD(t *testing.T) {
+	t.Run("ルートのシーンは取り除けない", func(t *testing.T) {
+		state := CreateState()
+		err := state.PopScene()
+		if err == nil {
+			t.Fatal("エラーを返さない")
+		} else if state.GetCurrentScene() != SceneTitle {
+			t.Fatal("ルートのシーンが取り除かれている")
+		}
+	})
+
+	t.Run("直前のシーンへ戻る", func(t *testing.T) {
+		state := CreateState()
+		state.PushScene(SceneModeSelect)
+		state.PushScene(SceneGame)
+		state.PopScene()
+		if state.GetCurrentScene() != SceneModeSelect {
+			t.Fatal("直前のシーンではない")
+		}
+	})
+}
+
+func TestState_PushScene_NotTD(t *testing.T) {
+	t.Run("場面を共有する状態の場面は変わらない", func(t *testing.T) {
+		state := CreateState()
+		state.PushScene(SceneModeSelect)
+		state.PopScene()
+		shared := *state
+		shared.PushScene(SceneGame)
+		state.PushScene(SceneSettings)
+		shared.ReplaceScene(SceneResults)
+		if shared.GetCurrentScene() != SceneResults || state.GetCurrentScene() != SceneSettings {
+			t.Fatal("場面を共有している")
+		}
+	})
+}
+
+func TestState_EmitEvent_NotTD(t *testing.T) {
+	t.Run("出来事を共有する状態の出来事は変わらない", func(t *testing.T) {
+		state := CreateState()
+		state.EmitEvent(&GameStartedEvent{})
+		state.EmitEvent(&GameStartedEvent{})
+		state.EmitEvent(&GameStartedEvent{})
+		shared := *state
+		shared.EmitEvent(&FloorClearedEvent{})
+		state.EmitEvent(&GameStartedEvent{})
+		if _, ok := shared.DrainEvents()[3].(*FloorClearedEvent); !ok {
+			t.Fatal("出来事を共有している")
+		}
+	})
+}
+
+func TestState_MoveMenuCursor_NotTD(t *testing.T) {
+	t.Run("両端で循環する", func(t *testing.T) {
+		state := CreateState()
+		state.MoveMenuCursor(-1, 3)
+		if state.GetMenuCursorIndex() != 2 {
+			t.Fatal("末尾へ移動していない")
+		}
+		state.MoveMenuCursor(1, 3)
+		if state.GetMenuCursorIndex() != 0 {
+			t.Fatal("先頭へ移動していない")
+		}
+	})
+}
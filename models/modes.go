@@ -0,0 +1,171 @@
+package models
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+type ScoreUnit int
+const (
+	// The number of reached floors, higher is better.
+	ScoreUnitFloors ScoreUnit = iota
+	// The seconds to reach the goal, lower is better.
+	ScoreUnitSeconds
+)
+
+// A rule set of a game.
+type GameMode interface {
+	GetName() string
+	// Returns the total time limit until the floor, it increases for modes that add time per floor.
+	// Zero means that there is no time limit.
+	CalculateTimeLimit(floorNumber int) time.Duration
+	// The floor number to reach for finishing the game. Zero means that there is no goal.
+	GetGoalFloorNumber() int
+	// It is judged every frame in a game.
+	IsFinished(game *Game, executionTime time.Duration) bool
+	CalculateScore(game *Game, executionTime time.Duration) float64
+	GetScoreUnit() ScoreUnit
+}
+
+// A game mode for practice, that allows to go back to the previous moves.
+type RewindableMode interface {
+	// The number of the moves that can be rewound.
+	GetRewindLimit() int
+}
+
+// Climb as many floors as possible within the time limit.
+type TimeAttackMode struct {
+	timeLimit time.Duration
+}
+
+func (mode *TimeAttackMode) GetName() string {
+	return "timeAttack"
+}
+
+func (mode *TimeAttackMode) CalculateTimeLimit(floorNumber int) time.Duration {
+	return mode.timeLimit
+}
+
+func (mode *TimeAttackMode) GetGoalFloorNumber() int {
+	return 0
+}
+
+func (mode *TimeAttackMode) IsFinished(game *Game, executionTime time.Duration) bool {
+	return game.CalculateRemainingTime(executionTime) == 0
+}
+
+func (mode *TimeAttackMode) CalculateScore(game *Game, executionTime time.Duration) float64 {
+	return float64(game.GetFloorNumber())
+}
+
+func (mode *TimeAttackMode) GetScoreUnit() ScoreUnit {
+	return ScoreUnitFloors
+}
+
+// Reach the goal floor as fast as possible.
+type SprintMode struct {
+	goalFloorNumber int
+}
+
+func (mode *SprintMode) GetName() string {
+	return "sprint"
+}
+
+func (mode *SprintMode) CalculateTimeLimit(floorNumber int) time.Duration {
+	return 0
+}
+
+func (mode *SprintMode) GetGoalFloorNumber() int {
+	return mode.goalFloorNumber
+}
+
+func (mode *SprintMode) IsFinished(game *Game, executionTime time.Duration) bool {
+	return game.GetFloorNumber() >= mode.goalFloorNumber
+}
+
+func (mode *SprintMode) CalculateScore(game *Game, executionTime time.Duration) float64 {
+	return game.CalculatePlaytime(executionTime).Seconds()
+}
+
+func (mode *SprintMode) GetScoreUnit() ScoreUnit {
+	return ScoreUnitSeconds
+}
+
+// Each floor adds time, the game continues until the time runs out.
+type SurvivalMode struct {
+	initialTimeLimit time.Duration
+	bonusTimePerFloor time.Duration
+}
+
+func (mode *SurvivalMode) GetName() string {
+	return "survival"
+}
+
+func (mode *SurvivalMode) CalculateTimeLimit(floorNumber int) time.Duration {
+	return mode.initialTimeLimit + mode.bonusTimePerFloor * time.Duration(floorNumber - 1)
+}
+
+func (mode *SurvivalMode) GetGoalFloorNumber() int {
+	return 0
+}
+
+func (mode *SurvivalMode) IsFinished(game *Game, executionTime time.Duration) bool {
+	return game.CalculateRemainingTime(executionTime) == 0
+}
+
+func (mode *SurvivalMode) CalculateScore(game *Game, executionTime time.Duration) float64 {
+	return float64(game.GetFloorNumber())
+}
+
+func (mode *SurvivalMode) GetScoreUnit() ScoreUnit {
+	return ScoreUnitFloors
+}
+
+// Climb freely without a timer. The game does not finish by itself.
+type ZenMode struct {
+}
+
+func (mode *ZenMode) GetName() string {
+	return "zen"
+}
+
+func (mode *ZenMode) CalculateTimeLimit(floorNumber int) time.Duration {
+	return 0
+}
+
+func (mode *ZenMode) GetGoalFloorNumber() int {
+	return 0
+}
+
+func (mode *ZenMode) IsFinished(game *Game, executionTime time.Duration) bool {
+	return false
+}
+
+func (mode *ZenMode) CalculateScore(game *Game, executionTime time.Duration) float64 {
+	return float64(game.GetFloorNumber())
+}
+
+func (mode *ZenMode) GetScoreUnit() ScoreUnit {
+	return ScoreUnitFloors
+}
+
+func (mode *ZenMode) GetRewindLimit() int {
+	return 100
+}
+
+// The names of the modes in order of the menu.
+var GameModeNames = []string{"timeAttack", "sprint", "survival", "zen"}
+
+func CreateGameMode(name string) (GameMode, error) {
+	switch name {
+	case "timeAttack":
+		return &TimeAttackMode{timeLimit: 30 * time.Second}, nil
+	case "sprint":
+		return &SprintMode{goalFloorNumber: 5}, nil
+	case "survival":
+		return &SurvivalMode{initialTimeLimit: 20 * time.Second, bonusTimePerFloor: 5 * time.Second}, nil
+	case "zen":
+		return &ZenMode{}, nil
+	}
+	return nil, errors.Errorf("The game mode \"%s\" does not exist.", name)
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func createGameOfMode(t *testing.T, modeName string) *Game {
+	mode, err := CreateGameMode(modeName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	game := &Game{}
+	game.SetMode(mode)
+	game.Reset()
+	return game
+}
+
+func TestCreateGameMode_NotTD(t *testing.T) {
+	t.Run("全てのモードを生成できる", func(t *testing.T) {
+		for _, modeName := range GameModeNames {
+			mode, err := CreateGameMode(modeName)
+			if err != nil {
+				t.Fatal(err)
+			} else if mode.GetName() != modeName {
+				t.Fatalf("%s の名前が違う", modeName)
+			}
+		}
+	})
+
+	t.Run("存在しないモードはエラーを返す", func(t *testing.T) {
+		_, err := CreateGameMode("unknown")
+		if err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+}
+
+func TestSprintMode_NotTD(t *testing.T) {
+	t.Run("目標の階へ到達すると終了する", func(t *testing.T) {
+		game := createGameOfMode(t, "sprint")
+		game.Start(time.Second)
+		for game.GetFloorNumber() < game.GetMode().GetGoalFloorNumber() {
+			if game.GetMode().IsFinished(game, 2*time.Second) {
+				t.Fatal("途中で終了している")
+			}
+			game.IncrementFloorNumber()
+		}
+		if !game.GetMode().IsFinished(game, 2*time.Second) {
+			t.Fatal("終了していない")
+		}
+	})
+
+	t.Run("到達までの秒数がスコアになる", func(t *testing.T) {
+		game := createGameOfMode(t, "sprint")
+		game.Start(time.Second)
+		game.Finish(26 * time.Second)
+		if game.CalculateScore(99 * time.Second) != 25 {
+			t.Fatal("スコアが違う")
+		}
+	})
+
+	t.Run("制限時間がない", func(t *testing.T) {
+		game := createGameOfMode(t, "sprint")
+		if game.HasTimeLimit() {
+			t.Fatal("制限時間がある")
+		}
+	})
+}
+
+func TestSurvivalMode_NotTD(t *testing.T) {
+	t.Run("階を上るごとに時間が増える", func(t *testing.T) {
+		game := createGameOfMode(t, "survival")
+		game.Start(time.Second)
+		before := game.CalculateRemainingTime(11 * time.Second)
+		game.IncrementFloorNumber()
+		after := game.CalculateRemainingTime(11 * time.Second)
+		if after - before != 5*time.Second {
+			t.Fatal("増えた時間が違う")
+		}
+	})
+
+	t.Run("時間切れで終了する", func(t *testing.T) {
+		game := createGameOfMode(t, "survival")
+		game.Start(time.Second)
+		if game.GetMode().IsFinished(game, 20*time.Second) {
+			t.Fatal("時間切れ前に終了している")
+		} else if !game.GetMode().IsFinished(game, 21*time.Second) {
+			t.Fatal("終了していない")
+		}
+	})
+}
+
+func TestZenMode_NotTD(t *testing.T) {
+	t.Run("終了しない", func(t *testing.T) {
+		game := createGameOfMode(t, "zen")
+		game.Start(time.Second)
+		if game.GetMode().IsFinished(game, time.Hour) {
+			t.Fatal("終了している")
+		}
+	})
+}
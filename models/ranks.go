@@ -0,0 +1,170 @@
+package models
+
+import (
+	"github.com/kjirou/tower-of-go/config"
+	"github.com/pkg/errors"
+	"math"
+)
+
+type Rank struct {
+	// A key of the message catalog. If it is empty, Title is shown as it is.
+	MessageKey string
+	Title string
+	// A color name of the theme such as "rank.good", or a color spec such as "208+bold".
+	Color string
+}
+
+type RankTier struct {
+	// The minimum score, or the maximum seconds in modes that are scored by time.
+	Threshold float64
+	Rank *Rank
+}
+
+type RankTable struct {
+	scoreUnit ScoreUnit
+	// In order from the best.
+	tiers []*RankTier
+	// The rank of scores that do not reach any threshold.
+	lowest *Rank
+}
+
+func (rankTable *RankTable) GetTiers() []*RankTier {
+	return rankTable.tiers
+}
+
+// Returns nil if the table is nil, it means that the mode does not rank.
+func (rankTable *RankTable) DecideRank(score float64) *Rank {
+	if rankTable == nil {
+		return nil
+	}
+	for _, tier := range rankTable.tiers {
+		if rankTable.scoreUnit == ScoreUnitSeconds && score <= tier.Threshold ||
+			rankTable.scoreUnit == ScoreUnitFloors && score >= tier.Threshold {
+			return tier.Rank
+		}
+	}
+	return rankTable.lowest
+}
+
+// In order from the best.
+var builtinRanks = []*Rank{
+	&Rank{MessageKey: "rank.gopher", Color: "rank.best"},
+	&Rank{MessageKey: "rank.marvelous", Color: "rank.good"},
+	&Rank{MessageKey: "rank.excellent", Color: "rank.good"},
+	&Rank{MessageKey: "rank.good", Color: "rank.good"},
+}
+
+var builtinLowestRank = &Rank{MessageKey: "rank.noGood", Color: "rank.normal"}
+
+// The thresholds of builtinRanks per "<mode>/<difficulty>".
+// There are no thresholds for the custom difficulty, they are calibrated from the autoplay.
+var builtinRankThresholds = map[string][]float64{
+	"timeAttack/easy": []float64{11, 9, 7, 5},
+	"timeAttack/normal": []float64{6, 5, 4, 3},
+	"timeAttack/hard": []float64{5, 4, 3, 2},
+	"sprint/easy": []float64{15, 22, 30, 40},
+	"sprint/normal": []float64{20, 30, 40, 60},
+	"sprint/hard": []float64{28, 40, 55, 80},
+	"survival/easy": []float64{20, 15, 10, 6},
+	"survival/normal": []float64{15, 10, 7, 4},
+	"survival/hard": []float64{12, 8, 5, 3},
+}
+
+func mapRankConfigToRank(rankConfig *config.RankConfig) *Rank {
+	color := rankConfig.Color
+	if color == "" {
+		color = "rank.normal"
+	}
+	return &Rank{Title: rankConfig.Title, Color: color}
+}
+
+// The ratios of the thresholds to the optimum score, in order from the best.
+// They are spaced evenly from 0.9 to 0.3.
+func calculateCalibrationRatios(rankCount int) []float64 {
+	ratios := make([]float64, rankCount)
+	for i := 0; i < rankCount; i++ {
+		ratios[i] = 0.9 - float64(i) * 0.6 / float64(rankCount)
+	}
+	return ratios
+}
+
+// Derive the thresholds from the optimum score of the autoplay.
+func calibrateRankThresholds(scoreUnit ScoreUnit, optimumScore float64, rankCount int) []float64 {
+	thresholds := make([]float64, 0)
+	for _, ratio := range calculateCalibrationRatios(rankCount) {
+		switch scoreUnit {
+		case ScoreUnitSeconds:
+			thresholds = append(thresholds, math.Round(optimumScore / ratio * 10) / 10)
+		default:
+			thresholds = append(thresholds, math.Max(1, math.Round(optimumScore * ratio)))
+		}
+	}
+	return thresholds
+}
+
+func validateRankThresholds(scoreUnit ScoreUnit, tiers []*RankTier) error {
+	for i := 1; i < len(tiers); i++ {
+		if scoreUnit == ScoreUnitSeconds && tiers[i-1].Threshold > tiers[i].Threshold {
+			return errors.Errorf("The thresholds of the ranks must be in ascending order in modes scored by time.")
+		} else if scoreUnit == ScoreUnitFloors && tiers[i-1].Threshold < tiers[i].Threshold {
+			return errors.Errorf("The thresholds of the ranks must be in descending order.")
+		}
+	}
+	return nil
+}
+
+// Returns nil if the mode does not rank, such as a mode without a time limit and a goal.
+// The table is looked up in the order of the config and the built-in tables.
+// If neither has it or the config requests, it is calibrated from the autoplay.
+func CreateRankTable(
+	mode GameMode, difficulty *Difficulty, rankTableConfigs map[string]*config.RankTableConfig) (*RankTable, error) {
+	if mode.CalculateTimeLimit(1) == 0 && mode.GetGoalFloorNumber() == 0 {
+		return nil, nil
+	}
+
+	rankTable := &RankTable{
+		scoreUnit: mode.GetScoreUnit(),
+		tiers: make([]*RankTier, 0),
+		lowest: builtinLowestRank,
+	}
+	ranks := builtinRanks
+	thresholds, hasBuiltinThresholds := builtinRankThresholds[mode.GetName() + "/" + difficulty.GetName()]
+	isCalibrated := !hasBuiltinThresholds
+
+	rankTableConfig, ok := rankTableConfigs[mode.GetName() + "/" + difficulty.GetName()]
+	if !ok {
+		rankTableConfig, ok = rankTableConfigs[mode.GetName()]
+	}
+	if ok {
+		isCalibrated = rankTableConfig.Auto
+		if len(rankTableConfig.Ranks) > 0 {
+			ranks = make([]*Rank, 0)
+			thresholds = make([]float64, 0)
+			for _, rankConfig := range rankTableConfig.Ranks {
+				ranks = append(ranks, mapRankConfigToRank(rankConfig))
+				thresholds = append(thresholds, rankConfig.Threshold)
+			}
+		} else if !rankTableConfig.Auto {
+			return nil, errors.Errorf("The rank table of the mode \"%s\" has no ranks.", mode.GetName())
+		}
+		if rankTableConfig.Lowest != nil {
+			rankTable.lowest = mapRankConfigToRank(rankTableConfig.Lowest)
+		}
+	}
+
+	if isCalibrated {
+		autoplayResult, err := SimulateAutoplay(mode, difficulty)
+		if err != nil {
+			return nil, err
+		}
+		thresholds = calibrateRankThresholds(mode.GetScoreUnit(), autoplayResult.Score, len(ranks))
+	}
+
+	for i, rank := range ranks {
+		rankTable.tiers = append(rankTable.tiers, &RankTier{Threshold: thresholds[i], Rank: rank})
+	}
+	if err := validateRankThresholds(rankTable.scoreUnit, rankTable.tiers); err != nil {
+		return nil, err
+	}
+	return rankTable, nil
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"github.com/kjirou/tower-of-go/config"
+	"testing"
+)
+
+func createRankTableOf(t *testing.T, modeName string, difficultyName string, rankTableConfigs map[string]*config.RankTableConfig) *RankTable {
+	mode, _ := CreateGameMode(modeName)
+	difficulty, _ := CreateDifficulty(difficultyName, nil)
+	rankTable, err := CreateRankTable(mode, difficulty, rankTableConfigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rankTable
+}
+
+func TestRankTable_DecideRank_NotTD(t *testing.T) {
+	t.Run("タイムアタックのふつうは従来のランクである", func(t *testing.T) {
+		rankTable := createRankTableOf(t, "timeAttack", "normal", nil)
+		testCases := map[float64]string{
+			2: "rank.noGood",
+			3: "rank.good",
+			4: "rank.excellent",
+			5: "rank.marvelous",
+			6: "rank.gopher",
+			9: "rank.gopher",
+		}
+		for score, messageKey := range testCases {
+			if rankTable.DecideRank(score).MessageKey != messageKey {
+				t.Fatalf("%v のランクが %s ではない", score, messageKey)
+			}
+		}
+	})
+
+	t.Run("時間で採点するモードは短いほど高い", func(t *testing.T) {
+		rankTable := createRankTableOf(t, "sprint", "normal", nil)
+		if rankTable.DecideRank(19).MessageKey != "rank.gopher" {
+			t.Fatal("最高のランクではない")
+		} else if rankTable.DecideRank(61).MessageKey != "rank.noGood" {
+			t.Fatal("最低のランクではない")
+		}
+	})
+
+	t.Run("禅モードはランク付けしない", func(t *testing.T) {
+		rankTable := createRankTableOf(t, "zen", "normal", nil)
+		if rankTable.DecideRank(100) != nil {
+			t.Fatal("ランクがある")
+		}
+	})
+}
+
+func TestCreateRankTable_NotTD(t *testing.T) {
+	t.Run("設定の表を優先する", func(t *testing.T) {
+		rankTable := createRankTableOf(t, "timeAttack", "normal", map[string]*config.RankTableConfig{
+			"timeAttack": &config.RankTableConfig{
+				Ranks: []*config.RankConfig{&config.RankConfig{Threshold: 2, Title: "Nice", Color: "red"}},
+				Lowest: &config.RankConfig{Title: "Oops"},
+			},
+		})
+		if rank := rankTable.DecideRank(2); rank.Title != "Nice" || rank.Color != "red" {
+			t.Fatal("設定のランクではない")
+		} else if rank := rankTable.DecideRank(1); rank.Title != "Oops" || rank.Color != "rank.normal" {
+			t.Fatal("設定の最低のランクではない")
+		}
+	})
+
+	t.Run("閾値の順序が不正なとき、エラーを返す", func(t *testing.T) {
+		mode, _ := CreateGameMode("timeAttack")
+		difficulty, _ := CreateDifficulty("normal", nil)
+		_, err := CreateRankTable(mode, difficulty, map[string]*config.RankTableConfig{
+			"timeAttack/normal": &config.RankTableConfig{
+				Ranks: []*config.RankConfig{&config.RankConfig{Threshold: 2}, &config.RankConfig{Threshold: 3}},
+			},
+		})
+		if err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+
+	t.Run("カスタムの難易度は自動調整した閾値になる", func(t *testing.T) {
+		rankTable := createRankTableOf(t, "sprint", "custom", nil)
+		tiers := rankTable.GetTiers()
+		if len(tiers) != len(builtinRanks) {
+			t.Fatal("ランクの数が違う")
+		}
+		for i := 1; i < len(tiers); i++ {
+			if tiers[i-1].Threshold > tiers[i].Threshold {
+				t.Fatal("閾値が昇順ではない")
+			}
+		}
+	})
+
+	t.Run("自動調整した閾値は毎回同じになる", func(t *testing.T) {
+		tiers := createRankTableOf(t, "timeAttack", "custom", nil).GetTiers()
+		otherTiers := createRankTableOf(t, "timeAttack", "custom", nil).GetTiers()
+		for i := range tiers {
+			if tiers[i].Threshold != otherTiers[i].Threshold {
+				t.Fatal("閾値が違う")
+			}
+		}
+	})
+}
+
+func TestCalibrateRankThresholds_NotTD(t *testing.T) {
+	t.Run("階数は最適値に比率を掛けて丸める", func(t *testing.T) {
+		thresholds := calibrateRankThresholds(ScoreUnitFloors, 10, 4)
+		expected := []float64{9, 8, 6, 5}
+		for i, threshold := range thresholds {
+			if threshold != expected[i] {
+				t.Fatalf("%v ではなく %v である", expected[i], threshold)
+			}
+		}
+	})
+}
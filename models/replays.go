@@ -0,0 +1,105 @@
+package models
+
+import (
+	"fmt"
+	"github.com/kjirou/tower-of-go/utils"
+	"math"
+	"time"
+)
+
+// A position of the hero in a recorded run.
+type ReplayStep struct {
+	// The playtime when the hero stepped on the position.
+	Time time.Duration `json:"time"`
+	FloorNumber int `json:"floorNumber"`
+	Y int `json:"y"`
+	X int `json:"x"`
+}
+
+// A recorded run, it is played back as a ghost in games of the same key.
+type Replay struct {
+	Key string `json:"key"`
+	Score float64 `json:"score"`
+	// The total points, it breaks ties of the score.
+	Points int `json:"points"`
+	ScoreUnit ScoreUnit `json:"scoreUnit"`
+	// In order of the time. The first step of each floor is the start of the floor.
+	Steps []*ReplayStep `json:"steps"`
+}
+
+func (replay *Replay) IsBetterThan(other *Replay) bool {
+	return other == nil || IsBetterScore(replay.ScoreUnit, replay.Score, replay.Points, other.Score, other.Points)
+}
+
+// Returns the step that the hero was on at the playtime, or nil before the first step.
+func (replay *Replay) FindStepAt(playtime time.Duration) *ReplayStep {
+	var found *ReplayStep
+	for _, step := range replay.Steps {
+		if step.Time > playtime {
+			break
+		}
+		found = step
+	}
+	return found
+}
+
+// The floors of games of the same key are the same, so their runs can be raced.
+// It is empty if the floors are not reproducible, that is a generated tower without a fixed seed.
+func CreateReplayKey(mode GameMode, difficulty *Difficulty, fixedSeed int64) string {
+	modeKey := mode.GetName()
+	if customTowerMode, ok := mode.(*CustomTowerMode); ok {
+		modeKey += ":" + customTowerMode.GetTowerName()
+	} else if fixedSeed == 0 {
+		return ""
+	} else {
+		modeKey += fmt.Sprintf(":%d", fixedSeed)
+	}
+	return modeKey + "/" + difficulty.GetName()
+}
+
+func (game *Game) CreateReplayKey() string {
+	return CreateReplayKey(game.GetMode(), game.GetDifficulty(), game.fixedSeed)
+}
+
+// It is called when the hero is placed at the start of a floor and every time the hero moves.
+func (game *Game) RecordHeroPosition(executionTime time.Duration, position *utils.MatrixPosition) {
+	game.replaySteps = append(game.replaySteps, &ReplayStep{
+		Time: game.CalculatePlaytime(executionTime),
+		FloorNumber: game.floorNumber,
+		Y: position.Y,
+		X: position.X,
+	})
+}
+
+// Returns nil if the game can not be raced again, such as an unfinished game or a game of random floors.
+func (game *Game) CreateReplay(executionTime time.Duration) *Replay {
+	key := game.CreateReplayKey()
+	score := game.CalculateScore(executionTime)
+	if !game.IsFinished() || key == "" || math.IsInf(score, 0) {
+		return nil
+	}
+	return &Replay{
+		Key: key,
+		Score: score,
+		Points: game.CalculateScoreBreakdown().CalculateTotal(),
+		ScoreUnit: game.GetMode().GetScoreUnit(),
+		Steps: game.replaySteps,
+	}
+}
+
+func (game *Game) GetGhost() *Replay {
+	return game.ghost
+}
+
+// The ghost is kept through the games, it is replaced when a better run finishes.
+func (game *Game) SetGhost(ghost *Replay) {
+	game.ghost = ghost
+}
+
+// Returns the step of the ghost at the current playtime, or nil if there is no ghost.
+func (game *Game) FindGhostStep(executionTime time.Duration) *ReplayStep {
+	if game.ghost == nil || !game.IsStarted() {
+		return nil
+	}
+	return game.ghost.FindStepAt(game.CalculatePlaytime(executionTime))
+}
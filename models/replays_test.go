@@ -0,0 +1,131 @@
+package models
+
+import (
+	"github.com/kjirou/tower-of-go/utils"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReplay_IsBetterThan_NotTD(t *testing.T) {
+	t.Run("階層で競うモードは、階層が高いほうが良く、同じなら点数が高いほうが良い", func(t *testing.T) {
+		replay := &Replay{Score: 5, Points: 5100, ScoreUnit: ScoreUnitFloors}
+		if !replay.IsBetterThan(&Replay{Score: 4, Points: 5500, ScoreUnit: ScoreUnitFloors}) {
+			t.Fatal("階層が高いのに良くない")
+		} else if replay.IsBetterThan(&Replay{Score: 5, Points: 5200, ScoreUnit: ScoreUnitFloors}) {
+			t.Fatal("点数が低いのに良い")
+		}
+	})
+
+	t.Run("時間で競うモードは、時間が短いほうが良い", func(t *testing.T) {
+		replay := &Replay{Score: 20, ScoreUnit: ScoreUnitSeconds}
+		if !replay.IsBetterThan(&Replay{Score: 25, ScoreUnit: ScoreUnitSeconds}) {
+			t.Fatal("短いのに良くない")
+		}
+	})
+
+	t.Run("比べる記録がなければ良い", func(t *testing.T) {
+		if !(&Replay{}).IsBetterThan(nil) {
+			t.Fatal("良くない")
+		}
+	})
+}
+
+func TestReplay_FindStepAt_NotTD(t *testing.T) {
+	replay := &Replay{Steps: []*ReplayStep{
+		&ReplayStep{Time: 0, FloorNumber: 1, Y: 1, X: 1},
+		&ReplayStep{Time: time.Second, FloorNumber: 1, Y: 1, X: 2},
+		&ReplayStep{Time: 2 * time.Second, FloorNumber: 2, Y: 1, X: 1},
+	}}
+
+	t.Run("その時間までの最後の位置を返す", func(t *testing.T) {
+		step := replay.FindStepAt(1500 * time.Millisecond)
+		if step.FloorNumber != 1 || step.X != 2 {
+			t.Fatal("位置が違う")
+		}
+		if replay.FindStepAt(3 * time.Second).FloorNumber != 2 {
+			t.Fatal("次の階へ進んでいない")
+		}
+	})
+
+	t.Run("最初の位置より前はnilを返す", func(t *testing.T) {
+		if replay.FindStepAt(-time.Second) != nil {
+			t.Fatal("nilではない")
+		}
+	})
+}
+
+func TestCreateReplayKey_NotTD(t *testing.T) {
+	mode, _ := CreateGameMode("sprint")
+	difficulty, _ := CreateDifficulty("normal", nil)
+
+	t.Run("固定した乱数の種がなければ、生成される階は再現できないので空である", func(t *testing.T) {
+		if CreateReplayKey(mode, difficulty, 0) != "" {
+			t.Fatal("空ではない")
+		}
+	})
+
+	t.Run("モード、乱数の種、難易度を含む", func(t *testing.T) {
+		if CreateReplayKey(mode, difficulty, 42) != "sprint:42/normal" {
+			t.Fatal("キーが違う")
+		}
+	})
+
+	t.Run("手作りの塔は乱数の種がなくても、塔の名前で決まる", func(t *testing.T) {
+		towerMode, _ := CreateCustomTowerMode("tutorial", []*CustomFloor{&CustomFloor{Field: CreateField(5, 5)}})
+		if CreateReplayKey(towerMode, difficulty, 0) != "custom:tutorial/normal" {
+			t.Fatal("キーが違う")
+		}
+	})
+}
+
+func TestGame_Replay_NotTD(t *testing.T) {
+	t.Run("固定した乱数の種のゲームは、毎回同じ階を生成する", func(t *testing.T) {
+		createFloorFields := func() []*Field {
+			game := createGameOfMode(t, "sprint")
+			game.SetFixedSeed(42)
+			game.Reset()
+			fields := make([]*Field, 0)
+			for i := 0; i < 3; i++ {
+				field, _ := CreateFloorField(game)
+				fields = append(fields, field)
+				game.IncrementFloorNumber()
+			}
+			return fields
+		}
+		if !reflect.DeepEqual(createFloorFields(), createFloorFields()) {
+			t.Fatal("階が違う")
+		}
+	})
+
+	t.Run("終わったゲームの記録が幽霊より良ければ、幽霊になる", func(t *testing.T) {
+		game := createGameOfMode(t, "sprint")
+		game.SetFixedSeed(42)
+		game.SetGhost(&Replay{Key: "sprint:42/normal", Score: 100, ScoreUnit: ScoreUnitSeconds})
+		game.Reset()
+		game.Start(time.Second)
+		game.RecordHeroPosition(time.Second, &utils.MatrixPosition{Y: 1, X: 1})
+		for i := 0; i < 4; i++ {
+			game.ClearFloor(time.Second)
+		}
+		game.Finish(11 * time.Second)
+		ghost := game.GetGhost()
+		if ghost.Score != 10 {
+			t.Fatal("幽霊が替わっていない")
+		} else if len(ghost.Steps) != 1 {
+			t.Fatal("位置が記録されていない")
+		}
+	})
+
+	t.Run("乱数の種を固定しないゲームは記録しない", func(t *testing.T) {
+		game := createGameOfMode(t, "sprint")
+		game.Start(time.Second)
+		for i := 0; i < 4; i++ {
+			game.ClearFloor(time.Second)
+		}
+		game.Finish(11 * time.Second)
+		if game.CreateReplay(11 * time.Second) != nil || game.GetGhost() != nil {
+			t.Fatal("記録している")
+		}
+	})
+}
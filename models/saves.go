@@ -0,0 +1,241 @@
+package models
+
+import (
+	"github.com/kjirou/tower-of-go/config"
+	"github.com/kjirou/tower-of-go/utils"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// The version of the format of SavedRun.
+// It is increased when the format changes, and the saves of other versions are rejected.
+// 2 added the fields of the played floors, and 3 added the breadcrumbs.
+const SavedRunVersion = 3
+
+type SavedPosition struct {
+	Y int `json:"y"`
+	X int `json:"x"`
+}
+
+// The symbols of the rows are "#" for a wall, "@" for the hero, "<" for the upstairs and "." for an empty cell.
+type SavedField struct {
+	Rows []string `json:"rows"`
+	Start *SavedPosition `json:"start"`
+	Upstairs *SavedPosition `json:"upstairs"`
+	Breadcrumbs []*SavedPosition `json:"breadcrumbs,omitempty"`
+}
+
+// A game in progress that is written to a file, and resumed later.
+// The rank table and the ghost are not saved, they are taken again from the config and the replays.
+type SavedRun struct {
+	Version int `json:"version"`
+	ModeName string `json:"mode"`
+	// The name of the hand-made tower, it is empty for the built-in modes.
+	TowerName string `json:"tower,omitempty"`
+	DifficultyName string `json:"difficulty"`
+	// It is nil except for the custom difficulty.
+	CustomDifficulty *config.DifficultyConfig `json:"customDifficulty,omitempty"`
+	ExecutionTime time.Duration `json:"executionTime"`
+	Field *SavedField `json:"field"`
+	FloorNumber int `json:"floorNumber"`
+	StartedAt time.Duration `json:"startedAt"`
+	FloorRecords []*FloorRecord `json:"floorRecords"`
+	FloorStartedAt time.Duration `json:"floorStartedAt"`
+	MoveCount int `json:"moveCount"`
+	OptimalPathLength int `json:"optimalPathLength"`
+	FixedSeed int64 `json:"fixedSeed"`
+	Seed int64 `json:"seed"`
+	// The state of the random numbers, so that the following floors are the same as without quitting.
+	RandomState uint64 `json:"randomState"`
+	ReplaySteps []*ReplayStep `json:"replaySteps"`
+	// The fields of the played floors for the heatmaps, in order of the floor number.
+	// There is one for every floor up to the current one.
+	FloorFields []*SavedField `json:"floorFields,omitempty"`
+}
+
+// Returns the settings of the custom difficulty, that create the same difficulty with CreateDifficulty.
+func (difficulty *Difficulty) ToConfig() *config.DifficultyConfig {
+	return &config.DifficultyConfig{
+		RowLength: difficulty.rowLength,
+		ColumnLength: difficulty.columnLength,
+		Algorithm: utils.MazeAlgorithmNames[difficulty.algorithm],
+		LoopDensity: difficulty.loopDensity,
+		TimeLimitRate: difficulty.timeLimitRate,
+		GrowthInterval: difficulty.growthInterval,
+		MaxRowLength: difficulty.maxRowLength,
+		MaxColumnLength: difficulty.maxColumnLength,
+		LoopDensityDelta: difficulty.loopDensityDelta,
+		MinSolutionRatio: difficulty.minSolutionRatio,
+		MaxSolutionRatio: difficulty.maxSolutionRatio,
+	}
+}
+
+func (state *State) IsGameInProgress() bool {
+	game := state.GetGame()
+	return state.GetCurrentScene() == SceneGame && game.IsStarted() && !game.IsFinished()
+}
+
+func createSavedField(field *Field) *SavedField {
+	rows := make([]string, 0)
+	breadcrumbs := make([]*SavedPosition, 0)
+	for _, row := range field.matrix {
+		symbols := make([]rune, 0)
+		for _, element := range row {
+			if element.GetOverlayClass() == "breadcrumb" {
+				breadcrumbs = append(breadcrumbs, &SavedPosition{Y: element.GetPosition().Y, X: element.GetPosition().X})
+			}
+			symbol := '.'
+			switch {
+			case element.GetObjectClass() == "wall":
+				symbol = '#'
+			case element.GetObjectClass() == "hero":
+				symbol = '@'
+			case element.GetFloorObjectClass() == "upstairs":
+				symbol = '<'
+			}
+			symbols = append(symbols, symbol)
+		}
+		rows = append(rows, string(symbols))
+	}
+	return &SavedField{
+		Rows: rows,
+		Start: &SavedPosition{Y: field.GetStartPosition().Y, X: field.GetStartPosition().X},
+		Upstairs: &SavedPosition{Y: field.GetUpstairsPosition().Y, X: field.GetUpstairsPosition().X},
+		Breadcrumbs: breadcrumbs,
+	}
+}
+
+// Returns nil if there is no game in progress. The playtests in the editor are not saved.
+func (state *State) CreateSavedRun() *SavedRun {
+	if !state.IsGameInProgress() || state.ContainsScene(SceneEditor) {
+		return nil
+	}
+	game := state.GetGame()
+
+	savedRun := &SavedRun{
+		Version: SavedRunVersion,
+		ModeName: game.GetMode().GetName(),
+		DifficultyName: game.GetDifficulty().GetName(),
+		ExecutionTime: state.GetExecutionTime(),
+		Field: createSavedField(state.GetField()),
+		FloorNumber: game.floorNumber,
+		StartedAt: game.startedAt,
+		FloorRecords: append([]*FloorRecord{}, game.floorRecords...),
+		FloorStartedAt: game.floorStartedAt,
+		MoveCount: game.moveCount,
+		OptimalPathLength: game.optimalPathLength,
+		FixedSeed: game.fixedSeed,
+		Seed: game.seed,
+		RandomState: game.randomSource.state,
+		ReplaySteps: append([]*ReplayStep{}, game.replaySteps...),
+		FloorFields: make([]*SavedField, 0),
+	}
+	for _, floorField := range game.floorFields {
+		savedRun.FloorFields = append(savedRun.FloorFields, createSavedField(floorField))
+	}
+	if customTowerMode, ok := game.GetMode().(*CustomTowerMode); ok {
+		savedRun.TowerName = customTowerMode.GetTowerName()
+	}
+	if savedRun.DifficultyName == "custom" {
+		savedRun.CustomDifficulty = game.GetDifficulty().ToConfig()
+	}
+	return savedRun
+}
+
+func restoreSavedField(savedField *SavedField) (*Field, error) {
+	if savedField == nil || len(savedField.Rows) == 0 || savedField.Start == nil || savedField.Upstairs == nil {
+		return nil, errors.Errorf("The field of the saved run is empty.")
+	}
+	field := createField(len(savedField.Rows), len([]rune(savedField.Rows[0])))
+	for y, row := range savedField.Rows {
+		symbols := []rune(row)
+		if len(symbols) != field.MeasureColumnLength() {
+			return nil, errors.Errorf("The rows of the saved field have different lengths.")
+		}
+		for x, symbol := range symbols {
+			element := field.matrix[y][x]
+			switch symbol {
+			case '#':
+				element.UpdateObjectClass("wall")
+			case '@':
+				element.UpdateObjectClass("hero")
+			case '<':
+				element.UpdateFloorObjectClass("upstairs")
+			case '.':
+			default:
+				return nil, errors.Errorf("The symbol \"%c\" of the saved field is unknown.", symbol)
+			}
+		}
+	}
+	start := &utils.MatrixPosition{Y: savedField.Start.Y, X: savedField.Start.X}
+	upstairs := &utils.MatrixPosition{Y: savedField.Upstairs.Y, X: savedField.Upstairs.X}
+	for _, position := range []*utils.MatrixPosition{start, upstairs} {
+		if !position.Validate(field.MeasureRowLength(), field.MeasureColumnLength()) {
+			return nil, errors.Errorf("The position (%d, %d) is outside the saved field.", position.Y, position.X)
+		}
+	}
+	field.SetStartPosition(start)
+	field.SetUpstairsPosition(upstairs)
+	for _, savedPosition := range savedField.Breadcrumbs {
+		element, err := field.At(&utils.MatrixPosition{Y: savedPosition.Y, X: savedPosition.X})
+		if err != nil {
+			return nil, err
+		}
+		element.UpdateOverlayClass("breadcrumb")
+	}
+	if _, err := field.GetElementOfHero(); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+// Returns the state in the game scene of the saved run, it is paused until the player is ready.
+// The mode and the difficulty of the setup must be the ones of the saved run, the seed of the setup is ignored.
+func RestoreSavedRun(savedRun *SavedRun, setup *GameSetup) (*State, error) {
+	if savedRun.Version != SavedRunVersion {
+		return nil, errors.Errorf(
+			"The saved run is version %d, but this version can only resume version %d.", savedRun.Version, SavedRunVersion)
+	}
+	field, err := restoreSavedField(savedRun.Field)
+	if err != nil {
+		return nil, err
+	}
+
+	state := CreateState()
+	state.executionTime = savedRun.ExecutionTime
+	state.field = field
+	state.sceneStack = []Scene{SceneTitle, SceneModeSelect, SceneGame}
+	state.isPaused = true
+
+	game := state.GetGame()
+	game.SetMode(setup.Mode)
+	game.SetDifficulty(setup.Difficulty)
+	game.SetRankTable(setup.RankTable)
+	game.SetFixedSeed(savedRun.FixedSeed)
+	game.SetGhost(setup.Ghost)
+	game.Reset()
+	game.floorNumber = savedRun.FloorNumber
+	game.startedAt = savedRun.StartedAt
+	game.floorRecords = append([]*FloorRecord{}, savedRun.FloorRecords...)
+	game.floorStartedAt = savedRun.FloorStartedAt
+	game.moveCount = savedRun.MoveCount
+	game.optimalPathLength = savedRun.OptimalPathLength
+	game.seed = savedRun.Seed
+	game.randomSource.state = savedRun.RandomState
+	game.replaySteps = append([]*ReplayStep{}, savedRun.ReplaySteps...)
+	if len(savedRun.FloorFields) != savedRun.FloorNumber {
+		return nil, errors.Errorf(
+			"The saved run has %d fields for %d floors.", len(savedRun.FloorFields), savedRun.FloorNumber)
+	}
+	for _, savedFloorField := range savedRun.FloorFields {
+		floorField, err := restoreSavedField(savedFloorField)
+		if err != nil {
+			return nil, err
+		}
+		game.floorFields = append(game.floorFields, floorField)
+	}
+	if !game.IsStarted() {
+		return nil, errors.Errorf("The saved run has not been started.")
+	}
+	return state, nil
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"encoding/json"
+	"github.com/kjirou/tower-of-go/config"
+	"github.com/kjirou/tower-of-go/utils"
+	"testing"
+	"time"
+)
+
+func createStateInProgress(t *testing.T, modeName string) *State {
+	state := CreateState()
+	state.game = createGameOfMode(t, modeName)
+	state.AlterExecutionTime(time.Second)
+	state.GetGame().Start(state.GetExecutionTime())
+	field, err := CreateFloorField(state.GetGame())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := state.PlaceFloor(field); err != nil {
+		t.Fatal(err)
+	}
+	state.PushScene(SceneModeSelect)
+	state.PushScene(SceneGame)
+	return state
+}
+
+func TestState_CreateSavedRun_NotTD(t *testing.T) {
+	t.Run("ゲーム中でなければnilを返す", func(t *testing.T) {
+		if CreateState().CreateSavedRun() != nil {
+			t.Fatal("nilではない")
+		}
+	})
+
+	t.Run("JSONを経由して同じ状態に戻せる", func(t *testing.T) {
+		state := createStateInProgress(t, "sprint")
+		game := state.GetGame()
+		game.KeepFloorField(state.GetField())
+		breadcrumbElement, _ := state.GetField().At(&utils.MatrixPosition{Y: 1, X: 2})
+		breadcrumbElement.UpdateOverlayClass("breadcrumb")
+		game.ClearFloor(state.GetExecutionTime())
+		game.KeepFloorField(state.GetField())
+		game.random.Int63()
+
+		content, err := json.Marshal(state.CreateSavedRun())
+		if err != nil {
+			t.Fatal(err)
+		}
+		savedRun := &SavedRun{}
+		if err := json.Unmarshal(content, savedRun); err != nil {
+			t.Fatal(err)
+		}
+		restored, err := RestoreSavedRun(savedRun, &GameSetup{Mode: game.GetMode(), Difficulty: game.GetDifficulty()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		restoredGame := restored.GetGame()
+		if !restored.IsPaused() || restored.GetCurrentScene() != SceneGame {
+			t.Fatal("一時停止したゲームではない")
+		} else if restored.GetExecutionTime() != state.GetExecutionTime() ||
+			restoredGame.GetFloorNumber() != 2 || len(restoredGame.GetFloorRecords()) != 1 {
+			t.Fatal("ゲームの進行が違う")
+		} else if restoredGame.GetSeed() != game.GetSeed() || restoredGame.random.Int63() != game.random.Int63() {
+			t.Fatal("乱数が違う")
+		} else if restoredGame.CountKeptFloorFields() != 2 {
+			t.Fatal("階ごとのフィールドが違う")
+		}
+		if restoredElement, _ := restored.GetField().At(&utils.MatrixPosition{Y: 1, X: 2}); restoredElement.GetOverlayClass() != "breadcrumb" {
+			t.Fatal("目印が違う")
+		}
+		for y := 0; y < state.GetField().MeasureRowLength(); y++ {
+			for x := 0; x < state.GetField().MeasureColumnLength(); x++ {
+				element := state.GetField().matrix[y][x]
+				restoredElement := restored.GetField().matrix[y][x]
+				if element.GetObjectClass() != restoredElement.GetObjectClass() ||
+					element.GetFloorObjectClass() != restoredElement.GetFloorObjectClass() {
+					t.Fatalf("(%d, %d) が違う", y, x)
+				}
+			}
+		}
+	})
+
+	t.Run("カスタムの難易度は設定ごと保存する", func(t *testing.T) {
+		state := createStateInProgress(t, "timeAttack")
+		difficulty, _ := CreateDifficulty("custom", &config.DifficultyConfig{RowLength: 7, ColumnLength: 9})
+		state.GetGame().SetDifficulty(difficulty)
+		savedRun := state.CreateSavedRun()
+		restoredDifficulty, err := CreateDifficulty(savedRun.DifficultyName, savedRun.CustomDifficulty)
+		if err != nil {
+			t.Fatal(err)
+		} else if *restoredDifficulty != *difficulty {
+			t.Fatal("難易度が違う")
+		}
+	})
+}
+
+func TestRestoreSavedRun_NotTD(t *testing.T) {
+	t.Run("版が違うときエラーを返す", func(t *testing.T) {
+		state := createStateInProgress(t, "timeAttack")
+		savedRun := state.CreateSavedRun()
+		savedRun.Version = SavedRunVersion + 1
+		if _, err := RestoreSavedRun(savedRun, &GameSetup{Mode: state.GetGame().GetMode()}); err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+
+	t.Run("階ごとのフィールドがない古い版の保存はエラーを返す", func(t *testing.T) {
+		state := createStateInProgress(t, "timeAttack")
+		state.GetGame().KeepFloorField(state.GetField())
+		savedRun := state.CreateSavedRun()
+		savedRun.Version = 1
+		savedRun.FloorFields = nil
+		if _, err := RestoreSavedRun(savedRun, &GameSetup{Mode: state.GetGame().GetMode()}); err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+
+	t.Run("目印がない古い版の保存はエラーを返す", func(t *testing.T) {
+		state := createStateInProgress(t, "timeAttack")
+		state.GetGame().KeepFloorField(state.GetField())
+		savedRun := state.CreateSavedRun()
+		savedRun.Version = 2
+		savedRun.Field.Breadcrumbs = nil
+		if _, err := RestoreSavedRun(savedRun, &GameSetup{Mode: state.GetGame().GetMode()}); err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+
+	t.Run("階ごとのフィールドが階の数と合わないときエラーを返す", func(t *testing.T) {
+		state := createStateInProgress(t, "timeAttack")
+		state.GetGame().KeepFloorField(state.GetField())
+		state.GetGame().IncrementFloorNumber()
+		savedRun := state.CreateSavedRun()
+		if _, err := RestoreSavedRun(savedRun, &GameSetup{Mode: state.GetGame().GetMode()}); err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+
+	t.Run("主人公がいないフィールドはエラーを返す", func(t *testing.T) {
+		state := createStateInProgress(t, "timeAttack")
+		savedRun := state.CreateSavedRun()
+		savedRun.Field.Rows = []string{"#####", "#...#", "#####"}
+		if _, err := RestoreSavedRun(savedRun, &GameSetup{Mode: state.GetGame().GetMode()}); err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+)
+
+// The record of a cleared floor.
+type FloorRecord struct {
+	FloorNumber int `json:"floorNumber"`
+	// The time from the start of the floor to reaching the upstairs.
+	ClearTime time.Duration `json:"clearTime"`
+	MoveCount int `json:"moveCount"`
+	OptimalPathLength int `json:"optimalPathLength"`
+	// The remaining time of the game when the floor was cleared. It is 0 in modes without a time limit.
+	RemainingTime time.Duration `json:"remainingTime"`
+}
+
+// Returns the rate of the optimal path length to the move count, from 0 to 1.
+func (floorRecord *FloorRecord) CalculateEfficiency() float64 {
+	if floorRecord.MoveCount <= 0 || floorRecord.OptimalPathLength <= 0 {
+		return 0
+	} else if floorRecord.MoveCount < floorRecord.OptimalPathLength {
+		return 1
+	}
+	return float64(floorRecord.OptimalPathLength) / float64(floorRecord.MoveCount)
+}
+
+const (
+	PointsPerFloor = 1000
+	PointsPerRemainingSecond = 10
+	// The points of a floor cleared along the shortest path.
+	MaxEfficiencyPointsPerFloor = 100
+)
+
+// The total points that combine the floors, the remaining time and the path efficiency.
+// It breaks ties between players who reached the same floor.
+type ScoreBreakdown struct {
+	FloorPoints int
+	// The remaining time when the last floor was cleared. Reaching the floor earlier gets more points.
+	TimeBonus int
+	EfficiencyBonus int
+}
+
+func (scoreBreakdown *ScoreBreakdown) CalculateTotal() int {
+	return scoreBreakdown.FloorPoints + scoreBreakdown.TimeBonus + scoreBreakdown.EfficiencyBonus
+}
+
+// Compare the scores of the same mode. The total points break ties.
+func IsBetterScore(scoreUnit ScoreUnit, score float64, points int, otherScore float64, otherPoints int) bool {
+	if score != otherScore {
+		if scoreUnit == ScoreUnitSeconds {
+			return score < otherScore
+		}
+		return score > otherScore
+	}
+	return points > otherPoints
+}
+
+func (game *Game) GetFloorRecords() []*FloorRecord {
+	return game.floorRecords
+}
+
+// It is called every time the hero moves.
+func (game *Game) IncrementMoveCount() {
+	game.moveCount += 1
+}
+
+// Start to record the current floor.
+func (game *Game) BeginFloor(executionTime time.Duration, optimalPathLength int) {
+	game.floorStartedAt = executionTime
+	game.moveCount = 0
+	game.optimalPathLength = optimalPathLength
+}
+
+// Record the current floor, and go to the next floor.
+func (game *Game) ClearFloor(executionTime time.Duration) {
+	game.floorRecords = append(game.floorRecords, &FloorRecord{
+		FloorNumber: game.floorNumber,
+		ClearTime: executionTime - game.floorStartedAt,
+		MoveCount: game.moveCount,
+		OptimalPathLength: game.optimalPathLength,
+		RemainingTime: game.CalculateRemainingTime(executionTime),
+	})
+	game.IncrementFloorNumber()
+}
+
+func (game *Game) CalculateScoreBreakdown() *ScoreBreakdown {
+	scoreBreakdown := &ScoreBreakdown{}
+	for _, floorRecord := range game.floorRecords {
+		scoreBreakdown.FloorPoints += PointsPerFloor
+		scoreBreakdown.EfficiencyBonus += int(float64(MaxEfficiencyPointsPerFloor) * floorRecord.CalculateEfficiency())
+	}
+	if len(game.floorRecords) > 0 {
+		lastFloorRecord := game.floorRecords[len(game.floorRecords)-1]
+		scoreBreakdown.TimeBonus = int(lastFloorRecord.RemainingTime.Seconds() * PointsPerRemainingSecond)
+	}
+	return scoreBreakdown
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFloorRecord_CalculateEfficiency_NotTD(t *testing.T) {
+	t.Run("最短経路の歩数と実際の歩数の比を返す", func(t *testing.T) {
+		floorRecord := &FloorRecord{MoveCount: 40, OptimalPathLength: 30}
+		if floorRecord.CalculateEfficiency() != 0.75 {
+			t.Fatal("比が違う")
+		}
+	})
+
+	t.Run("歩いていないなら0を返す", func(t *testing.T) {
+		floorRecord := &FloorRecord{MoveCount: 0, OptimalPathLength: 30}
+		if floorRecord.CalculateEfficiency() != 0 {
+			t.Fatal("0ではない")
+		}
+	})
+}
+
+func TestGame_CalculateScoreBreakdown_NotTD(t *testing.T) {
+	t.Run("同じ階層でも、早く効率良く到達した方が高い", func(t *testing.T) {
+		play := func(clearTime time.Duration, moveCount int) int {
+			game := &Game{}
+			game.Reset()
+			game.Start(time.Second)
+			executionTime := time.Second
+			for i := 0; i < 4; i++ {
+				game.BeginFloor(executionTime, 20)
+				for j := 0; j < moveCount; j++ {
+					game.IncrementMoveCount()
+				}
+				executionTime += clearTime
+				game.ClearFloor(executionTime)
+			}
+			return game.CalculateScoreBreakdown().CalculateTotal()
+		}
+		if play(5*time.Second, 20) <= play(6*time.Second, 20) {
+			t.Fatal("早く到達した方が低い")
+		} else if play(5*time.Second, 20) <= play(5*time.Second, 30) {
+			t.Fatal("効率良く到達した方が低い")
+		}
+	})
+
+	t.Run("階ごとの記録を残す", func(t *testing.T) {
+		game := &Game{}
+		game.Reset()
+		game.Start(time.Second)
+		game.BeginFloor(time.Second, 20)
+		game.ClearFloor(4 * time.Second)
+		floorRecords := game.GetFloorRecords()
+		if len(floorRecords) != 1 {
+			t.Fatal("記録の数が違う")
+		} else if floorRecords[0].FloorNumber != 1 || floorRecords[0].ClearTime != 3*time.Second {
+			t.Fatal("記録が違う")
+		} else if game.GetFloorNumber() != 2 {
+			t.Fatal("次の階へ進んでいない")
+		}
+	})
+}
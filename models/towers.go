@@ -0,0 +1,101 @@
+package models
+
+import (
+	"github.com/pkg/errors"
+	"math"
+	"time"
+)
+
+// A game mode that provides hand-made floors instead of generated mazes.
+type FloorProvider interface {
+	// Returns a new field every time, because the field is changed in the game.
+	CreateFloorField(floorNumber int) (*Field, error)
+}
+
+// Returns the field of the current floor of the game. The hero and the upstairs are not placed yet.
+func CreateFloorField(game *Game) (*Field, error) {
+	if floorProvider, ok := game.GetMode().(FloorProvider); ok {
+		return floorProvider.CreateFloorField(game.GetFloorNumber())
+	}
+	settings := game.GetDifficulty().CalculateFloorSettings(game.GetFloorNumber())
+	// The same seed generates the same floors.
+	settings.MazeOptions.Random = game.random
+	field := createField(settings.RowLength, settings.ColumnLength)
+	if err := field.ResetMaze(settings.MazeOptions); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+// A floor designed by hand.
+type CustomFloor struct {
+	Name string
+	// The time that is added when the floor starts. Zero means that the floor adds no time.
+	TimeLimit time.Duration
+	// The hero and the upstairs are placed at the start and the upstairs positions of it.
+	Field *Field
+}
+
+// Climb a sequence of hand-made floors, the score is the time to clear all of them.
+// The time limits of the floors are accumulated, the game has no time limit if no floor has it.
+type CustomTowerMode struct {
+	name string
+	floors []*CustomFloor
+}
+
+func (mode *CustomTowerMode) GetName() string {
+	return "custom"
+}
+
+// The name of the tower, such as the name of the map file.
+func (mode *CustomTowerMode) GetTowerName() string {
+	return mode.name
+}
+
+func (mode *CustomTowerMode) GetFloors() []*CustomFloor {
+	return mode.floors
+}
+
+func (mode *CustomTowerMode) CalculateTimeLimit(floorNumber int) time.Duration {
+	var timeLimit time.Duration
+	for i := 0; i < floorNumber && i < len(mode.floors); i++ {
+		timeLimit += mode.floors[i].TimeLimit
+	}
+	return timeLimit
+}
+
+// Reaching the floor above the last one clears the tower.
+func (mode *CustomTowerMode) GetGoalFloorNumber() int {
+	return len(mode.floors) + 1
+}
+
+func (mode *CustomTowerMode) IsFinished(game *Game, executionTime time.Duration) bool {
+	return game.GetFloorNumber() >= mode.GetGoalFloorNumber() ||
+		game.HasTimeLimit() && game.CalculateRemainingTime(executionTime) == 0
+}
+
+// The score of a game that did not clear the tower is infinite, it is the lowest in the unit of seconds.
+func (mode *CustomTowerMode) CalculateScore(game *Game, executionTime time.Duration) float64 {
+	if game.GetFloorNumber() < mode.GetGoalFloorNumber() {
+		return math.Inf(1)
+	}
+	return game.CalculatePlaytime(executionTime).Seconds()
+}
+
+func (mode *CustomTowerMode) GetScoreUnit() ScoreUnit {
+	return ScoreUnitSeconds
+}
+
+func (mode *CustomTowerMode) CreateFloorField(floorNumber int) (*Field, error) {
+	if floorNumber < 1 || floorNumber > len(mode.floors) {
+		return nil, errors.Errorf("The floor %d does not exist in the tower \"%s\".", floorNumber, mode.name)
+	}
+	return mode.floors[floorNumber-1].Field.Clone(), nil
+}
+
+func CreateCustomTowerMode(name string, floors []*CustomFloor) (*CustomTowerMode, error) {
+	if len(floors) == 0 {
+		return nil, errors.Errorf("The tower \"%s\" has no floors.", name)
+	}
+	return &CustomTowerMode{name: name, floors: floors}, nil
+}
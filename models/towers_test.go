@@ -0,0 +1,57 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func createTestTowerMode(t *testing.T, timeLimits ...time.Duration) *CustomTowerMode {
+	floors := make([]*CustomFloor, 0)
+	for _, timeLimit := range timeLimits {
+		floors = append(floors, &CustomFloor{TimeLimit: timeLimit, Field: createField(5, 5)})
+	}
+	mode, err := CreateCustomTowerMode("test", floors)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mode
+}
+
+func TestCustomTowerMode_NotTD(t *testing.T) {
+	t.Run("各階の制限時間を累積する", func(t *testing.T) {
+		mode := createTestTowerMode(t, 10*time.Second, 0, 5*time.Second)
+		if mode.CalculateTimeLimit(1) != 10*time.Second || mode.CalculateTimeLimit(3) != 15*time.Second {
+			t.Fatal("制限時間が違う")
+		}
+	})
+
+	t.Run("フィールドは毎回複製する", func(t *testing.T) {
+		mode := createTestTowerMode(t, 0)
+		field, _ := mode.CreateFloorField(1)
+		element, _ := field.At(field.GetStartPosition())
+		element.UpdateObjectClass("hero")
+		another, _ := mode.CreateFloorField(1)
+		anotherElement, _ := another.At(another.GetStartPosition())
+		if anotherElement.GetObjectClass() != "empty" {
+			t.Fatal("フィールドを共有している")
+		}
+	})
+
+	t.Run("頂上へ到達しなかったときのスコアは無限大である", func(t *testing.T) {
+		mode := createTestTowerMode(t, 10*time.Second)
+		game := &Game{}
+		game.SetMode(mode)
+		game.Reset()
+		game.Start(time.Second)
+		if !math.IsInf(mode.CalculateScore(game, 20*time.Second), 1) {
+			t.Fatal("無限大ではない")
+		} else if !mode.IsFinished(game, 20*time.Second) {
+			t.Fatal("時間切れで終了していない")
+		}
+		game.IncrementFloorNumber()
+		if mode.CalculateScore(game, 6*time.Second) != 5 {
+			t.Fatal("到達までの秒数ではない")
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"github.com/kjirou/tower-of-go/utils"
+)
+
+// Returns the cells that the hero stepped on in the floor, in order of the visits.
+// The first one is the start of the floor. They are the positions of the replay steps.
+func (game *Game) ListVisitedPositions(floorNumber int) []*utils.MatrixPosition {
+	positions := make([]*utils.MatrixPosition, 0)
+	for _, step := range game.replaySteps {
+		if step.FloorNumber == floorNumber {
+			positions = append(positions, &utils.MatrixPosition{Y: step.Y, X: step.X})
+		}
+	}
+	return positions
+}
+
+// Returns the number of the visits by cell.
+func CountVisits(positions []*utils.MatrixPosition) map[utils.MatrixPosition]int {
+	counts := make(map[utils.MatrixPosition]int)
+	for _, position := range positions {
+		counts[*position]++
+	}
+	return counts
+}
+
+// Keep a copy of the field of the floor that starts now, for the heatmaps after the game.
+func (game *Game) KeepFloorField(field *Field) {
+	game.floorFields = append(game.floorFields, field.Clone())
+}
+
+// Returns the number of the floors that have been played, including the current floor.
+func (game *Game) CountKeptFloorFields() int {
+	return len(game.floorFields)
+}
+
+// Returns nil if the floor has not been played.
+func (game *Game) GetFloorField(floorNumber int) *Field {
+	if floorNumber < 1 || floorNumber > len(game.floorFields) {
+		return nil
+	}
+	return game.floorFields[floorNumber - 1]
+}
+
+// Returns the visited cells that are proven to lead to nothing but dead ends.
+// A visited cell is a dead end if it has at most one passable neighbor that is not a dead end.
+// The cells that the hero has not visited may lead anywhere, so they are never dead ends and keep their neighbors open.
+// The loops are not proven, and the upstairs is never a dead end.
+func (field *Field) FindDeadEnds(visitCounts map[utils.MatrixPosition]int) map[utils.MatrixPosition]bool {
+	deadEnds := make(map[utils.MatrixPosition]bool)
+	isOpen := func(position utils.MatrixPosition) bool {
+		element, err := field.At(&position)
+		return err == nil && element.GetObjectClass() != "wall" && !deadEnds[position]
+	}
+	upstairsPosition := *field.GetUpstairsPosition()
+	for {
+		isChanged := false
+		for position := range visitCounts {
+			if deadEnds[position] || position == upstairsPosition || !isOpen(position) {
+				continue
+			}
+			openNeighborCount := 0
+			for _, neighbor := range []utils.MatrixPosition{
+				utils.MatrixPosition{Y: position.Y - 1, X: position.X},
+				utils.MatrixPosition{Y: position.Y, X: position.X + 1},
+				utils.MatrixPosition{Y: position.Y + 1, X: position.X},
+				utils.MatrixPosition{Y: position.Y, X: position.X - 1},
+			} {
+				if isOpen(neighbor) {
+					openNeighborCount++
+				}
+			}
+			if openNeighborCount <= 1 {
+				deadEnds[position] = true
+				isChanged = true
+			}
+		}
+		if !isChanged {
+			return deadEnds
+		}
+	}
+}
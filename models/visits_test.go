@@ -0,0 +1,100 @@
+package models
+
+import (
+	"github.com/kjirou/tower-of-go/utils"
+	"testing"
+)
+
+func TestGame_ListVisitedPositions_NotTD(t *testing.T) {
+	t.Run("指定した階の位置だけを訪れた順に返す", func(t *testing.T) {
+		game := &Game{}
+		game.Reset()
+		game.RecordHeroPosition(0, &utils.MatrixPosition{Y: 1, X: 1})
+		game.RecordHeroPosition(0, &utils.MatrixPosition{Y: 1, X: 2})
+		game.IncrementFloorNumber()
+		game.RecordHeroPosition(0, &utils.MatrixPosition{Y: 3, X: 3})
+		positions := game.ListVisitedPositions(1)
+		if len(positions) != 2 {
+			t.Fatalf("%d positions are listed", len(positions))
+		} else if positions[0].X != 1 || positions[1].X != 2 {
+			t.Fatal("順番が違う")
+		} else if len(game.ListVisitedPositions(3)) != 0 {
+			t.Fatal("遊んでいない階の位置を返している")
+		}
+	})
+}
+
+func TestCountVisits_NotTD(t *testing.T) {
+	t.Run("同じマスの訪問を数える", func(t *testing.T) {
+		counts := CountVisits([]*utils.MatrixPosition{
+			&utils.MatrixPosition{Y: 1, X: 1},
+			&utils.MatrixPosition{Y: 1, X: 2},
+			&utils.MatrixPosition{Y: 1, X: 1},
+		})
+		if len(counts) != 2 {
+			t.Fatalf("%d cells are counted", len(counts))
+		} else if counts[utils.MatrixPosition{Y: 1, X: 1}] != 2 || counts[utils.MatrixPosition{Y: 1, X: 2}] != 1 {
+			t.Fatal("数が違う")
+		}
+	})
+}
+
+func TestGame_KeepFloorField_NotTD(t *testing.T) {
+	t.Run("階ごとのフィールドの複製を保持する", func(t *testing.T) {
+		state := CreateState()
+		state.SetWelcomeData()
+		game := &Game{}
+		game.Reset()
+		game.KeepFloorField(state.GetField())
+		state.GetField().MoveObject(&utils.MatrixPosition{Y: 1, X: 1}, &utils.MatrixPosition{Y: 1, X: 2})
+		if game.CountKeptFloorFields() != 1 {
+			t.Fatal("保持していない")
+		}
+		element, _ := game.GetFloorField(1).GetElementOfHero()
+		if element.GetPosition().GetX() != 1 {
+			t.Fatal("元のフィールドを共有している")
+		}
+	})
+
+	t.Run("遊んでいない階はnilを返す", func(t *testing.T) {
+		game := &Game{}
+		game.Reset()
+		if game.GetFloorField(0) != nil || game.GetFloorField(1) != nil {
+			t.Fatal("nilではない")
+		}
+	})
+}
+
+func TestField_FindDeadEnds_NotTD(t *testing.T) {
+	field, err := restoreSavedField(&SavedField{
+		Rows: []string{"#######", "#..@..#", "#.###.#", "#.###<#", "#######"},
+		Start: &SavedPosition{Y: 1, X: 3},
+		Upstairs: &SavedPosition{Y: 3, X: 5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	countVisits := func(yxs ...int) map[utils.MatrixPosition]int {
+		positions := make([]*utils.MatrixPosition, 0)
+		for i := 0; i < len(yxs); i += 2 {
+			positions = append(positions, &utils.MatrixPosition{Y: yxs[i], X: yxs[i + 1]})
+		}
+		return CountVisits(positions)
+	}
+
+	t.Run("行き止まりまで訪れた通路を返す", func(t *testing.T) {
+		deadEnds := field.FindDeadEnds(countVisits(1, 3, 1, 2, 1, 1, 2, 1, 3, 1, 2, 1, 1, 1, 1, 2, 1, 3))
+		if len(deadEnds) != 5 {
+			t.Fatalf("%d dead ends are found", len(deadEnds))
+		} else if !deadEnds[utils.MatrixPosition{Y: 3, X: 1}] || !deadEnds[utils.MatrixPosition{Y: 1, X: 3}] {
+			t.Fatal("行き止まりが違う")
+		}
+	})
+
+	t.Run("訪れていない先がある通路と上り階段は行き止まりにしない", func(t *testing.T) {
+		deadEnds := field.FindDeadEnds(countVisits(1, 3, 1, 2, 1, 1, 2, 1, 1, 3, 1, 4, 1, 5, 2, 5, 3, 5))
+		if len(deadEnds) != 0 {
+			t.Fatalf("%d dead ends are found", len(deadEnds))
+		}
+	})
+}
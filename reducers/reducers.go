@@ -1,5 +1,11 @@
 package reducers
 
+//
+// The reducers receive the state by value, but it still shares the field, the game and the editor with the caller.
+// Therefore, a reducer copies them before it changes them, and the state of the caller remains as it was.
+// The other reducers, such as the one of every frame, do not copy them.
+//
+
 import(
 	"github.com/pkg/errors"
 	"github.com/kjirou/tower-of-go/models"
@@ -15,7 +21,31 @@ const (
 	FourDirectionLeft
 )
 
+// Generate the current floor of the game, and start to record it.
+func prepareFloorOfGame(state *models.State) error {
+	game := state.GetGame()
+	field, err := models.CreateFloorField(game)
+	if err != nil {
+		return err
+	}
+	err = state.PlaceFloor(field)
+	if err != nil {
+		return err
+	}
+	game.KeepFloorField(field)
+	game.BeginFloor(
+		state.GetExecutionTime(),
+		field.MeasureShortestPathLength(field.GetStartPosition(), field.GetUpstairsPosition()),
+	)
+	game.RecordHeroPosition(state.GetExecutionTime(), field.GetStartPosition())
+	return nil
+}
+
 func proceedMainLoopFrame(state *models.State, elapsedTime time.Duration) (*models.State, error) {
+	if state.IsPaused() {
+		return state, nil
+	}
+
 	game := state.GetGame()
 	field := state.GetField()
 
@@ -26,25 +56,42 @@ func proceedMainLoopFrame(state *models.State, elapsedTime time.Duration) (*mode
 		if getElementOfHeroErr != nil {
 			return state, errors.WithStack(getElementOfHeroErr)
 		}
-		if (heroFieldElement.GetFloorObjectClass() == "upstairs") {
-			// Generate a new maze.
-			// Remove the hero.
-			err := field.ResetMaze()
-			if err != nil {
-				return state, errors.WithStack(err)
-			}
+		isOnUpstairs := heroFieldElement.GetFloorObjectClass() == "upstairs"
+		// The game changes only on the stairs or at the end, most frames do not copy it.
+		if isOnUpstairs || game.GetMode().IsFinished(game, state.GetExecutionTime()) {
+			state.CopyFieldAndGame()
+			game = state.GetGame()
+		}
+		if isOnUpstairs {
+			game.ClearFloor(state.GetExecutionTime())
+			floorRecords := game.GetFloorRecords()
+			state.EmitEvent(&models.FloorClearedEvent{
+				Time: state.GetExecutionTime(),
+				Record: floorRecords[len(floorRecords)-1],
+			})
 
+			// Generate a new maze that follows the progression of the difficulty.
 			// Relocate the hero to the entrance.
-			replacedHeroFieldElement, _ := field.At(models.HeroPosition)
-			replacedHeroFieldElement.UpdateObjectClass("hero")
-
-			game.IncrementFloorNumber()
+			// There is no next floor if the game finishes here, such as the top of a tower.
+			if !game.GetMode().IsFinished(game, state.GetExecutionTime()) {
+				err := prepareFloorOfGame(state)
+				if err != nil {
+					return state, errors.WithStack(err)
+				}
+			}
 		}
 
-		// Time over of this game.
-		remainingTime := game.CalculateRemainingTime(state.GetExecutionTime())
-		if remainingTime == 0 {
-			game.Finish()
+		// The end of this game, such as time over or reaching the goal.
+		if game.GetMode().IsFinished(game, state.GetExecutionTime()) {
+			game.Finish(state.GetExecutionTime())
+			state.EmitEvent(&models.GameFinishedEvent{
+				Time: state.GetExecutionTime(),
+				Game: game,
+				IsPlaytest: state.ContainsScene(models.SceneEditor),
+			})
+			if state.GetCurrentScene() == models.SceneGame {
+				state.ReplaceScene(models.SceneResults)
+			}
 		}
 	}
 
@@ -58,28 +105,31 @@ func AdvanceOnlyTime(state models.State, elapsedTime time.Duration) (*models.Sta
 }
 
 func StartOrRestartGame(state models.State, elapsedTime time.Duration) (*models.State, error) {
+	state.CopyFieldAndGame()
 	game := state.GetGame()
-	field := state.GetField()
-
-	// Generate a new maze.
-	// Remove the hero.
-	err := field.ResetMaze()
-	if err != nil {
-		return &state, errors.WithStack(err)
-	}
-
-	// Replace the hero.
-	heroFieldElement, _ := field.At(models.HeroPosition)
-	heroFieldElement.UpdateObjectClass("hero")
 
 	// Start the new game.
 	game.Reset()
 	game.Start(state.GetExecutionTime())
+	state.EmitEvent(&models.GameStartedEvent{
+		Time: state.GetExecutionTime(),
+		ModeName: game.GetMode().GetName(),
+		DifficultyName: game.GetDifficulty().GetName(),
+		Seed: game.GetSeed(),
+	})
+
+	// Generate a new maze of the first floor.
+	// Replace the hero.
+	err := prepareFloorOfGame(&state)
+	if err != nil {
+		return &state, errors.WithStack(err)
+	}
 
 	return proceedMainLoopFrame(&state, elapsedTime)
 }
 
 func WalkHero(state models.State, elapsedTime time.Duration, direction FourDirection) (*models.State, error) {
+	state.CopyFieldAndGame()
 	game := state.GetGame()
 	if game.IsFinished() {
 		return &state, nil
@@ -112,9 +162,207 @@ func WalkHero(state models.State, elapsedTime time.Duration, direction FourDirec
 		if err != nil {
 			return &state, errors.WithStack(err)
 		} else if element.IsObjectEmpty() {
+			if game.IsStarted() {
+				game.SaveSnapshot(&state)
+			}
 			err := field.MoveObject(position, nextPosition)
+			if err == nil && game.IsStarted() {
+				game.IncrementMoveCount()
+				game.RecordHeroPosition(state.GetExecutionTime(), nextPosition)
+				state.EmitEvent(&models.HeroMovedEvent{
+					Time: state.GetExecutionTime(),
+					FloorNumber: game.GetFloorNumber(),
+					From: position,
+					To: nextPosition,
+				})
+			}
+			return &state, errors.WithStack(err)
+		}
+	}
+	return proceedMainLoopFrame(&state, elapsedTime)
+}
+
+// Go back to the field and the game before the last move, including the floor and the playtime.
+// It only advances the time if there is no move to go back.
+func RewindGame(state models.State, elapsedTime time.Duration) (*models.State, error) {
+	state.CopyFieldAndGame()
+	game := state.GetGame()
+	if game.IsFinished() {
+		return proceedMainLoopFrame(&state, elapsedTime)
+	}
+	snapshot := game.PopSnapshot()
+	if snapshot == nil {
+		return proceedMainLoopFrame(&state, elapsedTime)
+	}
+	// The breadcrumbs are not moves, they are kept on the same floor.
+	if snapshot.GetGame().GetFloorNumber() == game.GetFloorNumber() {
+		snapshot.GetField().CopyOverlays(state.GetField())
+	}
+	state.RestoreSnapshot(snapshot)
+	element, err := state.GetField().GetElementOfHero()
+	if err != nil {
+		return &state, errors.WithStack(err)
+	}
+	state.EmitEvent(&models.HeroRewoundEvent{
+		Time: state.GetExecutionTime(),
+		FloorNumber: state.GetGame().GetFloorNumber(),
+		Position: element.GetPosition(),
+	})
+	return proceedMainLoopFrame(&state, elapsedTime)
+}
+
+// Drop a breadcrumb on the cell of the hero, or pick it up if there is one already.
+func ToggleBreadcrumb(state models.State, elapsedTime time.Duration) (*models.State, error) {
+	state.CopyFieldAndGame()
+	game := state.GetGame()
+	if !game.IsStarted() || game.IsFinished() {
+		return proceedMainLoopFrame(&state, elapsedTime)
+	}
+	element, err := state.GetField().GetElementOfHero()
+	if err != nil {
+		return &state, errors.WithStack(err)
+	}
+	if element.GetOverlayClass() == "breadcrumb" {
+		element.UpdateOverlayClass("empty")
+	} else {
+		element.UpdateOverlayClass("breadcrumb")
+	}
+	return proceedMainLoopFrame(&state, elapsedTime)
+}
+
+// Restart the time of the paused game.
+func ResumeGame(state models.State, elapsedTime time.Duration) (*models.State, error) {
+	state.SetPaused(false)
+	return proceedMainLoopFrame(&state, elapsedTime)
+}
+
+func MoveMenuCursor(state models.State, elapsedTime time.Duration, delta int, itemCount int) (*models.State, error) {
+	state.MoveMenuCursor(delta, itemCount)
+	return proceedMainLoopFrame(&state, elapsedTime)
+}
+
+func PushScene(state models.State, elapsedTime time.Duration, scene models.Scene) (*models.State, error) {
+	state.PushScene(scene)
+	return proceedMainLoopFrame(&state, elapsedTime)
+}
+
+// Go back to the previous scene. A game in progress is abandoned.
+func PopScene(state models.State, elapsedTime time.Duration) (*models.State, error) {
+	if state.GetCurrentScene() == models.SceneGame {
+		state.CopyFieldAndGame()
+		state.GetGame().Reset()
+		state.SetPaused(false)
+	}
+	err := state.PopScene()
+	if err != nil {
+		return &state, errors.WithStack(err)
+	}
+	return proceedMainLoopFrame(&state, elapsedTime)
+}
+
+// Show the field of the welcome, the game is started by the player.
+func EnterGameScene(state models.State, elapsedTime time.Duration, setup *models.GameSetup) (*models.State, error) {
+	state.CopyFieldAndGame()
+	game := state.GetGame()
+	mode := setup.Mode
+	difficulty := setup.Difficulty
+	game.SetMode(mode)
+	game.SetDifficulty(difficulty)
+	game.SetRankTable(setup.RankTable)
+	game.SetFixedSeed(setup.Seed)
+	game.SetGhost(setup.Ghost)
+	game.Reset()
+	state.SetPaused(false)
+	// A hand-made tower shows the first floor, because its size and shape are fixed.
+	if floorProvider, ok := mode.(models.FloorProvider); ok {
+		field, err := floorProvider.CreateFloorField(game.GetFloorNumber())
+		if err != nil {
+			return &state, errors.WithStack(err)
+		}
+		err = state.PlaceFloor(field)
+		if err != nil {
+			return &state, errors.WithStack(err)
+		}
+	} else {
+		floorSettings := difficulty.CalculateFloorSettings(game.GetFloorNumber())
+		state.ResizeField(floorSettings.RowLength, floorSettings.ColumnLength)
+		state.GetField().Clear()
+		err := state.SetWelcomeData()
+		if err != nil {
 			return &state, errors.WithStack(err)
 		}
 	}
+	state.PushScene(models.SceneGame)
 	return proceedMainLoopFrame(&state, elapsedTime)
 }
+
+// Start a new game from the results.
+func RetryGame(state models.State, elapsedTime time.Duration) (*models.State, error) {
+	state.ReplaceScene(models.SceneGame)
+	return StartOrRestartGame(state, elapsedTime)
+}
+
+func OpenEditor(state models.State, elapsedTime time.Duration, editor *models.Editor) (*models.State, error) {
+	state.SetEditor(editor)
+	state.PushScene(models.SceneEditor)
+	return proceedMainLoopFrame(&state, elapsedTime)
+}
+
+func MoveEditorCursor(state models.State, elapsedTime time.Duration, direction FourDirection) (*models.State, error) {
+	state.CopyEditor()
+	editor := state.GetEditor()
+	switch direction {
+	case FourDirectionUp:
+		editor.MoveCursor(-1, 0)
+	case FourDirectionRight:
+		editor.MoveCursor(0, 1)
+	case FourDirectionDown:
+		editor.MoveCursor(1, 0)
+	case FourDirectionLeft:
+		editor.MoveCursor(0, -1)
+	}
+	return proceedMainLoopFrame(&state, elapsedTime)
+}
+
+func SelectEditorTile(state models.State, elapsedTime time.Duration, index int) (*models.State, error) {
+	state.CopyEditor()
+	err := state.GetEditor().SelectTile(index)
+	if err != nil {
+		return &state, errors.WithStack(err)
+	}
+	return proceedMainLoopFrame(&state, elapsedTime)
+}
+
+func CycleEditorTile(state models.State, elapsedTime time.Duration, delta int) (*models.State, error) {
+	state.CopyEditor()
+	state.GetEditor().CycleTile(delta)
+	return proceedMainLoopFrame(&state, elapsedTime)
+}
+
+// A tile that can not be placed is not an error of the application, it is told to the author.
+func PlaceEditorTile(state models.State, elapsedTime time.Duration) (*models.State, error) {
+	state.CopyEditor()
+	editor := state.GetEditor()
+	editor.SetNotice(nil)
+	if err := editor.PlaceTile(); err != nil {
+		editor.SetNotice(&models.EditorNotice{MessageKey: "editor.cannotPlace", Detail: err.Error(), IsError: true})
+	}
+	return proceedMainLoopFrame(&state, elapsedTime)
+}
+
+func SetEditorNotice(state models.State, elapsedTime time.Duration, notice *models.EditorNotice) (*models.State, error) {
+	state.CopyEditor()
+	state.GetEditor().SetNotice(notice)
+	return proceedMainLoopFrame(&state, elapsedTime)
+}
+
+// Start a game of the floor in the editor at once. Going back from the game returns to the editor.
+func PlaytestEditorFloor(state models.State, elapsedTime time.Duration, setup *models.GameSetup) (*models.State, error) {
+	state.CopyEditor()
+	state.GetEditor().SetNotice(nil)
+	newState, err := EnterGameScene(state, 0, setup)
+	if err != nil {
+		return newState, err
+	}
+	return StartOrRestartGame(*newState, elapsedTime)
+}
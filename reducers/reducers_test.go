@@ -0,0 +1,136 @@
+package reducers
+
+import (
+	"fmt"
+	"github.com/kjirou/tower-of-go/models"
+	"github.com/kjirou/tower-of-go/utils"
+	"testing"
+	"time"
+)
+
+// Describes the field and the game of the state, so that the state before and after a reducer can be compared.
+func describeState(state *models.State) string {
+	field := state.GetField()
+	description := ""
+	for y := 0; y < field.MeasureRowLength(); y++ {
+		for x := 0; x < field.MeasureColumnLength(); x++ {
+			element, _ := field.At(&utils.MatrixPosition{Y: y, X: x})
+			description += fmt.Sprintf(
+				"%s/%s/%s,", element.GetObjectClass(), element.GetFloorObjectClass(), element.GetOverlayClass())
+		}
+	}
+	game := state.GetGame()
+	return description + fmt.Sprint(
+		state.GetExecutionTime(),
+		state.GetCurrentScene(),
+		game.GetSeed(),
+		game.GetFloorNumber(),
+		game.IsStarted(),
+		game.IsFinished(),
+		game.CalculatePlaytime(state.GetExecutionTime()),
+		len(game.GetFloorRecords()),
+		game.CountKeptFloorFields(),
+		len(game.ListVisitedPositions(game.GetFloorNumber())),
+		game.CanRewind(),
+	)
+}
+
+// A zen game that has just started, the moves of it can be rewound.
+func createStartedState(t *testing.T) *models.State {
+	mode, _ := models.CreateGameMode("zen")
+	difficulty, _ := models.CreateDifficulty("normal", nil)
+	state := models.CreateState()
+	if err := state.SetWelcomeData(); err != nil {
+		t.Fatal(err)
+	}
+	// The zero time means that the game has not started.
+	state, err := EnterGameScene(*state, time.Second, &models.GameSetup{Mode: mode, Difficulty: difficulty})
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err = StartOrRestartGame(*state, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+// Move from the upper left corner, one of the right and the bottom is a passage.
+func walkHeroFromStart(t *testing.T, state *models.State) *models.State {
+	for _, direction := range []FourDirection{FourDirectionRight, FourDirectionDown} {
+		walkedState, err := WalkHero(*state, time.Second, direction)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if element, _ := walkedState.GetField().GetElementOfHero(); *element.GetPosition() != *models.HeroPosition {
+			return walkedState
+		}
+	}
+	t.Fatal("動けない")
+	return nil
+}
+
+func TestReducers_NotTD(t *testing.T) {
+	t.Run("時間だけを進めても元の状態は変わらない", func(t *testing.T) {
+		state := createStartedState(t)
+		before := describeState(state)
+		if _, err := AdvanceOnlyTime(*state, time.Second); err != nil {
+			t.Fatal(err)
+		} else if describeState(state) != before {
+			t.Fatal("元の状態が変わっている")
+		}
+	})
+
+	t.Run("階段に乗った時間を進めても元の状態は変わらない", func(t *testing.T) {
+		state := createStartedState(t).Clone()
+		field := state.GetField()
+		if err := field.MoveObject(models.HeroPosition, field.GetUpstairsPosition()); err != nil {
+			t.Fatal(err)
+		}
+		before := describeState(state)
+		newState, err := AdvanceOnlyTime(*state, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		} else if newState.GetGame().GetFloorNumber() != 2 {
+			t.Fatal("階を登っていない")
+		} else if describeState(state) != before {
+			t.Fatal("元の状態が変わっている")
+		}
+	})
+
+	t.Run("主人公を動かしても元の状態は変わらない", func(t *testing.T) {
+		state := createStartedState(t)
+		before := describeState(state)
+		walkHeroFromStart(t, state)
+		if describeState(state) != before {
+			t.Fatal("元の状態が変わっている")
+		}
+	})
+
+	t.Run("ゲームをやり直しても元の状態は変わらない", func(t *testing.T) {
+		state := walkHeroFromStart(t, createStartedState(t))
+		before := describeState(state)
+		if _, err := StartOrRestartGame(*state, time.Second); err != nil {
+			t.Fatal(err)
+		} else if describeState(state) != before {
+			t.Fatal("元の状態が変わっている")
+		}
+	})
+
+	t.Run("戻しても元の状態は変わらず、同じ状態から何度でも戻せる", func(t *testing.T) {
+		state := walkHeroFromStart(t, createStartedState(t))
+		before := describeState(state)
+		for i := 0; i < 2; i++ {
+			rewoundState, err := RewindGame(*state, time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if element, _ := rewoundState.GetField().GetElementOfHero(); *element.GetPosition() != *models.HeroPosition {
+				t.Fatal("戻っていない")
+			}
+		}
+		if describeState(state) != before {
+			t.Fatal("元の状態が変わっている")
+		}
+	})
+}
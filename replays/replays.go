@@ -0,0 +1,91 @@
+package replays
+
+//
+// The "replays" package keeps the best run of each replay key in a JSON file.
+// The file is optional, it is created when the first run is recorded.
+//
+
+import (
+	"encoding/json"
+	"github.com/kjirou/tower-of-go/models"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+type Store struct {
+	// The file that the store was loaded from. It is also the destination of saving.
+	filePath string
+	// The keys are the replay keys of the games.
+	Replays map[string]*models.Replay `json:"replays"`
+}
+
+// Returns nil if there is no replay of the key.
+func (store *Store) Find(key string) *models.Replay {
+	if key == "" {
+		return nil
+	}
+	return store.Replays[key]
+}
+
+// Keep the replay if it is better than the one of the same key. Returns whether it is kept.
+func (store *Store) Submit(replay *models.Replay) bool {
+	if replay == nil || !replay.IsBetterThan(store.Find(replay.Key)) {
+		return false
+	}
+	store.Replays[replay.Key] = replay
+	return true
+}
+
+// Returns "$XDG_CONFIG_HOME/tower-of-go/replays.json" or the equivalent of the OS.
+func GetDefaultStoreFilePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return filepath.Join(configDir, "tower-of-go", "replays.json"), nil
+}
+
+// A store that is not saved to a file.
+func CreateStore() *Store {
+	return &Store{Replays: make(map[string]*models.Replay)}
+}
+
+// Returns an empty store if the file does not exist.
+func LoadStore(filePath string) (*Store, error) {
+	store := CreateStore()
+	store.filePath = filePath
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return store, nil
+	} else if err != nil {
+		return store, errors.WithStack(err)
+	}
+	if err := json.Unmarshal(content, store); err != nil {
+		return store, errors.Wrapf(err, "The replay file (%s) is invalid.", filePath)
+	}
+	if store.Replays == nil {
+		store.Replays = make(map[string]*models.Replay)
+	}
+	return store, nil
+}
+
+// Write the store to the file that it was loaded from.
+// A store that was not loaded from a file is not saved.
+func SaveStore(store *Store) error {
+	if store.filePath == "" {
+		return nil
+	}
+	content, err := json.Marshal(store)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(store.filePath), 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := ioutil.WriteFile(store.filePath, append(content, '\n'), 0644); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
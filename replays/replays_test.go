@@ -0,0 +1,75 @@
+package replays
+
+import (
+	"github.com/kjirou/tower-of-go/models"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore_Submit_NotTD(t *testing.T) {
+	t.Run("同じキーの記録より良いときだけ置き換える", func(t *testing.T) {
+		store := CreateStore()
+		if !store.Submit(&models.Replay{Key: "a", Score: 30, ScoreUnit: models.ScoreUnitSeconds}) {
+			t.Fatal("最初の記録を保持しない")
+		} else if store.Submit(&models.Replay{Key: "a", Score: 40, ScoreUnit: models.ScoreUnitSeconds}) {
+			t.Fatal("悪い記録を保持する")
+		} else if !store.Submit(&models.Replay{Key: "a", Score: 20, ScoreUnit: models.ScoreUnitSeconds}) {
+			t.Fatal("良い記録を保持しない")
+		} else if store.Find("a").Score != 20 {
+			t.Fatal("記録が違う")
+		}
+	})
+
+	t.Run("nilは保持しない", func(t *testing.T) {
+		if CreateStore().Submit(nil) {
+			t.Fatal("保持する")
+		}
+	})
+}
+
+func TestLoadStore_NotTD(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "tower-of-go")
+	defer os.RemoveAll(dir)
+
+	t.Run("ファイルが存在しないとき、空の保管庫を返す", func(t *testing.T) {
+		store, err := LoadStore(filepath.Join(dir, "missing.json"))
+		if err != nil {
+			t.Fatal(err)
+		} else if len(store.Replays) != 0 {
+			t.Fatal("空ではない")
+		}
+	})
+
+	t.Run("保存した記録を読み込む", func(t *testing.T) {
+		filePath := filepath.Join(dir, "sub", "replays.json")
+		store, _ := LoadStore(filePath)
+		store.Submit(&models.Replay{
+			Key: "sprint:42/normal",
+			Score: 20,
+			ScoreUnit: models.ScoreUnitSeconds,
+			Steps: []*models.ReplayStep{&models.ReplayStep{FloorNumber: 1, Y: 1, X: 1}},
+		})
+		if err := SaveStore(store); err != nil {
+			t.Fatal(err)
+		}
+		loadedStore, err := LoadStore(filePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		replay := loadedStore.Find("sprint:42/normal")
+		if replay == nil || replay.Score != 20 || len(replay.Steps) != 1 {
+			t.Fatal("記録が違う")
+		}
+	})
+
+	t.Run("ファイルが不正なとき、エラーを返す", func(t *testing.T) {
+		filePath := filepath.Join(dir, "invalid.json")
+		ioutil.WriteFile(filePath, []byte(`{`), 0644)
+		_, err := LoadStore(filePath)
+		if err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package saves
+
+//
+// The "saves" package keeps a game in progress in a JSON file, so that it can be resumed after quitting.
+// There is only one saved run, a new one overwrites it.
+//
+
+import (
+	"encoding/json"
+	"github.com/kjirou/tower-of-go/models"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Returns "$XDG_CONFIG_HOME/tower-of-go/save.json" or the equivalent of the OS.
+func GetDefaultSaveFilePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return filepath.Join(configDir, "tower-of-go", "save.json"), nil
+}
+
+func SaveRun(filePath string, savedRun *models.SavedRun) error {
+	content, err := json.MarshalIndent(savedRun, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := ioutil.WriteFile(filePath, append(content, '\n'), 0644); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Returns nil if there is no saved run.
+// The version is checked before the rest is read, because the format of other versions may differ.
+func LoadRun(filePath string) (*models.SavedRun, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(content, &header); err != nil {
+		return nil, errors.Wrapf(err, "The saved run (%s) is invalid.", filePath)
+	}
+	if header.Version != models.SavedRunVersion {
+		return nil, errors.Errorf(
+			"The saved run (%s) is version %d, but this version can only resume version %d.",
+			filePath, header.Version, models.SavedRunVersion)
+	}
+	savedRun := &models.SavedRun{}
+	if err := json.Unmarshal(content, savedRun); err != nil {
+		return nil, errors.Wrapf(err, "The saved run (%s) is invalid.", filePath)
+	}
+	return savedRun, nil
+}
+
+// It does nothing if there is no saved run.
+func RemoveRun(filePath string) error {
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return errors.WithStack(err)
+	}
+	return nil
+}
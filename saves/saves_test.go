@@ -0,0 +1,50 @@
+package saves
+
+import (
+	"github.com/kjirou/tower-of-go/models"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadRun_NotTD(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "tower-of-go")
+	defer os.RemoveAll(dir)
+
+	t.Run("ファイルが存在しないとき、nilを返す", func(t *testing.T) {
+		savedRun, err := LoadRun(filepath.Join(dir, "missing.json"))
+		if err != nil {
+			t.Fatal(err)
+		} else if savedRun != nil {
+			t.Fatal("nilではない")
+		}
+	})
+
+	t.Run("保存したゲームを読み込み、削除できる", func(t *testing.T) {
+		filePath := filepath.Join(dir, "save.json")
+		err := SaveRun(filePath, &models.SavedRun{Version: models.SavedRunVersion, ModeName: "zen", FloorNumber: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		savedRun, err := LoadRun(filePath)
+		if err != nil {
+			t.Fatal(err)
+		} else if savedRun.ModeName != "zen" || savedRun.FloorNumber != 3 {
+			t.Fatal("内容が違う")
+		}
+		if err := RemoveRun(filePath); err != nil {
+			t.Fatal(err)
+		} else if savedRun, _ := LoadRun(filePath); savedRun != nil {
+			t.Fatal("削除されていない")
+		}
+	})
+
+	t.Run("版が違うときエラーを返す", func(t *testing.T) {
+		filePath := filepath.Join(dir, "old.json")
+		ioutil.WriteFile(filePath, []byte(`{"version": 0, "field": "unknown format"}`), 0644)
+		if _, err := LoadRun(filePath); err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+}
@@ -0,0 +1,228 @@
+package stats
+
+//
+// The "stats" package keeps the history of the finished games in a JSON file, and summarizes it.
+// The file is optional, it is created when the first game is finished.
+//
+
+import (
+	"encoding/json"
+	"github.com/kjirou/tower-of-go/models"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type GameRecord struct {
+	FinishedAt time.Time `json:"finishedAt"`
+	Mode string `json:"mode"`
+	Difficulty string `json:"difficulty"`
+	// The reached floor.
+	FloorNumber int `json:"floorNumber"`
+	Score float64 `json:"score"`
+	Points int `json:"points"`
+	ScoreUnit models.ScoreUnit `json:"scoreUnit"`
+	// The clear times and the moves of the cleared floors.
+	Floors []*models.FloorRecord `json:"floors"`
+}
+
+// Returns the record of the finished game.
+// Returns nil if the game has no score, such as a hand-made tower that was not cleared. Its score is infinite.
+func CreateGameRecord(finishedAt time.Time, game *models.Game, executionTime time.Duration) *GameRecord {
+	score := game.CalculateScore(executionTime)
+	if math.IsInf(score, 0) {
+		return nil
+	}
+	return &GameRecord{
+		FinishedAt: finishedAt,
+		Mode: game.GetMode().GetName(),
+		Difficulty: game.GetDifficulty().GetName(),
+		FloorNumber: game.GetFloorNumber(),
+		Score: score,
+		Points: game.CalculateScoreBreakdown().CalculateTotal(),
+		ScoreUnit: game.GetMode().GetScoreUnit(),
+		Floors: append([]*models.FloorRecord{}, game.GetFloorRecords()...),
+	}
+}
+
+// Returns the mean of the efficiencies of the floors, from 0 to 1. It is 0 if no floor was cleared.
+func (gameRecord *GameRecord) CalculateEfficiency() float64 {
+	if len(gameRecord.Floors) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, floorRecord := range gameRecord.Floors {
+		total += floorRecord.CalculateEfficiency()
+	}
+	return total / float64(len(gameRecord.Floors))
+}
+
+// The games of a mode and a difficulty, their scores can be compared.
+type GameGroup struct {
+	Mode string
+	Difficulty string
+	// In order of finishing.
+	Games []*GameRecord
+}
+
+type Store struct {
+	// The file that the store was loaded from. It is also the destination of saving.
+	filePath string
+	// In order of finishing.
+	Games []*GameRecord `json:"games"`
+}
+
+func (store *Store) Append(gameRecord *GameRecord) {
+	store.Games = append(store.Games, gameRecord)
+}
+
+// Returns the groups in order of the game that was finished last, the latest first.
+func (store *Store) ListGroups() []*GameGroup {
+	groups := make([]*GameGroup, 0)
+	for i := len(store.Games) - 1; i >= 0; i-- {
+		gameRecord := store.Games[i]
+		var group *GameGroup
+		for _, g := range groups {
+			if g.Mode == gameRecord.Mode && g.Difficulty == gameRecord.Difficulty {
+				group = g
+				break
+			}
+		}
+		if group == nil {
+			group = &GameGroup{Mode: gameRecord.Mode, Difficulty: gameRecord.Difficulty}
+			groups = append(groups, group)
+		}
+		group.Games = append([]*GameRecord{gameRecord}, group.Games...)
+	}
+	return groups
+}
+
+// Returns the best score until each game, so the personal bests are followed through the games.
+func CalculateBestTrend(gameRecords []*GameRecord) []float64 {
+	trend := make([]float64, 0)
+	var best *GameRecord
+	for _, gameRecord := range gameRecords {
+		if best == nil ||
+			models.IsBetterScore(gameRecord.ScoreUnit, gameRecord.Score, gameRecord.Points, best.Score, best.Points) {
+			best = gameRecord
+		}
+		trend = append(trend, best.Score)
+	}
+	return trend
+}
+
+// Returns 0 if there is no game.
+func CalculateMeanScore(gameRecords []*GameRecord) float64 {
+	if len(gameRecords) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, gameRecord := range gameRecords {
+		total += gameRecord.Score
+	}
+	return total / float64(len(gameRecords))
+}
+
+// A range of the clear times of floors. It includes Min and excludes Max, except the last bin.
+type HistogramBin struct {
+	Min time.Duration
+	Max time.Duration
+	Count int
+}
+
+// Divide the range of the clear times of all floors into bins of the same width.
+// Returns no bins if no floor was cleared.
+func CalculateFloorTimeHistogram(gameRecords []*GameRecord, binCount int) []*HistogramBin {
+	clearTimes := make([]time.Duration, 0)
+	for _, gameRecord := range gameRecords {
+		for _, floorRecord := range gameRecord.Floors {
+			clearTimes = append(clearTimes, floorRecord.ClearTime)
+		}
+	}
+	if len(clearTimes) == 0 || binCount <= 0 {
+		return []*HistogramBin{}
+	}
+	minTime := clearTimes[0]
+	maxTime := clearTimes[0]
+	for _, clearTime := range clearTimes {
+		if clearTime < minTime {
+			minTime = clearTime
+		}
+		if clearTime > maxTime {
+			maxTime = clearTime
+		}
+	}
+	// All floors are in one bin if their times are the same.
+	if minTime == maxTime {
+		return []*HistogramBin{&HistogramBin{Min: minTime, Max: maxTime, Count: len(clearTimes)}}
+	}
+	width := (maxTime - minTime) / time.Duration(binCount)
+	if width <= 0 {
+		width = 1
+	}
+	bins := make([]*HistogramBin, binCount)
+	for i := range bins {
+		bins[i] = &HistogramBin{Min: minTime + width * time.Duration(i), Max: minTime + width * time.Duration(i + 1)}
+	}
+	bins[binCount - 1].Max = maxTime
+	for _, clearTime := range clearTimes {
+		index := int((clearTime - minTime) / width)
+		if index >= binCount {
+			index = binCount - 1
+		}
+		bins[index].Count++
+	}
+	return bins
+}
+
+// Returns "$XDG_CONFIG_HOME/tower-of-go/history.json" or the equivalent of the OS.
+func GetDefaultStoreFilePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return filepath.Join(configDir, "tower-of-go", "history.json"), nil
+}
+
+// A store that is not saved to a file.
+func CreateStore() *Store {
+	return &Store{Games: make([]*GameRecord, 0)}
+}
+
+// Returns an empty store if the file does not exist.
+func LoadStore(filePath string) (*Store, error) {
+	store := CreateStore()
+	store.filePath = filePath
+	content, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return store, nil
+	} else if err != nil {
+		return store, errors.WithStack(err)
+	}
+	if err := json.Unmarshal(content, store); err != nil {
+		return store, errors.Wrapf(err, "The history file (%s) is invalid.", filePath)
+	}
+	return store, nil
+}
+
+// Write the store to the file that it was loaded from.
+// A store that was not loaded from a file is not saved.
+func SaveStore(store *Store) error {
+	if store.filePath == "" {
+		return nil
+	}
+	content, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(store.filePath), 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := ioutil.WriteFile(store.filePath, append(content, '\n'), 0644); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package stats
+
+import (
+	"github.com/kjirou/tower-of-go/models"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func createGameRecord(mode string, score float64, clearTimes ...time.Duration) *GameRecord {
+	scoreUnit := models.ScoreUnitFloors
+	if mode == "sprint" {
+		scoreUnit = models.ScoreUnitSeconds
+	}
+	floors := make([]*models.FloorRecord, 0)
+	for i, clearTime := range clearTimes {
+		floors = append(floors, &models.FloorRecord{FloorNumber: i + 1, ClearTime: clearTime, MoveCount: 10, OptimalPathLength: 5})
+	}
+	return &GameRecord{Mode: mode, Difficulty: "normal", Score: score, ScoreUnit: scoreUnit, Floors: floors}
+}
+
+func TestStore_ListGroups_NotTD(t *testing.T) {
+	t.Run("モードと難易度ごとに、最後に遊んだ順で分ける", func(t *testing.T) {
+		store := CreateStore()
+		store.Append(createGameRecord("sprint", 30))
+		store.Append(createGameRecord("timeAttack", 3))
+		store.Append(createGameRecord("sprint", 25))
+		groups := store.ListGroups()
+		if len(groups) != 2 || groups[0].Mode != "sprint" || groups[1].Mode != "timeAttack" {
+			t.Fatal("分け方が違う")
+		} else if len(groups[0].Games) != 2 || groups[0].Games[0].Score != 30 || groups[0].Games[1].Score != 25 {
+			t.Fatal("ゲームが終えた順ではない")
+		}
+	})
+}
+
+func TestCalculateBestTrend_NotTD(t *testing.T) {
+	t.Run("秒数のモードでは、それまでの最小値を返す", func(t *testing.T) {
+		trend := CalculateBestTrend([]*GameRecord{
+			createGameRecord("sprint", 30), createGameRecord("sprint", 35), createGameRecord("sprint", 25)})
+		if len(trend) != 3 || trend[0] != 30 || trend[1] != 30 || trend[2] != 25 {
+			t.Fatalf("%v is wrong", trend)
+		}
+	})
+
+	t.Run("階数のモードでは、それまでの最大値を返す", func(t *testing.T) {
+		trend := CalculateBestTrend([]*GameRecord{
+			createGameRecord("timeAttack", 3), createGameRecord("timeAttack", 5), createGameRecord("timeAttack", 4)})
+		if len(trend) != 3 || trend[0] != 3 || trend[1] != 5 || trend[2] != 5 {
+			t.Fatalf("%v is wrong", trend)
+		}
+	})
+}
+
+func TestCalculateFloorTimeHistogram_NotTD(t *testing.T) {
+	t.Run("全ての階の時間を同じ幅の区間へ数える", func(t *testing.T) {
+		bins := CalculateFloorTimeHistogram([]*GameRecord{
+			createGameRecord("sprint", 0, 1*time.Second, 2*time.Second, 5*time.Second),
+			createGameRecord("sprint", 0, 3*time.Second),
+		}, 2)
+		if len(bins) != 2 {
+			t.Fatal("区間の数が違う")
+		} else if bins[0].Min != 1*time.Second || bins[1].Max != 5*time.Second {
+			t.Fatal("範囲が違う")
+		} else if bins[0].Count != 2 || bins[1].Count != 2 {
+			t.Fatalf("%d, %d are wrong", bins[0].Count, bins[1].Count)
+		}
+	})
+
+	t.Run("全て同じ時間なら1つの区間にまとめ、階がなければ区間もない", func(t *testing.T) {
+		bins := CalculateFloorTimeHistogram([]*GameRecord{createGameRecord("sprint", 0, time.Second, time.Second)}, 4)
+		if len(bins) != 1 || bins[0].Count != 2 {
+			t.Fatal("まとめていない")
+		} else if len(CalculateFloorTimeHistogram([]*GameRecord{createGameRecord("sprint", 0)}, 4)) != 0 {
+			t.Fatal("区間がある")
+		}
+	})
+}
+
+func TestLoadStore_NotTD(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "tower-of-go")
+	defer os.RemoveAll(dir)
+
+	t.Run("ファイルが存在しないとき、空の保管庫を返す", func(t *testing.T) {
+		store, err := LoadStore(filepath.Join(dir, "missing.json"))
+		if err != nil {
+			t.Fatal(err)
+		} else if len(store.Games) != 0 {
+			t.Fatal("空ではない")
+		}
+	})
+
+	t.Run("保存したゲームを各階の記録とともに読み込む", func(t *testing.T) {
+		filePath := filepath.Join(dir, "history.json")
+		store, _ := LoadStore(filePath)
+		store.Append(createGameRecord("sprint", 30, 2*time.Second))
+		if err := SaveStore(store); err != nil {
+			t.Fatal(err)
+		}
+		loadedStore, err := LoadStore(filePath)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(loadedStore.Games) != 1 || len(loadedStore.Games[0].Floors) != 1 ||
+			loadedStore.Games[0].Floors[0].ClearTime != 2*time.Second {
+			t.Fatal("ゲームを読み込んでいない")
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package themes
+
+import (
+	"github.com/nsf/termbox-go"
+	"github.com/pkg/errors"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type ColorMode int
+const (
+	// The 8 basic colors.
+	ColorModeBasic ColorMode = iota
+	// The xterm 256 colors.
+	// The termbox version in use can not output true colors, therefore they are approximated in this mode.
+	ColorMode256
+)
+
+// Decide the color mode from the terminal's environment variables.
+func DetectColorMode() ColorMode {
+	colorTerm := strings.ToLower(os.Getenv("COLORTERM"))
+	if colorTerm == "truecolor" || colorTerm == "24bit" {
+		return ColorMode256
+	}
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return ColorMode256
+	}
+	return ColorModeBasic
+}
+
+// The config value is "basic", "256" or "auto". An empty value is the same as "auto".
+func ParseColorMode(value string) (ColorMode, error) {
+	switch value {
+	case "", "auto":
+		return DetectColorMode(), nil
+	case "basic":
+		return ColorModeBasic, nil
+	case "256":
+		return ColorMode256, nil
+	}
+	return ColorModeBasic, errors.Errorf("The color mode \"%s\" is invalid.", value)
+}
+
+type rgb struct {
+	r int
+	g int
+	b int
+}
+
+var namedColors = map[string]termbox.Attribute{
+	"default": termbox.ColorDefault,
+	"black": termbox.ColorBlack,
+	"red": termbox.ColorRed,
+	"green": termbox.ColorGreen,
+	"yellow": termbox.ColorYellow,
+	"blue": termbox.ColorBlue,
+	"magenta": termbox.ColorMagenta,
+	"cyan": termbox.ColorCyan,
+	"white": termbox.ColorWhite,
+}
+
+var namedAttributes = map[string]termbox.Attribute{
+	"bold": termbox.AttrBold,
+	"underline": termbox.AttrUnderline,
+	"reverse": termbox.AttrReverse,
+}
+
+// The typical RGB values of the basic colors, from black to white.
+var basicColorRgbs = []rgb{
+	rgb{0, 0, 0},
+	rgb{205, 0, 0},
+	rgb{0, 205, 0},
+	rgb{205, 205, 0},
+	rgb{0, 0, 238},
+	rgb{205, 0, 205},
+	rgb{0, 205, 205},
+	rgb{229, 229, 229},
+}
+
+var xtermCubeLevels = []int{0, 95, 135, 175, 215, 255}
+
+func measureRgbDistance(a rgb, b rgb) int {
+	return (a.r-b.r)*(a.r-b.r) + (a.g-b.g)*(a.g-b.g) + (a.b-b.b)*(a.b-b.b)
+}
+
+// Convert an xterm 256 color index to RGB.
+func convertXtermIndexToRgb(index int) rgb {
+	switch {
+	case index < 8:
+		return basicColorRgbs[index]
+	case index < 16:
+		base := basicColorRgbs[index-8]
+		brighten := func(value int) int {
+			return int(math.Min(255, float64(value) + 50))
+		}
+		return rgb{brighten(base.r), brighten(base.g), brighten(base.b)}
+	case index < 232:
+		cubeIndex := index - 16
+		return rgb{
+			xtermCubeLevels[cubeIndex/36],
+			xtermCubeLevels[(cubeIndex/6)%6],
+			xtermCubeLevels[cubeIndex%6],
+		}
+	}
+	level := 8 + (index-232)*10
+	return rgb{level, level, level}
+}
+
+func findNearestXtermIndex(color rgb, candidates []int) int {
+	nearestIndex := candidates[0]
+	nearestDistance := -1
+	for _, index := range candidates {
+		distance := measureRgbDistance(color, convertXtermIndexToRgb(index))
+		if nearestDistance < 0 || distance < nearestDistance {
+			nearestIndex = index
+			nearestDistance = distance
+		}
+	}
+	return nearestIndex
+}
+
+// Returns the termbox color of the xterm 256 color index in the color mode.
+func convertXtermIndexToAttribute(index int, mode ColorMode) termbox.Attribute {
+	if mode == ColorModeBasic {
+		if index >= 8 && index < 16 {
+			index -= 8
+		} else if index >= 16 {
+			index = findNearestXtermIndex(convertXtermIndexToRgb(index), []int{0, 1, 2, 3, 4, 5, 6, 7})
+		}
+	}
+	// In both modes, the termbox color is the index plus one, because 0 is the default color.
+	return termbox.Attribute(index + 1)
+}
+
+func convertRgbToAttribute(color rgb, mode ColorMode) termbox.Attribute {
+	candidates := make([]int, 0)
+	if mode == ColorModeBasic {
+		candidates = []int{0, 1, 2, 3, 4, 5, 6, 7}
+	} else {
+		// The system colors 0-15 are excluded, because terminals customize them.
+		for index := 16; index < 256; index++ {
+			candidates = append(candidates, index)
+		}
+	}
+	return termbox.Attribute(findNearestXtermIndex(color, candidates) + 1)
+}
+
+// Parse a color spec of the config.
+//
+// A spec is a color optionally followed by attributes with "+", for example "red+bold".
+// The color is one of a name such as "red", an xterm 256 color index such as "208" or a hex RGB such as "#ff8800".
+// Colors that the mode can not output are approximated to the nearest one.
+func ParseColor(spec string, mode ColorMode) (termbox.Attribute, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(spec)), "+")
+	var color termbox.Attribute
+	colorPart := parts[0]
+	if namedColor, ok := namedColors[colorPart]; ok {
+		color = namedColor
+	} else if strings.HasPrefix(colorPart, "#") && len(colorPart) == 7 {
+		value, err := strconv.ParseUint(colorPart[1:], 16, 32)
+		if err != nil {
+			return 0, errors.Errorf("The color \"%s\" is invalid.", spec)
+		}
+		color = convertRgbToAttribute(rgb{int(value >> 16), int((value >> 8) & 0xff), int(value & 0xff)}, mode)
+	} else if index, err := strconv.Atoi(colorPart); err == nil && index >= 0 && index < 256 {
+		color = convertXtermIndexToAttribute(index, mode)
+	} else {
+		return 0, errors.Errorf("The color \"%s\" is invalid.", spec)
+	}
+	for _, attributePart := range parts[1:] {
+		attribute, ok := namedAttributes[attributePart]
+		if !ok {
+			return 0, errors.Errorf("The attribute \"%s\" of the color \"%s\" is invalid.", attributePart, spec)
+		}
+		color |= attribute
+	}
+	return color, nil
+}
@@ -0,0 +1,272 @@
+package themes
+
+//
+// The "themes" package defines the glyphs and colors of the screen.
+//
+
+import (
+	"github.com/kjirou/tower-of-go/config"
+	"github.com/nsf/termbox-go"
+	"github.com/pkg/errors"
+	"sort"
+	"unicode/utf8"
+)
+
+const DefaultThemeName = "default"
+
+// The names of tiles that a theme defines.
+var TileNames = []string{"floor", "hero", "ghost", "trail", "breadcrumb", "deadEnd", "wall", "upstairs", "unknown"}
+
+// The names of colors that a theme defines.
+var ColorNames = []string{
+	"text",
+	"background",
+	"border",
+	"accent",
+	"warning",
+	"rank.normal",
+	"rank.good",
+	"rank.best",
+	"heat.1",
+	"heat.2",
+	"heat.3",
+	"heat.4",
+}
+
+var builtinThemes = map[string]*config.ThemeConfig{
+	"default": &config.ThemeConfig{
+		Tiles: map[string]*config.ThemeTileConfig{
+			"floor": &config.ThemeTileConfig{Glyph: ".", Foreground: "white"},
+			"hero": &config.ThemeTileConfig{Glyph: "@", Foreground: "magenta"},
+			// The best run of the past. It is drawn on the background of the tile under it.
+			"ghost": &config.ThemeTileConfig{Glyph: "&", Foreground: "blue"},
+			// The cells that the hero has visited in the floor, when the trail is shown.
+			"trail": &config.ThemeTileConfig{Glyph: ":", Foreground: "cyan"},
+			// A mark that the player has dropped.
+			"breadcrumb": &config.ThemeTileConfig{Glyph: "*", Foreground: "red+bold"},
+			// The visited cells that only lead to dead ends, when they are shaded.
+			"deadEnd": &config.ThemeTileConfig{Glyph: "x", Foreground: "blue"},
+			"wall": &config.ThemeTileConfig{Glyph: "#", Foreground: "yellow"},
+			"upstairs": &config.ThemeTileConfig{Glyph: "<", Foreground: "green"},
+			"unknown": &config.ThemeTileConfig{Glyph: "?", Foreground: "white"},
+		},
+		Colors: map[string]string{
+			"text": "white",
+			"background": "black",
+			"border": "white",
+			"accent": "yellow",
+			"warning": "yellow",
+			"rank.normal": "white",
+			"rank.good": "green",
+			"rank.best": "cyan",
+			// The backgrounds of the heatmaps, from 1 visit to 4 or more visits.
+			"heat.1": "blue",
+			"heat.2": "green",
+			"heat.3": "yellow",
+			"heat.4": "red",
+		},
+	},
+	// The Okabe-Ito palette, which is distinguishable for the common types of color blindness.
+	"colorblind": &config.ThemeConfig{
+		Base: "default",
+		Tiles: map[string]*config.ThemeTileConfig{
+			"floor": &config.ThemeTileConfig{Foreground: "#999999"},
+			"hero": &config.ThemeTileConfig{Foreground: "#e69f00+bold"},
+			"ghost": &config.ThemeTileConfig{Foreground: "#cc79a7"},
+			"trail": &config.ThemeTileConfig{Foreground: "#009e73"},
+			"breadcrumb": &config.ThemeTileConfig{Foreground: "#f0e442+bold"},
+			"deadEnd": &config.ThemeTileConfig{Foreground: "#d55e00"},
+			"wall": &config.ThemeTileConfig{Foreground: "#0072b2"},
+			"upstairs": &config.ThemeTileConfig{Foreground: "#56b4e9+bold"},
+		},
+		Colors: map[string]string{
+			"accent": "#f0e442",
+			"warning": "#d55e00",
+			"rank.good": "#009e73",
+			"rank.best": "#56b4e9+bold",
+			"heat.1": "#56b4e9",
+			"heat.2": "#009e73",
+			"heat.3": "#f0e442",
+			"heat.4": "#d55e00",
+		},
+	},
+	// Only glyphs and text attributes distinguish tiles, and the terminal's own colors are used.
+	"monochrome": &config.ThemeConfig{
+		Base: "default",
+		Tiles: map[string]*config.ThemeTileConfig{
+			"floor": &config.ThemeTileConfig{Foreground: "default", Background: "default"},
+			"hero": &config.ThemeTileConfig{Foreground: "default+bold", Background: "default"},
+			"ghost": &config.ThemeTileConfig{Foreground: "default", Background: "default"},
+			"trail": &config.ThemeTileConfig{Foreground: "default", Background: "default"},
+			"breadcrumb": &config.ThemeTileConfig{Foreground: "default+bold", Background: "default"},
+			"deadEnd": &config.ThemeTileConfig{Foreground: "default", Background: "default"},
+			"wall": &config.ThemeTileConfig{Foreground: "default", Background: "default"},
+			"upstairs": &config.ThemeTileConfig{Foreground: "default+bold+underline", Background: "default"},
+			"unknown": &config.ThemeTileConfig{Foreground: "default", Background: "default"},
+		},
+		Colors: map[string]string{
+			"text": "default",
+			"background": "default",
+			"border": "default",
+			"accent": "default+bold",
+			"warning": "default+reverse",
+			"rank.normal": "default",
+			"rank.good": "default+bold",
+			"rank.best": "default+bold+underline",
+			// The heatmaps are read from the numbers of the visits.
+			"heat.1": "default",
+			"heat.2": "default",
+			"heat.3": "default",
+			"heat.4": "default",
+		},
+	},
+}
+
+type Tile struct {
+	Symbol rune
+	Foreground termbox.Attribute
+	Background termbox.Attribute
+}
+
+type Theme struct {
+	name string
+	tiles map[string]*Tile
+	colors map[string]termbox.Attribute
+	mode ColorMode
+}
+
+func (theme *Theme) GetName() string {
+	return theme.name
+}
+
+// An undefined tile is drawn as the "unknown" tile.
+func (theme *Theme) GetTile(name string) *Tile {
+	if tile, ok := theme.tiles[name]; ok {
+		return tile
+	}
+	return theme.tiles["unknown"]
+}
+
+// An undefined color is the terminal's default color.
+func (theme *Theme) GetColor(name string) termbox.Attribute {
+	return theme.colors[name]
+}
+
+// The value is a color name of the theme such as "rank.good", or a color spec such as "208+bold".
+func (theme *Theme) ResolveColor(value string) (termbox.Attribute, error) {
+	if color, ok := theme.colors[value]; ok {
+		return color, nil
+	}
+	return ParseColor(value, theme.mode)
+}
+
+func GetBuiltinThemeNames() []string {
+	names := make([]string, 0)
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Merge the theme and its ancestors into a single config.
+// Custom themes take precedence over built-in themes of the same name.
+func flattenThemeConfig(
+	name string, customThemes map[string]*config.ThemeConfig, visitedNames map[string]bool) (*config.ThemeConfig, error) {
+	if visitedNames[name] {
+		return nil, errors.Errorf("The theme \"%s\" inherits from itself.", name)
+	}
+	visitedNames[name] = true
+
+	themeConfig, ok := customThemes[name]
+	if !ok {
+		themeConfig, ok = builtinThemes[name]
+	}
+	if !ok {
+		return nil, errors.Errorf("The theme \"%s\" does not exist.", name)
+	}
+
+	flattened := &config.ThemeConfig{
+		Tiles: make(map[string]*config.ThemeTileConfig),
+		Colors: make(map[string]string),
+	}
+	baseName := themeConfig.Base
+	if baseName == "" && name != DefaultThemeName {
+		baseName = DefaultThemeName
+	}
+	if baseName != "" {
+		base, err := flattenThemeConfig(baseName, customThemes, visitedNames)
+		if err != nil {
+			return nil, err
+		}
+		flattened = base
+	}
+
+	for tileName, tileConfig := range themeConfig.Tiles {
+		merged := &config.ThemeTileConfig{}
+		if baseTileConfig, ok := flattened.Tiles[tileName]; ok {
+			*merged = *baseTileConfig
+		}
+		if tileConfig.Glyph != "" {
+			merged.Glyph = tileConfig.Glyph
+		}
+		if tileConfig.Foreground != "" {
+			merged.Foreground = tileConfig.Foreground
+		}
+		if tileConfig.Background != "" {
+			merged.Background = tileConfig.Background
+		}
+		flattened.Tiles[tileName] = merged
+	}
+	for colorName, spec := range themeConfig.Colors {
+		flattened.Colors[colorName] = spec
+	}
+	return flattened, nil
+}
+
+func CreateTheme(name string, customThemes map[string]*config.ThemeConfig, mode ColorMode) (*Theme, error) {
+	if name == "" {
+		name = DefaultThemeName
+	}
+	themeConfig, err := flattenThemeConfig(name, customThemes, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	theme := &Theme{
+		name: name,
+		tiles: make(map[string]*Tile),
+		colors: make(map[string]termbox.Attribute),
+		mode: mode,
+	}
+	for colorName, spec := range themeConfig.Colors {
+		color, err := ParseColor(spec, mode)
+		if err != nil {
+			return nil, errors.Wrapf(err, "The color \"%s\" of the theme \"%s\" is invalid.", colorName, name)
+		}
+		theme.colors[colorName] = color
+	}
+	for tileName, tileConfig := range themeConfig.Tiles {
+		symbol, _ := utf8.DecodeRuneInString(tileConfig.Glyph)
+		if tileConfig.Glyph == "" || symbol == utf8.RuneError {
+			return nil, errors.Errorf("The glyph of the tile \"%s\" of the theme \"%s\" is invalid.", tileName, name)
+		}
+		tile := &Tile{
+			Symbol: symbol,
+			Foreground: theme.colors["text"],
+			Background: theme.colors["background"],
+		}
+		if tileConfig.Foreground != "" {
+			if tile.Foreground, err = ParseColor(tileConfig.Foreground, mode); err != nil {
+				return nil, errors.Wrapf(err, "The tile \"%s\" of the theme \"%s\" is invalid.", tileName, name)
+			}
+		}
+		if tileConfig.Background != "" {
+			if tile.Background, err = ParseColor(tileConfig.Background, mode); err != nil {
+				return nil, errors.Wrapf(err, "The tile \"%s\" of the theme \"%s\" is invalid.", tileName, name)
+			}
+		}
+		theme.tiles[tileName] = tile
+	}
+	return theme, nil
+}
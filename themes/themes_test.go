@@ -0,0 +1,122 @@
+package themes
+
+import (
+	"github.com/kjirou/tower-of-go/config"
+	"github.com/nsf/termbox-go"
+	"strings"
+	"testing"
+)
+
+func TestParseColor_NotTD(t *testing.T) {
+	type testCase struct {
+		spec string
+		mode ColorMode
+		want termbox.Attribute
+	}
+	testCases := []testCase{
+		{spec: "red", mode: ColorModeBasic, want: termbox.ColorRed},
+		{spec: "default", mode: ColorModeBasic, want: termbox.ColorDefault},
+		{spec: "green+bold", mode: ColorModeBasic, want: termbox.ColorGreen | termbox.AttrBold},
+		{spec: "208", mode: ColorMode256, want: termbox.Attribute(209)},
+		{spec: "9", mode: ColorModeBasic, want: termbox.ColorRed},
+		{spec: "#ff8700", mode: ColorMode256, want: termbox.Attribute(209)},
+		{spec: "#0000ee", mode: ColorModeBasic, want: termbox.ColorBlue},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.spec + "を変換できる", func(t *testing.T) {
+			got, err := ParseColor(tc.spec, tc.mode)
+			if err != nil {
+				t.Fatal("エラーを返す")
+			} else if got != tc.want {
+				t.Fatalf("色が違う: %d", got)
+			}
+		})
+	}
+
+	t.Run("不正な指定はエラーを返す", func(t *testing.T) {
+		for _, spec := range []string{"", "purple", "256", "#12345", "red+blink"} {
+			if _, err := ParseColor(spec, ColorMode256); err == nil {
+				t.Fatalf("%sでエラーを返さない", spec)
+			}
+		}
+	})
+}
+
+func TestCreateTheme_NotTD(t *testing.T) {
+	t.Run("全ての組み込みテーマを生成できる", func(t *testing.T) {
+		for _, name := range GetBuiltinThemeNames() {
+			theme, err := CreateTheme(name, nil, ColorModeBasic)
+			if err != nil {
+				t.Fatalf("%sでエラーを返す", name)
+			}
+			for _, tileName := range TileNames {
+				if _, ok := theme.tiles[tileName]; !ok {
+					t.Fatalf("%sに%sのタイルがない", name, tileName)
+				}
+			}
+		}
+	})
+
+	t.Run("独自のテーマは基底テーマの未指定の値を継承する", func(t *testing.T) {
+		customThemes := map[string]*config.ThemeConfig{
+			"mine": &config.ThemeConfig{
+				Base: "monochrome",
+				Tiles: map[string]*config.ThemeTileConfig{
+					"wall": &config.ThemeTileConfig{Glyph: "█"},
+				},
+			},
+		}
+		theme, err := CreateTheme("mine", customThemes, ColorModeBasic)
+		if err != nil {
+			t.Fatal("エラーを返す")
+		}
+		wall := theme.GetTile("wall")
+		if wall.Symbol != '█' {
+			t.Fatal("記号が上書きされていない")
+		} else if wall.Foreground != termbox.ColorDefault {
+			t.Fatal("色が継承されていない")
+		}
+		if theme.GetTile("hero").Symbol != '@' {
+			t.Fatal("他のタイルが継承されていない")
+		}
+	})
+
+	t.Run("自身を継承するテーマはエラーを返す", func(t *testing.T) {
+		customThemes := map[string]*config.ThemeConfig{
+			"a": &config.ThemeConfig{Base: "b"},
+			"b": &config.ThemeConfig{Base: "a"},
+		}
+		_, err := CreateTheme("a", customThemes, ColorModeBasic)
+		if err == nil {
+			t.Fatal("エラーを返さない")
+		} else if !strings.Contains(err.Error(), "inherits from itself") {
+			t.Fatal("意図したエラーメッセージではない")
+		}
+	})
+}
+
+func TestTheme_ResolveColor_NotTD(t *testing.T) {
+	theme, _ := CreateTheme("default", nil, ColorModeBasic)
+
+	t.Run("テーマの色名を解決する", func(t *testing.T) {
+		color, err := theme.ResolveColor("rank.good")
+		if err != nil || color != theme.GetColor("rank.good") {
+			t.Fatal("テーマの色ではない")
+		}
+	})
+
+	t.Run("色名でなければ色の指定として解析する", func(t *testing.T) {
+		color, err := theme.ResolveColor("red+bold")
+		if err != nil || color != termbox.ColorRed | termbox.AttrBold {
+			t.Fatal("指定した色ではない")
+		}
+	})
+
+	t.Run("不正な値はエラーを返す", func(t *testing.T) {
+		_, err := theme.ResolveColor("no-such-color")
+		if err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+}
@@ -12,6 +12,61 @@ const (
 	MazeCellContentUnbreakableWall
 )
 
+type MazeAlgorithm int
+const (
+	// Joins clusters by breaking walls in random order. It makes many short dead ends.
+	MazeAlgorithmClustering MazeAlgorithm = iota
+	// Digs passages depth-first. It makes long winding corridors.
+	MazeAlgorithmDigging
+)
+
+var mazeAlgorithmNames = map[string]MazeAlgorithm{
+	"clustering": MazeAlgorithmClustering,
+	"digging": MazeAlgorithmDigging,
+}
+
+// The names of the algorithms, in the order of MazeAlgorithm.
+var MazeAlgorithmNames = []string{"clustering", "digging"}
+
+// An empty name means the clustering method.
+func ParseMazeAlgorithm(name string) (MazeAlgorithm, error) {
+	if name == "" {
+		return MazeAlgorithmClustering, nil
+	}
+	algorithm, ok := mazeAlgorithmNames[name]
+	if !ok {
+		return MazeAlgorithmClustering, errors.Errorf("The maze algorithm \"%s\" does not exist.", name)
+	}
+	return algorithm, nil
+}
+
+type MazeOptions struct {
+	Algorithm MazeAlgorithm
+	// The rate of the walls between passages that are broken after the generation, from 0 to 1.
+	// Broken walls make loops, 0 makes a perfect maze.
+	LoopDensity float64
+	// The source of randomness, the same one generates the same maze.
+	// If it is nil, a new one is seeded from the global source.
+	Random *rand.Rand
+	// The range of the steps from the upper left passage to the lower right one. Zero means no limit.
+	// Mazes are generated again until one falls inside it.
+	MinSolutionLength int
+	MaxSolutionLength int
+}
+
+// The generation gives up the range of the solution length after it, and returns the closest maze.
+const mazeGenerationMaxAttempts = 100
+
+// Returns how many steps the length is out of the range, 0 means inside.
+func (options *MazeOptions) measureSolutionLengthGap(solutionLength int) int {
+	if options.MinSolutionLength > 0 && solutionLength < options.MinSolutionLength {
+		return options.MinSolutionLength - solutionLength
+	} else if options.MaxSolutionLength > 0 && solutionLength > options.MaxSolutionLength {
+		return solutionLength - options.MaxSolutionLength
+	}
+	return 0
+}
+
 type mazeCell struct {
 	ClusterIndex int
 	Content MazeCellContent
@@ -55,23 +110,9 @@ func generateRawMazeMatrix(rowLength int, columnLength int) ([][]*mazeCell, erro
 	return cells, nil
 }
 
-// Generate a maze with the clustering method.
-//
 // The maze generation algorithm referred to the following article.
 // https://qiita.com/kaityo256/items/b2e504c100f4274deb42
-//
-// For example, if set rowLength=5 and columnLength=7 then a maze of the following size is generated.
-// #######
-// #     #
-// #     #
-// #     #
-// #######
-func GenerateMaze(rowLength int, columnLength int) ([][]*mazeCell, error) {
-	cells, err := generateRawMazeMatrix(rowLength, columnLength)
-	if err != nil {
-		return cells, err
-	}
-
+func digMazeByClustering(cells [][]*mazeCell, random *rand.Rand) {
 	breakableWalls := make([]*mazeCell, 0)
 	for _, row := range cells {
 		for _, cell := range row {
@@ -81,7 +122,7 @@ func GenerateMaze(rowLength int, columnLength int) ([][]*mazeCell, error) {
 		}
 	}
 
-	rand.Shuffle(len(breakableWalls), func (i, j int) {
+	random.Shuffle(len(breakableWalls), func (i, j int) {
 		breakableWalls[i], breakableWalls[j] = breakableWalls[j], breakableWalls[i]
 	})
 
@@ -129,6 +170,138 @@ func GenerateMaze(rowLength int, columnLength int) ([][]*mazeCell, error) {
 			breakableWall.Content = MazeCellContentUnbreakableWall
 		}
 	}
+}
+
+// Dig passages from the upper left corner with the depth-first search.
+func digMazeByDepthFirstSearch(cells [][]*mazeCell, random *rand.Rand) {
+	fourDirections := []struct {
+		deltaY int
+		deltaX int
+	}{
+		{deltaY: -1, deltaX: 0},
+		{deltaY: 0, deltaX: 1},
+		{deltaY: 1, deltaX: 0},
+		{deltaY: 0, deltaX: -1},
+	}
+	visited := make(map[*mazeCell]bool)
+	stack := []*mazeCell{cells[1][1]}
+	visited[cells[1][1]] = true
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		candidates := make([]*mazeCell, 0)
+		for _, direction := range fourDirections {
+			wall := cells[current.Y + direction.deltaY][current.X + direction.deltaX]
+			if wall.Content != MazeCellContentBreakableWall {
+				continue
+			}
+			next := cells[current.Y + direction.deltaY*2][current.X + direction.deltaX*2]
+			if !visited[next] {
+				candidates = append(candidates, wall)
+			}
+		}
+		if len(candidates) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		wall := candidates[random.Intn(len(candidates))]
+		next := cells[wall.Y*2 - current.Y][wall.X*2 - current.X]
+		wall.Content = MazeCellContentEmpty
+		visited[next] = true
+		stack = append(stack, next)
+	}
+
+	for _, row := range cells {
+		for _, cell := range row {
+			if cell.Content == MazeCellContentBreakableWall {
+				cell.Content = MazeCellContentUnbreakableWall
+			}
+		}
+	}
+}
+
+// Break the walls between passages at the rate of loopDensity.
+func breakWallsToMakeLoops(cells [][]*mazeCell, loopDensity float64, random *rand.Rand) {
+	if loopDensity <= 0 {
+		return
+	}
+	rowLength := len(cells)
+	columnLength := len(cells[0])
+	for _, row := range cells {
+		for _, cell := range row {
+			isEdge := cell.Y == 0 || cell.Y == rowLength-1 || cell.X == 0 || cell.X == columnLength-1
+			isBetweenPassages := cell.Y%2 == 0 && cell.X%2 == 1 || cell.Y%2 == 1 && cell.X%2 == 0
+			if !isEdge && isBetweenPassages && cell.Content == MazeCellContentUnbreakableWall &&
+				random.Float64() < loopDensity {
+				cell.Content = MazeCellContentEmpty
+			}
+		}
+	}
+}
+
+// Generate a perfect maze with the clustering method.
+//
+// For example, if set rowLength=5 and columnLength=7 then a maze of the following size is generated.
+// #######
+// #     #
+// #     #
+// #     #
+// #######
+func GenerateMaze(rowLength int, columnLength int) ([][]*mazeCell, error) {
+	return GenerateMazeWithOptions(rowLength, columnLength, &MazeOptions{})
+}
+
+func generateMazeOnce(
+	rowLength int, columnLength int, options *MazeOptions, random *rand.Rand) ([][]*mazeCell, error) {
+	cells, err := generateRawMazeMatrix(rowLength, columnLength)
+	if err != nil {
+		return cells, err
+	}
+
+	switch options.Algorithm {
+	case MazeAlgorithmDigging:
+		digMazeByDepthFirstSearch(cells, random)
+	default:
+		digMazeByClustering(cells, random)
+	}
+	breakWallsToMakeLoops(cells, options.LoopDensity, random)
 
 	return cells, nil
 }
+
+func measureSolutionLengthOfMaze(cells [][]*mazeCell) int {
+	rowLength := len(cells)
+	columnLength := len(cells[0])
+	isPassable := func(position *MatrixPosition) bool {
+		return cells[position.Y][position.X].Content == MazeCellContentEmpty
+	}
+	return FindShortestPathLength(
+		rowLength, columnLength, isPassable,
+		&MatrixPosition{Y: 1, X: 1}, &MatrixPosition{Y: rowLength - 2, X: columnLength - 2})
+}
+
+// If the range of the solution length is not reached in mazeGenerationMaxAttempts, the closest maze is returned.
+// The result is still the same for the same Random.
+func GenerateMazeWithOptions(rowLength int, columnLength int, options *MazeOptions) ([][]*mazeCell, error) {
+	random := options.Random
+	if random == nil {
+		random = rand.New(rand.NewSource(rand.Int63()))
+	}
+	hasSolutionLengthRange := options.MinSolutionLength > 0 || options.MaxSolutionLength > 0
+
+	var closestCells [][]*mazeCell
+	closestGap := -1
+	for attempt := 0; attempt < mazeGenerationMaxAttempts; attempt++ {
+		cells, err := generateMazeOnce(rowLength, columnLength, options, random)
+		if err != nil || !hasSolutionLengthRange {
+			return cells, err
+		}
+		gap := options.measureSolutionLengthGap(measureSolutionLengthOfMaze(cells))
+		if gap == 0 {
+			return cells, nil
+		} else if closestGap == -1 || gap < closestGap {
+			closestCells = cells
+			closestGap = gap
+		}
+	}
+	return closestCells, nil
+}
@@ -256,3 +256,104 @@ func TestGenerateMaze_NotTD(t *testing.T) {
 		}
 	})
 }
+
+func TestGenerateMazeWithOptions_NotTD(t *testing.T) {
+	t.Run("穴掘り法で正しい迷路を生成する", func(t *testing.T) {
+		cells, err := GenerateMazeWithOptions(13, 21, &MazeOptions{Algorithm: MazeAlgorithmDigging})
+		if err != nil {
+			t.Fatal(err)
+		}
+		noBeforeMazeCell := mazeCell{}
+		steppedCells := exploreMaze(cells, cells[1][1], &noBeforeMazeCell, make([]*mazeCell, 0))
+		emptyCellCount := 0
+		for _, row := range cells {
+			for _, cell := range row {
+				if cell.Content == MazeCellContentBreakableWall {
+					t.Fatalf("Y=%d,X=%d は壊せる壁である", cell.Y, cell.X)
+				} else if cell.Content == MazeCellContentEmpty {
+					emptyCellCount++
+				}
+			}
+		}
+		if emptyCellCount != len(steppedCells) {
+			t.Fatal("全ての空セルが結合されていない")
+		}
+	})
+
+	t.Run("ループ密度が1のとき、外周以外の通路間の壁は全て壊れる", func(t *testing.T) {
+		cells, _ := GenerateMazeWithOptions(7, 9, &MazeOptions{LoopDensity: 1})
+		for _, row := range cells {
+			for _, cell := range row {
+				isEdge := cell.Y == 0 || cell.Y == 6 || cell.X == 0 || cell.X == 8
+				isBetweenPassages := cell.Y%2 != cell.X%2
+				if !isEdge && isBetweenPassages && cell.Content != MazeCellContentEmpty {
+					t.Fatalf("Y=%d,X=%d が壊れていない", cell.Y, cell.X)
+				}
+			}
+		}
+	})
+
+	t.Run("同じ乱数の種から同じ迷路を生成する", func(t *testing.T) {
+		for _, algorithm := range []MazeAlgorithm{MazeAlgorithmClustering, MazeAlgorithmDigging} {
+			a, _ := GenerateMazeWithOptions(13, 21, &MazeOptions{
+				Algorithm: algorithm, LoopDensity: 0.2, Random: rand.New(rand.NewSource(1))})
+			b, _ := GenerateMazeWithOptions(13, 21, &MazeOptions{
+				Algorithm: algorithm, LoopDensity: 0.2, Random: rand.New(rand.NewSource(1))})
+			if !reflect.DeepEqual(a, b) {
+				t.Fatal("迷路が違う")
+			}
+		}
+	})
+	t.Run("解の長さの範囲を指定したとき、範囲内の迷路を生成する", func(t *testing.T) {
+		for seed := int64(1); seed <= 20; seed++ {
+			cells, _ := GenerateMazeWithOptions(13, 21, &MazeOptions{
+				MinSolutionLength: 30,
+				MaxSolutionLength: 36,
+				Random: rand.New(rand.NewSource(seed)),
+			})
+			solutionLength := measureSolutionLengthOfMaze(cells)
+			if solutionLength < 30 || solutionLength > 36 {
+				t.Fatalf("Seed=%d の解の長さ %d が範囲外である", seed, solutionLength)
+			}
+		}
+	})
+
+	t.Run("解の長さの範囲を指定しても、同じ乱数の種から同じ迷路を生成する", func(t *testing.T) {
+		options := func() *MazeOptions {
+			return &MazeOptions{MinSolutionLength: 40, Random: rand.New(rand.NewSource(1))}
+		}
+		a, _ := GenerateMazeWithOptions(13, 21, options())
+		b, _ := GenerateMazeWithOptions(13, 21, options())
+		if !reflect.DeepEqual(a, b) {
+			t.Fatal("迷路が違う")
+		}
+	})
+
+	t.Run("範囲内の迷路を生成できないとき、最も近い迷路を返す", func(t *testing.T) {
+		cells, err := GenerateMazeWithOptions(13, 21, &MazeOptions{
+			MaxSolutionLength: 1,
+			Random: rand.New(rand.NewSource(1)),
+		})
+		if err != nil {
+			t.Fatal(err)
+		} else if measureSolutionLengthOfMaze(cells) != 28 {
+			t.Fatal("最短の迷路ではない")
+		}
+	})
+}
+
+func TestParseMazeAlgorithm_NotTD(t *testing.T) {
+	t.Run("空文字列はクラスタリング法になる", func(t *testing.T) {
+		algorithm, err := ParseMazeAlgorithm("")
+		if err != nil || algorithm != MazeAlgorithmClustering {
+			t.Fatal("クラスタリング法ではない")
+		}
+	})
+
+	t.Run("存在しない名前はエラーを返す", func(t *testing.T) {
+		_, err := ParseMazeAlgorithm("unknown")
+		if err == nil {
+			t.Fatal("エラーを返さない")
+		}
+	})
+}
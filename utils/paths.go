@@ -0,0 +1,51 @@
+package utils
+
+// The positions next to a position, in the order of up, right, down and left.
+var FourDirections = []MatrixPosition{
+	MatrixPosition{Y: -1, X: 0},
+	MatrixPosition{Y: 0, X: 1},
+	MatrixPosition{Y: 1, X: 0},
+	MatrixPosition{Y: 0, X: -1},
+}
+
+// Returns the number of steps from the position to every position with the breadth-first search.
+// The positions that can not be reached are -1.
+func MeasureDistances(
+	rowLength int, columnLength int, isPassable func(position *MatrixPosition) bool,
+	from *MatrixPosition) [][]int {
+	distances := make([][]int, rowLength)
+	for y := 0; y < rowLength; y++ {
+		distances[y] = make([]int, columnLength)
+		for x := 0; x < columnLength; x++ {
+			distances[y][x] = -1
+		}
+	}
+	if !from.Validate(rowLength, columnLength) {
+		return distances
+	}
+	distances[from.Y][from.X] = 0
+	queue := []*MatrixPosition{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, direction := range FourDirections {
+			next := &MatrixPosition{Y: current.Y + direction.Y, X: current.X + direction.X}
+			if next.Validate(rowLength, columnLength) && distances[next.Y][next.X] == -1 && isPassable(next) {
+				distances[next.Y][next.X] = distances[current.Y][current.X] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+	return distances
+}
+
+// Returns the number of steps of the shortest path between the positions with the breadth-first search.
+// Returns -1 if the destination can not be reached.
+func FindShortestPathLength(
+	rowLength int, columnLength int, isPassable func(position *MatrixPosition) bool,
+	from *MatrixPosition, to *MatrixPosition) int {
+	if !from.Validate(rowLength, columnLength) || !to.Validate(rowLength, columnLength) {
+		return -1
+	}
+	return MeasureDistances(rowLength, columnLength, isPassable, from)[to.Y][to.X]
+}
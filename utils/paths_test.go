@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestFindShortestPathLength_NotTD(t *testing.T) {
+	// # = 壁
+	lines := []string{
+		"#####",
+		"#...#",
+		"#.#.#",
+		"#.#.#",
+		"#####",
+	}
+	isPassable := func(position *MatrixPosition) bool {
+		return lines[position.Y][position.X] != '#'
+	}
+
+	t.Run("壁を迂回した最短の歩数を返す", func(t *testing.T) {
+		length := FindShortestPathLength(5, 5, isPassable, &MatrixPosition{Y: 3, X: 1}, &MatrixPosition{Y: 3, X: 3})
+		if length != 6 {
+			t.Fatalf("%d 歩ではない", length)
+		}
+	})
+
+	t.Run("同じ位置なら0を返す", func(t *testing.T) {
+		length := FindShortestPathLength(5, 5, isPassable, &MatrixPosition{Y: 1, X: 1}, &MatrixPosition{Y: 1, X: 1})
+		if length != 0 {
+			t.Fatal("0ではない")
+		}
+	})
+
+	t.Run("到達できないなら-1を返す", func(t *testing.T) {
+		length := FindShortestPathLength(5, 5, isPassable, &MatrixPosition{Y: 1, X: 1}, &MatrixPosition{Y: 0, X: 0})
+		if length != -1 {
+			t.Fatal("-1ではない")
+		}
+	})
+}
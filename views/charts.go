@@ -0,0 +1,62 @@
+package views
+
+import (
+	"strings"
+)
+
+// The levels of a sparkline, from the lowest.
+var sparklineRunes = []rune("▁▂▃▄▅▆▇█")
+
+// The partial blocks of a bar, from 1/8 to 7/8 of a cell.
+var partialBarRunes = []rune("▏▎▍▌▋▊▉")
+
+// Returns a line of block characters, the height of each one is a value scaled between the minimum and the maximum.
+// Only the last values are drawn if they do not fit in maxColumnLength. Zero means no limit.
+func FormatSparkline(values []float64, maxColumnLength int) string {
+	if maxColumnLength > 0 && len(values) > maxColumnLength {
+		values = values[len(values)-maxColumnLength:]
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	minValue := values[0]
+	maxValue := values[0]
+	for _, value := range values {
+		if value < minValue {
+			minValue = value
+		}
+		if value > maxValue {
+			maxValue = value
+		}
+	}
+	runes := make([]rune, 0)
+	for _, value := range values {
+		// The values are drawn in the middle if they are all the same.
+		level := len(sparklineRunes) / 2 - 1
+		if maxValue > minValue {
+			level = int((value - minValue) / (maxValue - minValue) * float64(len(sparklineRunes) - 1) + 0.5)
+		}
+		runes = append(runes, sparklineRunes[level])
+	}
+	return string(runes)
+}
+
+// Returns a horizontal bar of block characters, maxColumnLength cells are the maximum value.
+// A positive value has at least the narrowest block, so that it is distinguished from zero.
+func FormatBar(value float64, maxValue float64, maxColumnLength int) string {
+	if value <= 0 || maxValue <= 0 {
+		return ""
+	}
+	if value > maxValue {
+		value = maxValue
+	}
+	eighths := int(value / maxValue * float64(maxColumnLength * 8) + 0.5)
+	if eighths == 0 {
+		eighths = 1
+	}
+	bar := strings.Repeat("█", eighths / 8)
+	if eighths % 8 > 0 {
+		bar += string(partialBarRunes[eighths % 8 - 1])
+	}
+	return bar
+}
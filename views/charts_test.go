@@ -0,0 +1,43 @@
+package views
+
+import (
+	"testing"
+)
+
+func TestFormatSparkline_NotTD(t *testing.T) {
+	t.Run("最小値から最大値までを8段階で表す", func(t *testing.T) {
+		if got := FormatSparkline([]float64{0, 7, 3.5, 1}, 0); got != "▁█▅▂" {
+			t.Fatalf("%s is wrong", got)
+		}
+	})
+
+	t.Run("幅に収まらないときは最後の値だけを表す", func(t *testing.T) {
+		if got := FormatSparkline([]float64{0, 1, 2}, 2); got != "▁█" {
+			t.Fatalf("%s is wrong", got)
+		}
+	})
+
+	t.Run("全て同じ値なら中段で表す", func(t *testing.T) {
+		if got := FormatSparkline([]float64{5, 5}, 0); got != "▄▄" {
+			t.Fatalf("%s is wrong", got)
+		}
+	})
+}
+
+func TestFormatBar_NotTD(t *testing.T) {
+	t.Run("最大値を最大幅として、1/8セル単位で表す", func(t *testing.T) {
+		if got := FormatBar(10, 10, 3); got != "███" {
+			t.Fatalf("%s is wrong", got)
+		} else if got := FormatBar(5, 10, 3); got != "█▌" {
+			t.Fatalf("%s is wrong", got)
+		}
+	})
+
+	t.Run("正の値は最小でも細いブロックで表し、0は空にする", func(t *testing.T) {
+		if got := FormatBar(1, 1000, 3); got != "▏" {
+			t.Fatalf("%s is wrong", got)
+		} else if got := FormatBar(0, 10, 3); got != "" {
+			t.Fatalf("%s is wrong", got)
+		}
+	})
+}
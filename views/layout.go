@@ -0,0 +1,182 @@
+package views
+
+import (
+	"github.com/nsf/termbox-go"
+)
+
+func (screen *Screen) createFrame(content Widget) Widget {
+	return &Box{
+		Title: screen.translator.Translate("title"),
+		Child: &Padding{Top: 1, Right: 1, Left: 1, Child: content},
+	}
+}
+
+func (screen *Screen) createStatusPanel(props *GameSceneProps) Widget {
+	timeText := screen.translator.Translate("status.elapsedTime", props.ElapsedTime)
+	if props.HasTimeLimit {
+		timeText = screen.translator.Translate("status.time", props.RemainingTime)
+	}
+	floorText := screen.translator.Translate("status.floor", props.FloorNumber)
+	if props.GoalFloorNumber > 0 {
+		floorText = screen.translator.Translate("status.floorWithGoal", props.FloorNumber, props.GoalFloorNumber)
+	}
+	children := []Widget{
+		&Label{Text: timeText},
+		&Label{Text: floorText},
+	}
+	if props.GhostFloorNumber > 0 {
+		children = append(children, &Label{
+			Text: screen.translator.Translate("status.ghost", props.GhostFloorNumber),
+			Foreground: screen.theme.GetTile("ghost").Foreground,
+		})
+	}
+	if props.Notice != "" {
+		children = append(children, &Label{Text: props.Notice, Foreground: screen.theme.GetColor("accent")})
+	}
+	if props.IsPaused {
+		children = append(children, &Label{
+			Text: screen.translator.Translate("status.paused"),
+			Foreground: screen.theme.GetColor("accent"),
+		})
+	}
+	return &Stack{Direction: StackDirectionVertical, Children: children}
+}
+
+func (screen *Screen) createHelpPanel(props *GameSceneProps) Widget {
+	children := []Widget{
+		&Label{Text: screen.translator.Translate("help.title")},
+		&StyledText{Spans: []*Span{
+			&Span{Text: "\""},
+			&Span{Text: "s", Foreground: screen.theme.GetColor("accent")},
+			&Span{Text: "\" ... " + screen.translator.Translate("help.start")},
+		}},
+		&StyledText{Spans: []*Span{
+			&Span{Text: screen.translator.Translate("help.arrowKeys"), Foreground: screen.theme.GetColor("accent")},
+			&Span{Text: " " + screen.translator.Translate("help.or") + " \""},
+			&Span{Text: "k,l,j,h", Foreground: screen.theme.GetColor("accent")},
+			&Span{Text: "\" ... " + screen.translator.Translate("help.move")},
+		}},
+		&StyledText{Spans: []*Span{
+			&Span{Text: "Esc", Foreground: screen.theme.GetColor("accent")},
+			&Span{Text: " ... " + screen.translator.Translate("help.back")},
+		}},
+	}
+	children = append(children, &StyledText{Spans: []*Span{
+		&Span{Text: "\""},
+		&Span{Text: "t", Foreground: screen.theme.GetColor("accent")},
+		&Span{Text: "\" ... " + screen.translator.Translate("help.trail")},
+	}}, &StyledText{Spans: []*Span{
+		&Span{Text: "\""},
+		&Span{Text: "m", Foreground: screen.theme.GetColor("accent")},
+		&Span{Text: "\" ... " + screen.translator.Translate("help.breadcrumb")},
+	}})
+	if props.IsRewindable {
+		children = append(children, &StyledText{Spans: []*Span{
+			&Span{Text: "\""},
+			&Span{Text: "u", Foreground: screen.theme.GetColor("accent")},
+			&Span{Text: "\" ... " + screen.translator.Translate("help.rewind")},
+		}})
+	}
+	return &Stack{Direction: StackDirectionVertical, Children: children}
+}
+
+// If maxColumnLength is greater than 0, the description is wrapped to fit within it.
+func (screen *Screen) createDescriptionPanel(props *GameSceneProps, maxColumnLength int) Widget {
+	return &StyledText{
+		Spans: []*Span{&Span{Text: props.Description}},
+		Wraps: maxColumnLength > 0,
+		MaxColumnLength: maxColumnLength,
+	}
+}
+
+func (screen *Screen) createUrlLabel() Widget {
+	return &Align{
+		Horizontal: AlignmentEnd,
+		Child: &Label{
+			Text: "https://github.com/kjirou/tower-of-go",
+			Foreground: screen.theme.GetColor("text") | termbox.AttrUnderline,
+		},
+	}
+}
+
+// The panels are placed on the right side of the field.
+// The help panel is aligned to the bottom of the field.
+func (screen *Screen) createWideLayout(props *GameSceneProps, hasDescription bool) Widget {
+	sidePanels := &Padding{
+		Top: 1,
+		Bottom: 1,
+		Child: &Stack{
+			Direction: StackDirectionVertical,
+			Children: []Widget{screen.createStatusPanel(props), &Spacer{}, screen.createHelpPanel(props)},
+		},
+	}
+	children := []Widget{
+		&Stack{
+			Direction: StackDirectionHorizontal,
+			Gap: 2,
+			Children: []Widget{&Grid{Cells: props.FieldCells}, sidePanels},
+		},
+	}
+	if hasDescription {
+		children = append(children, &Padding{Top: 2, Left: 1, Child: screen.createDescriptionPanel(props, 0)})
+	}
+	children = append(children, &Spacer{}, screen.createUrlLabel())
+	return screen.createFrame(&Stack{Direction: StackDirectionVertical, Children: children})
+}
+
+// The panels are stacked below the field.
+// The description is wrapped to the width of the field.
+func (screen *Screen) createNarrowLayout(props *GameSceneProps, hasHelp bool, hasDescription bool, hasUrl bool) Widget {
+	field := &Grid{Cells: props.FieldCells}
+	_, fieldColumnLength := field.Measure()
+	children := []Widget{
+		field,
+		&Padding{Top: 1, Child: screen.createStatusPanel(props)},
+	}
+	if hasHelp {
+		children = append(children, &Padding{Top: 1, Child: screen.createHelpPanel(props)})
+	}
+	if hasDescription {
+		children = append(children, &Padding{Top: 1, Child: screen.createDescriptionPanel(props, fieldColumnLength)})
+	}
+	if hasUrl {
+		children = append(children, &Spacer{}, screen.createUrlLabel())
+	}
+	return screen.createFrame(&Stack{Direction: StackDirectionVertical, Children: children})
+}
+
+// Returns layouts in order of preference. The last one is the minimum layout.
+func (screen *Screen) createGameLayoutCandidates(props *GameSceneProps) []Widget {
+	return []Widget{
+		screen.createWideLayout(props, true),
+		screen.createWideLayout(props, false),
+		screen.createNarrowLayout(props, true, true, true),
+		screen.createNarrowLayout(props, true, false, true),
+		screen.createNarrowLayout(props, false, true, false),
+		screen.createNarrowLayout(props, false, false, true),
+		screen.createNarrowLayout(props, false, false, false),
+	}
+}
+
+func (screen *Screen) createTooSmallLayout(
+	rowLength int, columnLength int, minRowLength int, minColumnLength int) Widget {
+	lines := []string{
+		screen.translator.Translate("screen.tooSmall"),
+		screen.translator.Translate("screen.need", minColumnLength, minRowLength),
+		screen.translator.Translate("screen.have", columnLength, rowLength),
+	}
+	children := make([]Widget, 0)
+	for _, line := range lines {
+		children = append(children, &Align{
+			Horizontal: AlignmentCenter,
+			Child: &Label{Text: line, Foreground: screen.theme.GetColor("warning")},
+		})
+	}
+	return &Box{
+		Child: &Align{
+			Vertical: AlignmentCenter,
+			Horizontal: AlignmentCenter,
+			Child: &Stack{Direction: StackDirectionVertical, Children: children},
+		},
+	}
+}
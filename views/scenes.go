@@ -0,0 +1,215 @@
+package views
+
+import (
+	"github.com/mattn/go-runewidth"
+)
+
+// The menu is placed in the center of the screen.
+func (screen *Screen) createMenuLayout(props *MenuSceneProps) Widget {
+	items := make([]*StyledText, 0)
+	for _, item := range props.Items {
+		items = append(items, &StyledText{Spans: []*Span{&Span{Text: item}}})
+	}
+	return screen.createFrame(&Align{
+		Vertical: AlignmentCenter,
+		Horizontal: AlignmentCenter,
+		Child: &Stack{
+			Direction: StackDirectionVertical,
+			Gap: 1,
+			Children: []Widget{
+				&Align{
+					Horizontal: AlignmentCenter,
+					Child: &Label{Text: props.Heading, Foreground: screen.theme.GetColor("accent")},
+				},
+				&Align{
+					Horizontal: AlignmentCenter,
+					Child: &List{Items: items, HasCursor: true, CursorIndex: props.CursorIndex},
+				},
+				&Label{Text: props.Hint},
+			},
+		},
+	})
+}
+
+// A table of two columns, the values are aligned.
+func (screen *Screen) createTable(rowProps []*ResultsRowProps) Widget {
+	labelColumnLength := 0
+	for _, row := range rowProps {
+		if width := runewidth.StringWidth(row.Label); width > labelColumnLength {
+			labelColumnLength = width
+		}
+	}
+	rows := make([]Widget, 0)
+	for _, row := range rowProps {
+		rows = append(rows, &StyledText{Spans: []*Span{
+			&Span{Text: runewidth.FillRight(row.Label, labelColumnLength) + "  "},
+			&Span{Text: row.Value, Foreground: screen.theme.GetColor("accent")},
+		}})
+	}
+	return &Stack{Direction: StackDirectionVertical, Children: rows}
+}
+
+// The rows are placed as a table of two columns in the center of the screen.
+func (screen *Screen) createResultsLayout(props *ResultsSceneProps) Widget {
+	children := []Widget{
+		&Align{
+			Horizontal: AlignmentCenter,
+			Child: &Label{
+				Text: screen.translator.Translate("results.heading"),
+				Foreground: screen.theme.GetColor("accent"),
+			},
+		},
+		&Align{
+			Horizontal: AlignmentCenter,
+			Child: &Label{Text: props.RankMessage, Foreground: props.RankMessageForeground},
+		},
+	}
+	if props.Notice != "" {
+		children = append(children, &Align{
+			Horizontal: AlignmentCenter,
+			Child: &Label{Text: props.Notice, Foreground: screen.theme.GetColor("accent")},
+		})
+	}
+	children = append(children,
+		&Align{Horizontal: AlignmentCenter, Child: screen.createTable(props.Rows)},
+		&Label{Text: props.Hint},
+	)
+	return screen.createFrame(&Align{
+		Vertical: AlignmentCenter,
+		Horizontal: AlignmentCenter,
+		Child: &Stack{Direction: StackDirectionVertical, Gap: 1, Children: children},
+	})
+}
+
+// The heading and the table are placed in the center of the screen, like the results.
+func (screen *Screen) createStatsLayout(props *StatsSceneProps) Widget {
+	return screen.createFrame(&Align{
+		Vertical: AlignmentCenter,
+		Horizontal: AlignmentCenter,
+		Child: &Stack{
+			Direction: StackDirectionVertical,
+			Gap: 1,
+			Children: []Widget{
+				&Align{
+					Horizontal: AlignmentCenter,
+					Child: &Label{Text: props.Heading, Foreground: screen.theme.GetColor("accent")},
+				},
+				&Align{Horizontal: AlignmentCenter, Child: screen.createTable(props.Rows)},
+				&Label{Text: props.Hint},
+			},
+		},
+	})
+}
+
+// The legend and the table are placed on the right side of the field, or below it if the screen is narrow.
+func (screen *Screen) createHeatmapLayout(props *HeatmapSceneProps, isWide bool) Widget {
+	direction := StackDirectionVertical
+	if isWide {
+		direction = StackDirectionHorizontal
+	}
+	return screen.createFrame(&Align{
+		Vertical: AlignmentCenter,
+		Horizontal: AlignmentCenter,
+		Child: &Stack{
+			Direction: StackDirectionVertical,
+			Gap: 1,
+			Children: []Widget{
+				&Label{Text: props.Heading, Foreground: screen.theme.GetColor("accent")},
+				&Stack{
+					Direction: direction,
+					Gap: 2,
+					Children: []Widget{
+						&Grid{Cells: props.FieldCells},
+						&Stack{
+							Direction: StackDirectionVertical,
+							Gap: 1,
+							Children: []Widget{
+								&Stack{
+									Direction: StackDirectionHorizontal,
+									Gap: 1,
+									Children: []Widget{
+										&Label{Text: screen.translator.Translate("heatmap.legend")},
+										&Grid{Cells: [][]*ScreenCellProps{props.LegendCells}},
+									},
+								},
+								screen.createTable(props.Rows),
+							},
+						},
+					},
+				},
+				&Label{Text: props.Hint},
+			},
+		},
+	})
+}
+
+// Returns layouts in order of preference. The last one is the minimum layout.
+func (screen *Screen) createHeatmapLayoutCandidates(props *HeatmapSceneProps) []Widget {
+	return []Widget{
+		screen.createHeatmapLayout(props, true),
+		screen.createHeatmapLayout(props, false),
+	}
+}
+
+func (screen *Screen) createEditorPalettePanel(props *EditorSceneProps) Widget {
+	items := make([]*StyledText, 0)
+	for _, item := range props.PaletteItems {
+		items = append(items, &StyledText{Spans: []*Span{
+			&Span{Text: string(item.Tile.Symbol), Foreground: item.Tile.Foreground},
+			&Span{Text: " " + item.Label},
+		}})
+	}
+	noticeForeground := screen.theme.GetColor("accent")
+	if props.NoticeIsError {
+		noticeForeground = screen.theme.GetColor("warning")
+	}
+	return &Stack{
+		Direction: StackDirectionVertical,
+		Children: []Widget{
+			&Label{Text: screen.translator.Translate("editor.palette")},
+			&List{Items: items, HasCursor: true, CursorIndex: props.SelectedTileIndex},
+			&Spacer{RowLength: 1},
+			&StyledText{
+				Spans: []*Span{&Span{Text: props.Notice, Foreground: noticeForeground}},
+				Wraps: true,
+				MaxColumnLength: 30,
+			},
+		},
+	}
+}
+
+// The palette is placed on the right side of the field, or below it if the screen is narrow.
+// The hint is wrapped to the width of them.
+func (screen *Screen) createEditorLayout(props *EditorSceneProps, isWide bool) Widget {
+	direction := StackDirectionVertical
+	if isWide {
+		direction = StackDirectionHorizontal
+	}
+	body := &Stack{
+		Direction: direction,
+		Gap: 2,
+		Children: []Widget{&Grid{Cells: props.FieldCells}, screen.createEditorPalettePanel(props)},
+	}
+	_, bodyColumnLength := body.Measure()
+	return screen.createFrame(&Stack{
+		Direction: StackDirectionVertical,
+		Gap: 1,
+		Children: []Widget{
+			&Label{Text: props.Heading, Foreground: screen.theme.GetColor("accent")},
+			body,
+			&StyledText{
+				Spans: []*Span{&Span{Text: props.Hint}},
+				Wraps: true,
+				MaxColumnLength: bodyColumnLength,
+			},
+		},
+	})
+}
+
+// Returns layouts in order of preference. The last one is the minimum layout.
+func (screen *Screen) createEditorLayoutCandidates(props *EditorSceneProps) []Widget {
+	return []Widget{
+		screen.createEditorLayout(props, true),
+		screen.createEditorLayout(props, false),
+	}
+}
@@ -0,0 +1,145 @@
+package views
+
+import (
+	"github.com/mattn/go-runewidth"
+	"github.com/nsf/termbox-go"
+	"unicode"
+)
+
+// A character of a styled text.
+type styledRune struct {
+	symbol rune
+	foreground termbox.Attribute
+}
+
+// Returns the number of cells that the character occupies on the terminal.
+// Control characters and combining characters are regarded as one cell, because a cell can not be empty.
+func measureRuneWidth(symbol rune) int {
+	width := runewidth.RuneWidth(symbol)
+	if width < 1 {
+		return 1
+	}
+	return width
+}
+
+func measureStyledRunesWidth(runes []styledRune) int {
+	width := 0
+	for _, character := range runes {
+		width += measureRuneWidth(character.symbol)
+	}
+	return width
+}
+
+func convertSpansToStyledRunes(spans []*Span) []styledRune {
+	runes := make([]styledRune, 0)
+	for _, span := range spans {
+		for _, symbol := range span.Text {
+			runes = append(runes, styledRune{symbol: symbol, foreground: span.Foreground})
+		}
+	}
+	return runes
+}
+
+// Split characters into tokens which are not broken by the word wrapping.
+// A token is a sequence of non-space characters, a space or a wide character.
+// Wide characters are tokens by themselves, because CJK texts do not separate words with spaces.
+func tokenizeStyledRunes(runes []styledRune) [][]styledRune {
+	tokens := make([][]styledRune, 0)
+	token := make([]styledRune, 0)
+	flush := func() {
+		if len(token) > 0 {
+			tokens = append(tokens, token)
+			token = make([]styledRune, 0)
+		}
+	}
+	for _, character := range runes {
+		if unicode.IsSpace(character.symbol) || measureRuneWidth(character.symbol) > 1 {
+			flush()
+			tokens = append(tokens, []styledRune{character})
+		} else {
+			token = append(token, character)
+		}
+	}
+	flush()
+	return tokens
+}
+
+func trimTrailingSpaces(line []styledRune) []styledRune {
+	for len(line) > 0 && unicode.IsSpace(line[len(line)-1].symbol) {
+		line = line[:len(line)-1]
+	}
+	return line
+}
+
+// Split characters into lines by line breaks.
+// If maxColumnLength is greater than 0, lines are also wrapped to fit within it.
+func wrapStyledRunes(runes []styledRune, maxColumnLength int) [][]styledRune {
+	paragraphs := make([][]styledRune, 0)
+	paragraph := make([]styledRune, 0)
+	for _, character := range runes {
+		if character.symbol == '\n' {
+			paragraphs = append(paragraphs, paragraph)
+			paragraph = make([]styledRune, 0)
+		} else {
+			paragraph = append(paragraph, character)
+		}
+	}
+	paragraphs = append(paragraphs, paragraph)
+	if maxColumnLength <= 0 {
+		return paragraphs
+	}
+
+	lines := make([][]styledRune, 0)
+	for _, paragraph := range paragraphs {
+		line := make([]styledRune, 0)
+		lineWidth := 0
+		isWrappedLine := false
+		breakLine := func() {
+			lines = append(lines, trimTrailingSpaces(line))
+			line = make([]styledRune, 0)
+			lineWidth = 0
+			isWrappedLine = true
+		}
+		for _, token := range tokenizeStyledRunes(paragraph) {
+			tokenWidth := measureStyledRunesWidth(token)
+			isSpace := unicode.IsSpace(token[0].symbol)
+			if isSpace && lineWidth == 0 && isWrappedLine {
+				continue
+			}
+			if lineWidth + tokenWidth > maxColumnLength && lineWidth > 0 {
+				breakLine()
+				if isSpace {
+					continue
+				}
+			}
+			// A word longer than the line is broken at any character.
+			for _, character := range token {
+				runeWidth := measureRuneWidth(character.symbol)
+				if lineWidth + runeWidth > maxColumnLength && lineWidth > 0 {
+					breakLine()
+				}
+				line = append(line, character)
+				lineWidth += runeWidth
+			}
+		}
+		lines = append(lines, trimTrailingSpaces(line))
+	}
+	return lines
+}
+
+// Characters without a foreground are drawn in the theme's text color.
+func drawStyledRunes(canvas *Canvas, y int, x int, runes []styledRune) {
+	theme := canvas.screen.theme
+	for _, character := range runes {
+		fg := character.foreground
+		if fg == 0 {
+			fg = theme.GetColor("text")
+		}
+		canvas.SetCell(y, x, &ScreenCellProps{
+			Symbol: character.symbol,
+			Foreground: fg,
+			Background: theme.GetColor("background"),
+		})
+		x += measureRuneWidth(character.symbol)
+	}
+}
@@ -0,0 +1,105 @@
+package views
+
+import (
+	"github.com/kjirou/tower-of-go/i18n"
+	"github.com/kjirou/tower-of-go/themes"
+	"github.com/nsf/termbox-go"
+	"testing"
+)
+
+func convertLinesToStrings(lines [][]styledRune) []string {
+	texts := make([]string, 0)
+	for _, line := range lines {
+		text := ""
+		for _, character := range line {
+			text += string(character.symbol)
+		}
+		texts = append(texts, text)
+	}
+	return texts
+}
+
+func TestWrapStyledRunes_NotTD(t *testing.T) {
+	type testCase struct {
+		name string
+		text string
+		maxColumnLength int
+		want []string
+	}
+	testCases := []testCase{
+		{
+			name: "最大幅が0のときは改行でのみ分割する",
+			text: "foo bar\nbaz",
+			maxColumnLength: 0,
+			want: []string{"foo bar", "baz"},
+		},
+		{
+			name: "単語の区切りで折り返し、行末と行頭の空白は除く",
+			text: "foo bar baz",
+			maxColumnLength: 7,
+			want: []string{"foo bar", "baz"},
+		},
+		{
+			name: "最大幅より長い単語は文字の区切りで折り返す",
+			text: "abcdefg",
+			maxColumnLength: 3,
+			want: []string{"abc", "def", "g"},
+		},
+		{
+			name: "全角文字は2セル幅として扱い、文字の区切りで折り返す",
+			text: "あいうえお",
+			maxColumnLength: 5,
+			want: []string{"あい", "うえ", "お"},
+		},
+		{
+			name: "改行と折り返しを併用できる",
+			text: "foo bar\nbaz",
+			maxColumnLength: 3,
+			want: []string{"foo", "bar", "baz"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			runes := convertSpansToStyledRunes([]*Span{&Span{Text: tc.text}})
+			got := convertLinesToStrings(wrapStyledRunes(runes, tc.maxColumnLength))
+			if len(got) != len(tc.want) {
+				t.Fatalf("行数が違う: %q", got)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("%d行目が違う: %q", i, got)
+				}
+			}
+		})
+	}
+}
+
+func TestScreen_setCell_NotTD(t *testing.T) {
+	theme, _ := themes.CreateTheme("default", nil, themes.ColorModeBasic)
+
+	t.Run("全角文字の右半分は出力されない", func(t *testing.T) {
+		screen := CreateScreen(1, 3, i18n.CreateTranslator("en"), theme)
+		screen.setCell(0, 0, &ScreenCellProps{Symbol: 'あ'})
+		symbols := ""
+		screen.ForEachCells(func(_ int, _ int, symbol rune, _ termbox.Attribute, _ termbox.Attribute) {
+			symbols += string(symbol)
+		})
+		if symbols != "あ_" {
+			t.Fatalf("出力が違う: %q", symbols)
+		}
+	})
+
+	t.Run("全角文字の右半分を上書きしたとき、左半分は空白になる", func(t *testing.T) {
+		screen := CreateScreen(1, 3, i18n.CreateTranslator("en"), theme)
+		screen.setCell(0, 0, &ScreenCellProps{Symbol: 'あ'})
+		screen.setCell(0, 1, &ScreenCellProps{Symbol: 'a'})
+		symbols := ""
+		screen.ForEachCells(func(_ int, _ int, symbol rune, _ termbox.Attribute, _ termbox.Attribute) {
+			symbols += string(symbol)
+		})
+		if symbols != " a_" {
+			t.Fatalf("出力が違う: %q", symbols)
+		}
+	})
+}
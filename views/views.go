@@ -5,8 +5,8 @@ package views
 //
 
 import (
-	"fmt"
-	"github.com/kjirou/tower-of-go/utils"
+	"github.com/kjirou/tower-of-go/i18n"
+	"github.com/kjirou/tower-of-go/themes"
 	"github.com/nsf/termbox-go"
 )
 
@@ -20,62 +20,116 @@ type screenCell struct {
 	symbol          rune
 	foreground termbox.Attribute
 	background termbox.Attribute
+	// The right half of a wide character. It is not output, because the terminal fills it.
+	isContinuation bool
 }
 
 func (screenCell *screenCell) render(props *ScreenCellProps) {
 	screenCell.symbol = props.Symbol
 	screenCell.foreground = props.Foreground
 	screenCell.background = props.Background
+	screenCell.isContinuation = false
 }
 
-type screenText struct {
-	Position *utils.MatrixPosition
-	// ASCII only. Line breaks are not allowed.
-	Text string
-	Foreground termbox.Attribute
+type GameSceneProps struct {
+	FieldCells [][]*ScreenCellProps
+	FloorNumber int
+	// Zero means that there is no goal.
+	GoalFloorNumber int
+	// The floor where the ghost is. Zero means that there is no ghost.
+	GhostFloorNumber int
+	// The mode is for practice, the moves can be rewound.
+	IsRewindable bool
+	IsPaused bool
+	// Empty means that there is no notice, such as an unlocked achievement.
+	Notice string
+	// If the game has no time limit, the elapsed time is displayed instead of the remaining time.
+	HasTimeLimit bool
+	RemainingTime float64
+	ElapsedTime float64
+	Description string
 }
 
-func createSequentialScreenTexts(position *utils.MatrixPosition, parts []*screenText) []*screenText {
-	texts := make([]*screenText, 0)
-	deltaX := 0
-	for _, part := range parts {
-		pos := &utils.MatrixPosition{
-			Y: position.GetY(),
-			X: position.GetX() + deltaX,
-		}
-		deltaX += len(part.Text)
-		fg := termbox.ColorWhite
-		if part.Foreground != 0 {
-			fg = part.Foreground
-		}
-		text := screenText{
-			Position: pos,
-			Text: part.Text,
-			Foreground: fg,
-		}
-		texts = append(texts, &text)
-	}
-	return texts
+type MenuSceneProps struct {
+	Heading string
+	Items []string
+	CursorIndex int
+	// Descriptions of the available keys.
+	Hint string
 }
 
-type ScreenProps struct {
+type ResultsRowProps struct {
+	Label string
+	Value string
+}
+
+type ResultsSceneProps struct {
+	Rows []*ResultsRowProps
+	RankMessage string
+	RankMessageForeground termbox.Attribute
+	// Empty means that there is no notice, such as an unlocked achievement.
+	Notice string
+	Hint string
+}
+
+// The rows are shown in the same table as the results.
+type StatsSceneProps struct {
+	Heading string
+	Rows []*ResultsRowProps
+	Hint string
+}
+
+type HeatmapSceneProps struct {
+	Heading string
+	// The visited cells are colored by the number of the visits.
 	FieldCells [][]*ScreenCellProps
-	FloorNumber int
-	LankMessage string
-	LankMessageForeground termbox.Attribute
-	RemainingTime float64
+	// The cells that explain the colors, from the fewest visits.
+	LegendCells []*ScreenCellProps
+	Rows []*ResultsRowProps
+	Hint string
+}
+
+type EditorPaletteItemProps struct {
+	Tile *ScreenCellProps
+	Label string
+}
+
+type EditorSceneProps struct {
+	// The cursor is already drawn in the cells.
+	FieldCells [][]*ScreenCellProps
+	Heading string
+	PaletteItems []*EditorPaletteItemProps
+	SelectedTileIndex int
+	// Empty means that there is no notice.
+	Notice string
+	NoticeIsError bool
+	Hint string
+}
+
+// Only the props of the current scene are set.
+type ScreenProps struct {
+	Game *GameSceneProps
+	Menu *MenuSceneProps
+	Results *ResultsSceneProps
+	Stats *StatsSceneProps
+	Heatmap *HeatmapSceneProps
+	Editor *EditorSceneProps
 }
 
 type Screen struct {
 	matrix [][]*screenCell
-	staticTexts []*screenText
+	translator *i18n.Translator
+	theme *themes.Theme
 }
 
-func (screen *Screen) measureRowLength() int {
+func (screen *Screen) MeasureRowLength() int {
 	return len(screen.matrix)
 }
 
-func (screen *Screen) measureColumnLength() int {
+func (screen *Screen) MeasureColumnLength() int {
+	if len(screen.matrix) == 0 {
+		return 0
+	}
 	return len(screen.matrix[0])
 }
 
@@ -88,87 +142,84 @@ func (screen *Screen) ForEachCells(
 		background termbox.Attribute)) {
 	for y, row := range screen.matrix {
 		for x, cell := range row {
+			if cell.isContinuation {
+				continue
+			}
 			callback(y, x, cell.symbol, cell.foreground, cell.background)
 		}
 	}
 }
 
+// Update a cell, keeping wide characters consistent.
+// A wide character that is partly overwritten is replaced with a blank.
+func (screen *Screen) setCell(y int, x int, props *ScreenCellProps) {
+	row := screen.matrix[y]
+	cell := row[x]
+	if cell.isContinuation && x > 0 {
+		row[x-1].symbol = ' '
+	}
+	if x + 1 < len(row) && row[x+1].isContinuation {
+		row[x+1].render(&ScreenCellProps{Symbol: ' ', Foreground: cell.foreground, Background: cell.background})
+	}
+	cell.render(props)
+	if measureRuneWidth(props.Symbol) > 1 && x + 1 < len(row) {
+		if x + 2 < len(row) && row[x+2].isContinuation {
+			row[x+2].render(&ScreenCellProps{Symbol: ' ', Foreground: props.Foreground, Background: props.Background})
+		}
+		row[x+1].render(&ScreenCellProps{Symbol: ' ', Foreground: props.Foreground, Background: props.Background})
+		row[x+1].isContinuation = true
+	}
+}
+
+func (screen *Screen) createCanvas() *Canvas {
+	return &Canvas{
+		screen: screen,
+		top: 0,
+		left: 0,
+		rowLength: screen.MeasureRowLength(),
+		columnLength: screen.MeasureColumnLength(),
+	}
+}
+
 func (screen *Screen) Render(props *ScreenProps) {
-	rowLength := screen.measureRowLength()
-	columnLength := screen.measureColumnLength()
+	rowLength := screen.MeasureRowLength()
+	columnLength := screen.MeasureColumnLength()
 
 	// Pad elements with blanks.
-	// Set borders.
 	for y := 0; y < rowLength; y++ {
 		for x := 0; x < columnLength; x++ {
-			isTopOrBottomEdge := y == 0 || y == rowLength-1
-			isLeftOrRightEdge := x == 0 || x == columnLength-1
-			symbol := ' '
-			switch {
-			case isTopOrBottomEdge && isLeftOrRightEdge:
-				symbol = '+'
-			case isTopOrBottomEdge && !isLeftOrRightEdge:
-				symbol = '-'
-			case !isTopOrBottomEdge && isLeftOrRightEdge:
-				symbol = '|'
-			}
-			cell := screen.matrix[y][x]
-			cell.render(&ScreenCellProps{
-				Symbol: symbol,
-				Foreground: termbox.ColorWhite,
-				Background: termbox.ColorBlack,
+			screen.matrix[y][x].render(&ScreenCellProps{
+				Symbol: ' ',
+				Foreground: screen.theme.GetColor("text"),
+				Background: screen.theme.GetColor("background"),
 			})
 		}
 	}
 
-	// Place the field.
-	fieldPosition := &utils.MatrixPosition{Y: 2, X: 2}
-	for y, rowProps := range props.FieldCells {
-		for x, cellProps := range rowProps {
-			cell := screen.matrix[y + fieldPosition.GetY()][x + fieldPosition.GetX()]
-			cell.render(cellProps)
-		}
-	}
-
-	// Prepare texts.
-	texts := make([]*screenText, 0)
-	texts = append(texts, screen.staticTexts...)
-	remainingTimeText := fmt.Sprintf("%4.1f", props.RemainingTime)
-	timeText := &screenText{
-		Position: &utils.MatrixPosition{Y: 3, X: 25},
-		Text: fmt.Sprintf("Time : %s", remainingTimeText),
-		Foreground: termbox.ColorWhite,
+	candidates := make([]Widget, 0)
+	switch {
+	case props.Game != nil:
+		candidates = screen.createGameLayoutCandidates(props.Game)
+	case props.Menu != nil:
+		candidates = append(candidates, screen.createMenuLayout(props.Menu))
+	case props.Results != nil:
+		candidates = append(candidates, screen.createResultsLayout(props.Results))
+	case props.Stats != nil:
+		candidates = append(candidates, screen.createStatsLayout(props.Stats))
+	case props.Heatmap != nil:
+		candidates = screen.createHeatmapLayoutCandidates(props.Heatmap)
+	case props.Editor != nil:
+		candidates = screen.createEditorLayoutCandidates(props.Editor)
 	}
-	texts = append(texts, timeText)
-	floorNumberText := &screenText{
-		Position: &utils.MatrixPosition{Y: 4, X: 25},
-		Text: fmt.Sprintf("Floor: %2d", props.FloorNumber),
-		Foreground: termbox.ColorWhite,
-	}
-	texts = append(texts, floorNumberText)
-	if props.LankMessage != "" {
-		lankText := &screenText{
-			Position: &utils.MatrixPosition{Y: 5, X: 27},
-			Text: props.LankMessage,
-			Foreground: props.LankMessageForeground,
-		}
-		texts = append(texts, lankText)
-	}
-
-	// Place texts.
-	for _, textInstance := range texts {
-		for deltaX, character := range textInstance.Text {
-			cell := screen.matrix[textInstance.Position.GetY()][textInstance.Position.GetX() + deltaX]
-			cell.render(&ScreenCellProps{
-				Symbol: character,
-				Foreground: textInstance.Foreground,
-				Background: termbox.ColorBlack,
-			})
-		}
+	root := chooseFittingWidget(rowLength, columnLength, candidates)
+	if root == nil {
+		minRowLength, minColumnLength := candidates[len(candidates)-1].Measure()
+		root = screen.createTooSmallLayout(rowLength, columnLength, minRowLength, minColumnLength)
 	}
+	root.Draw(screen.createCanvas())
 }
 
-func CreateScreen(rowLength int, columnLength int) *Screen {
+func CreateScreen(rowLength int, columnLength int, translator *i18n.Translator, theme *themes.Theme) *Screen {
 	matrix := make([][]*screenCell, rowLength)
 	for y := 0; y < rowLength; y++ {
 		row := make([]*screenCell, columnLength)
@@ -176,72 +227,17 @@ func CreateScreen(rowLength int, columnLength int) *Screen {
 			cell := &screenCell{}
 			cell.render(&ScreenCellProps{
 				Symbol:          '_',
-				Foreground: termbox.ColorWhite,
-				Background: termbox.ColorBlack,
+				Foreground: theme.GetColor("text"),
+				Background: theme.GetColor("background"),
 			})
 			row[x] = cell
 		}
 		matrix[y] = row
 	}
 
-	staticTexts := make([]*screenText, 0)
-
-	titleText := &screenText{
-		Position: &utils.MatrixPosition{Y: 0, X: 2},
-		Text: "[ A Tower of Go ]",
-		Foreground: termbox.ColorWhite,
-	}
-	staticTexts = append(staticTexts, titleText)
-
-	urlText := &screenText{
-		Position: &utils.MatrixPosition{Y: 22, X: 41},
-		Text: "https://github.com/kjirou/tower-of-go",
-		Foreground: termbox.ColorWhite | termbox.AttrUnderline,
-	}
-	staticTexts = append(staticTexts, urlText)
-
-	operationTitleText := &screenText{
-		Position: &utils.MatrixPosition{Y: 11, X: 25},
-		Text: "[ Operations ]",
-		Foreground: termbox.ColorWhite,
-	}
-	staticTexts = append(staticTexts, operationTitleText)
-
-	var sKeyHelpTextParts = make([]*screenText, 0)
-	sKeyHelpTextParts = append(sKeyHelpTextParts, &screenText{Text: "\""})
-	sKeyHelpTextParts = append(sKeyHelpTextParts, &screenText{Text: "s", Foreground: termbox.ColorYellow})
-	sKeyHelpTextParts = append(sKeyHelpTextParts, &screenText{Text: "\" ... Start or restart a new game."})
-	sKeyHelpTexts := createSequentialScreenTexts(&utils.MatrixPosition{Y: 12, X: 25}, sKeyHelpTextParts)
-	staticTexts = append(staticTexts, sKeyHelpTexts...)
-
-	var moveKeysHelpTextParts = make([]*screenText, 0)
-	moveKeysHelpTextParts =
-		append(moveKeysHelpTextParts, &screenText{Text: "Arrow keys", Foreground: termbox.ColorYellow})
-	moveKeysHelpTextParts = append(moveKeysHelpTextParts, &screenText{Text: " or \""})
-	moveKeysHelpTextParts =
-		append(moveKeysHelpTextParts, &screenText{Text: "k,l,j,h", Foreground: termbox.ColorYellow})
-	moveKeysHelpTextParts = append(moveKeysHelpTextParts, &screenText{Text: "\" ... Move the player."})
-	staticTexts = append(
-		staticTexts,
-		createSequentialScreenTexts(&utils.MatrixPosition{Y: 13, X: 25}, moveKeysHelpTextParts)...
-	)
-
-	description1Text := &screenText{
-		Position: &utils.MatrixPosition{Y: 17, X: 3},
-		Text: "Move the player in the upper left to reach the stairs in the lower right.",
-		Foreground: termbox.ColorWhite,
-	}
-	staticTexts = append(staticTexts, description1Text)
-
-	description2Text := &screenText{
-		Position: &utils.MatrixPosition{Y: 18, X: 3},
-		Text: "The score is the number of floors that can be reached within 30 seconds.",
-		Foreground: termbox.ColorWhite,
-	}
-	staticTexts = append(staticTexts, description2Text)
-
 	return &Screen{
 		matrix: matrix,
-		staticTexts: staticTexts,
+		translator: translator,
+		theme: theme,
 	}
 }
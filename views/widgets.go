@@ -0,0 +1,446 @@
+package views
+
+//
+// Widgets are the building blocks of a screen.
+// A screen is drawn by composing widgets into a tree and drawing its root on a canvas that covers the screen.
+// Each widget reports its preferred size, and containers divide their area among their children based on it.
+//
+
+import (
+	"github.com/mattn/go-runewidth"
+	"github.com/nsf/termbox-go"
+)
+
+type Widget interface {
+	// Returns the preferred size. Widgets have to draw themselves in any size of area.
+	Measure() (rowLength int, columnLength int)
+	Draw(canvas *Canvas)
+}
+
+// A rectangular area of the screen. Cells outside the area are not drawn.
+type Canvas struct {
+	screen *Screen
+	top int
+	left int
+	rowLength int
+	columnLength int
+}
+
+func (canvas *Canvas) GetRowLength() int {
+	return canvas.rowLength
+}
+
+func (canvas *Canvas) GetColumnLength() int {
+	return canvas.columnLength
+}
+
+// A wide character occupies two cells. It is replaced with a blank if the right half is out of the canvas.
+func (canvas *Canvas) SetCell(y int, x int, props *ScreenCellProps) {
+	if y < 0 || y >= canvas.rowLength || x < 0 || x >= canvas.columnLength {
+		return
+	}
+	if measureRuneWidth(props.Symbol) > 1 && x + 1 >= canvas.columnLength {
+		props = &ScreenCellProps{
+			Symbol: ' ',
+			Foreground: props.Foreground,
+			Background: props.Background,
+		}
+	}
+	canvas.screen.setCell(canvas.top + y, canvas.left + x, props)
+}
+
+// Returns an area relative to this canvas. The area is clipped to this canvas.
+func (canvas *Canvas) Sub(y int, x int, rowLength int, columnLength int) *Canvas {
+	top := canvas.top + y
+	left := canvas.left + x
+	bottom := top + rowLength
+	right := left + columnLength
+	if top < canvas.top {
+		top = canvas.top
+	}
+	if left < canvas.left {
+		left = canvas.left
+	}
+	if bottom > canvas.top + canvas.rowLength {
+		bottom = canvas.top + canvas.rowLength
+	}
+	if right > canvas.left + canvas.columnLength {
+		right = canvas.left + canvas.columnLength
+	}
+	if bottom < top {
+		bottom = top
+	}
+	if right < left {
+		right = left
+	}
+	return &Canvas{
+		screen: canvas.screen,
+		top: top,
+		left: left,
+		rowLength: bottom - top,
+		columnLength: right - left,
+	}
+}
+
+// A part of a text which has its own color. A zero foreground means the theme's text color.
+type Span struct {
+	Text string
+	Foreground termbox.Attribute
+}
+
+// A text composed of colored spans. Line breaks are allowed.
+// If it wraps, lines are wrapped to fit within MaxColumnLength or the given area, whichever is narrower.
+type StyledText struct {
+	Spans []*Span
+	Wraps bool
+	MaxColumnLength int
+}
+
+func (styledText *StyledText) measureLines(maxColumnLength int) [][]styledRune {
+	if !styledText.Wraps {
+		maxColumnLength = 0
+	}
+	return wrapStyledRunes(convertSpansToStyledRunes(styledText.Spans), maxColumnLength)
+}
+
+func (styledText *StyledText) Measure() (int, int) {
+	lines := styledText.measureLines(styledText.MaxColumnLength)
+	columnLength := 0
+	for _, line := range lines {
+		if lineWidth := measureStyledRunesWidth(line); lineWidth > columnLength {
+			columnLength = lineWidth
+		}
+	}
+	return len(lines), columnLength
+}
+
+func (styledText *StyledText) Draw(canvas *Canvas) {
+	maxColumnLength := canvas.GetColumnLength()
+	if styledText.MaxColumnLength > 0 && styledText.MaxColumnLength < maxColumnLength {
+		maxColumnLength = styledText.MaxColumnLength
+	}
+	for y, line := range styledText.measureLines(maxColumnLength) {
+		drawStyledRunes(canvas, y, 0, line)
+	}
+}
+
+// A text of a single color. Line breaks are allowed.
+type Label struct {
+	Text string
+	Foreground termbox.Attribute
+}
+
+func (label *Label) toStyledText() *StyledText {
+	return &StyledText{Spans: []*Span{&Span{Text: label.Text, Foreground: label.Foreground}}}
+}
+
+func (label *Label) Measure() (int, int) {
+	return label.toStyledText().Measure()
+}
+
+func (label *Label) Draw(canvas *Canvas) {
+	label.toStyledText().Draw(canvas)
+}
+
+// A border around the child with the title on the top border.
+type Box struct {
+	Title string
+	Child Widget
+}
+
+func (box *Box) Measure() (int, int) {
+	rowLength, columnLength := 0, 0
+	if box.Child != nil {
+		rowLength, columnLength = box.Child.Measure()
+	}
+	if titleColumnLength := runewidth.StringWidth(box.Title) + 3; titleColumnLength > columnLength {
+		columnLength = titleColumnLength
+	}
+	return rowLength + 2, columnLength + 2
+}
+
+func (box *Box) Draw(canvas *Canvas) {
+	rowLength := canvas.GetRowLength()
+	columnLength := canvas.GetColumnLength()
+	for y := 0; y < rowLength; y++ {
+		for x := 0; x < columnLength; x++ {
+			isTopOrBottomEdge := y == 0 || y == rowLength-1
+			isLeftOrRightEdge := x == 0 || x == columnLength-1
+			symbol := ' '
+			switch {
+			case isTopOrBottomEdge && isLeftOrRightEdge:
+				symbol = '+'
+			case isTopOrBottomEdge && !isLeftOrRightEdge:
+				symbol = '-'
+			case !isTopOrBottomEdge && isLeftOrRightEdge:
+				symbol = '|'
+			default:
+				continue
+			}
+			canvas.SetCell(y, x, &ScreenCellProps{
+				Symbol: symbol,
+				Foreground: canvas.screen.theme.GetColor("border"),
+				Background: canvas.screen.theme.GetColor("background"),
+			})
+		}
+	}
+	if box.Title != "" {
+		// Keep the corner, therefore the title is cut off at the end of the top border.
+		drawStyledRunes(
+			canvas.Sub(0, 0, 1, columnLength-1), 0, 2, convertSpansToStyledRunes([]*Span{&Span{Text: box.Title}}))
+	}
+	if box.Child != nil {
+		box.Child.Draw(canvas.Sub(1, 1, rowLength-2, columnLength-2))
+	}
+}
+
+// Blank space around the child.
+type Padding struct {
+	Top int
+	Right int
+	Bottom int
+	Left int
+	Child Widget
+}
+
+func (padding *Padding) Measure() (int, int) {
+	rowLength, columnLength := padding.Child.Measure()
+	return rowLength + padding.Top + padding.Bottom, columnLength + padding.Left + padding.Right
+}
+
+func (padding *Padding) Draw(canvas *Canvas) {
+	padding.Child.Draw(canvas.Sub(
+		padding.Top,
+		padding.Left,
+		canvas.GetRowLength() - padding.Top - padding.Bottom,
+		canvas.GetColumnLength() - padding.Left - padding.Right,
+	))
+}
+
+// A matrix of cells, for example the field.
+// If any cell has a wide character, all cells are two columns wide to keep the matrix square.
+type Grid struct {
+	Cells [][]*ScreenCellProps
+}
+
+func (grid *Grid) measureCellColumnLength() int {
+	cellColumnLength := 1
+	for _, rowProps := range grid.Cells {
+		for _, cellProps := range rowProps {
+			if width := measureRuneWidth(cellProps.Symbol); width > cellColumnLength {
+				cellColumnLength = width
+			}
+		}
+	}
+	return cellColumnLength
+}
+
+func (grid *Grid) Measure() (int, int) {
+	if len(grid.Cells) == 0 {
+		return 0, 0
+	}
+	return len(grid.Cells), len(grid.Cells[0]) * grid.measureCellColumnLength()
+}
+
+func (grid *Grid) Draw(canvas *Canvas) {
+	cellColumnLength := grid.measureCellColumnLength()
+	for y, rowProps := range grid.Cells {
+		for x, cellProps := range rowProps {
+			canvas.SetCell(y, x * cellColumnLength, cellProps)
+			for deltaX := measureRuneWidth(cellProps.Symbol); deltaX < cellColumnLength; deltaX++ {
+				canvas.SetCell(y, x * cellColumnLength + deltaX, &ScreenCellProps{
+					Symbol: ' ',
+					Foreground: cellProps.Foreground,
+					Background: cellProps.Background,
+				})
+			}
+		}
+	}
+}
+
+// Vertically arranged items. If it has a cursor, the item at the cursor is marked.
+type List struct {
+	Items []*StyledText
+	HasCursor bool
+	CursorIndex int
+}
+
+const listCursorMarker = "> "
+
+func (list *List) Measure() (int, int) {
+	columnLength := 0
+	for _, item := range list.Items {
+		_, itemColumnLength := item.Measure()
+		if itemColumnLength > columnLength {
+			columnLength = itemColumnLength
+		}
+	}
+	if list.HasCursor {
+		columnLength += runewidth.StringWidth(listCursorMarker)
+	}
+	return len(list.Items), columnLength
+}
+
+func (list *List) Draw(canvas *Canvas) {
+	for index, item := range list.Items {
+		spans := item.Spans
+		if list.HasCursor {
+			marker := &Span{Text: "  "}
+			if index == list.CursorIndex {
+				marker = &Span{Text: listCursorMarker, Foreground: canvas.screen.theme.GetColor("accent")}
+			}
+			spans = append([]*Span{marker}, spans...)
+		}
+		drawStyledRunes(canvas, index, 0, convertSpansToStyledRunes(spans))
+	}
+}
+
+type StackDirection int
+const (
+	StackDirectionVertical StackDirection = iota
+	StackDirectionHorizontal
+)
+
+// Children arranged in a line.
+// Each child gets its preferred size along the direction, and the rest is divided among spacers.
+// Each child gets the full size across the direction.
+type Stack struct {
+	Direction StackDirection
+	Gap int
+	Children []Widget
+}
+
+func (stack *Stack) Measure() (int, int) {
+	mainLength, crossLength := 0, 0
+	for index, child := range stack.Children {
+		childRowLength, childColumnLength := child.Measure()
+		childMainLength, childCrossLength := childRowLength, childColumnLength
+		if stack.Direction == StackDirectionHorizontal {
+			childMainLength, childCrossLength = childColumnLength, childRowLength
+		}
+		if index > 0 {
+			mainLength += stack.Gap
+		}
+		mainLength += childMainLength
+		if childCrossLength > crossLength {
+			crossLength = childCrossLength
+		}
+	}
+	if stack.Direction == StackDirectionHorizontal {
+		return crossLength, mainLength
+	}
+	return mainLength, crossLength
+}
+
+func (stack *Stack) Draw(canvas *Canvas) {
+	givenMainLength := canvas.GetRowLength()
+	if stack.Direction == StackDirectionHorizontal {
+		givenMainLength = canvas.GetColumnLength()
+	}
+
+	mainLengths := make([]int, len(stack.Children))
+	spacerCount := 0
+	restLength := givenMainLength
+	for index, child := range stack.Children {
+		childRowLength, childColumnLength := child.Measure()
+		mainLengths[index] = childRowLength
+		if stack.Direction == StackDirectionHorizontal {
+			mainLengths[index] = childColumnLength
+		}
+		restLength -= mainLengths[index]
+		if index > 0 {
+			restLength -= stack.Gap
+		}
+		if _, ok := child.(*Spacer); ok {
+			spacerCount++
+		}
+	}
+	if restLength > 0 && spacerCount > 0 {
+		for index, child := range stack.Children {
+			if _, ok := child.(*Spacer); ok {
+				share := restLength / spacerCount
+				mainLengths[index] += share
+				restLength -= share
+				spacerCount--
+			}
+		}
+	}
+
+	offset := 0
+	for index, child := range stack.Children {
+		if index > 0 {
+			offset += stack.Gap
+		}
+		if stack.Direction == StackDirectionHorizontal {
+			child.Draw(canvas.Sub(0, offset, canvas.GetRowLength(), mainLengths[index]))
+		} else {
+			child.Draw(canvas.Sub(offset, 0, mainLengths[index], canvas.GetColumnLength()))
+		}
+		offset += mainLengths[index]
+	}
+}
+
+// Blank space in a stack. It expands to fill the rest of the stack.
+type Spacer struct {
+	RowLength int
+	ColumnLength int
+}
+
+func (spacer *Spacer) Measure() (int, int) {
+	return spacer.RowLength, spacer.ColumnLength
+}
+
+func (spacer *Spacer) Draw(canvas *Canvas) {
+}
+
+type Alignment int
+const (
+	AlignmentStart Alignment = iota
+	AlignmentCenter
+	AlignmentEnd
+)
+
+// Places the child with its preferred size at the aligned position of the given area.
+type Align struct {
+	Vertical Alignment
+	Horizontal Alignment
+	Child Widget
+}
+
+func (align *Align) Measure() (int, int) {
+	return align.Child.Measure()
+}
+
+func alignOffset(alignment Alignment, givenLength int, length int) int {
+	switch alignment {
+	case AlignmentCenter:
+		return (givenLength - length) / 2
+	case AlignmentEnd:
+		return givenLength - length
+	}
+	return 0
+}
+
+func (align *Align) Draw(canvas *Canvas) {
+	rowLength, columnLength := align.Child.Measure()
+	y := alignOffset(align.Vertical, canvas.GetRowLength(), rowLength)
+	x := alignOffset(align.Horizontal, canvas.GetColumnLength(), columnLength)
+	if y < 0 {
+		y = 0
+	}
+	if x < 0 {
+		x = 0
+	}
+	align.Child.Draw(canvas.Sub(y, x, rowLength, columnLength))
+}
+
+// Returns the first candidate which fits in the given size, or nil if nothing fits.
+func chooseFittingWidget(rowLength int, columnLength int, candidates []Widget) Widget {
+	for _, candidate := range candidates {
+		candidateRowLength, candidateColumnLength := candidate.Measure()
+		if candidateRowLength <= rowLength && candidateColumnLength <= columnLength {
+			return candidate
+		}
+	}
+	return nil
+}